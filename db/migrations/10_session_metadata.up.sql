@@ -0,0 +1,8 @@
+-- Add device metadata to sessions for Vistara Backend
+-- This lets users see where they are logged in
+ALTER TABLE sessions
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip_address VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN last_used_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE sessions SET last_used_at = created_at;
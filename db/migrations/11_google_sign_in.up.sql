@@ -0,0 +1,2 @@
+-- Link users to their Google account by the stable "sub" claim of the ID token
+ALTER TABLE users ADD COLUMN google_id VARCHAR UNIQUE;
@@ -0,0 +1,18 @@
+-- Email verification and password reset for Vistara Backend
+-- Tokens are stored as SHA-256 hashes and can be used once before they expire
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Google only hands out verified emails
+UPDATE users SET email_verified_at = created_at WHERE google_id IS NOT NULL;
+
+CREATE TABLE user_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR NOT NULL,
+    token_hash VARCHAR NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens(user_id, purpose) WHERE used_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_premium_expiry;
+DROP TABLE IF EXISTS subscription_orders;
+DROP TABLE IF EXISTS subscription_plans;
@@ -0,0 +1,36 @@
+-- Premium subscriptions for Vistara Backend
+-- Plans are sold through Midtrans Snap, each paid order extends users.expired_at by the plan duration
+CREATE TABLE subscription_plans (
+    id UUID PRIMARY KEY,
+    code VARCHAR NOT NULL UNIQUE,
+    name VARCHAR NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price BIGINT NOT NULL CHECK (price > 0),
+    duration_days INT NOT NULL CHECK (duration_days > 0),
+    is_active BOOL NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE subscription_orders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    plan_id UUID NOT NULL REFERENCES subscription_plans(id),
+    amount BIGINT NOT NULL,
+    duration_days INT NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending_payment',
+    payment_url TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP,
+    period_start TIMESTAMP,
+    period_end TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_subscription_orders_user ON subscription_orders(user_id, created_at DESC);
+CREATE INDEX idx_users_premium_expiry ON users(expired_at) WHERE is_premium;
+
+INSERT INTO subscription_plans (id, code, name, description, price, duration_days) VALUES
+    ('0190a1b2-0000-7000-8000-000000000001', 'premium-monthly', 'Premium Monthly', 'Unlimited smart planner, Nusalingo and historical stories for 30 days', 49000, 30),
+    ('0190a1b2-0000-7000-8000-000000000002', 'premium-yearly', 'Premium Yearly', 'Unlimited smart planner, Nusalingo and historical stories for 365 days', 499000, 365);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tourist_attractions_owner;
+DROP INDEX IF EXISTS idx_locals_owner;
+ALTER TABLE tourist_attractions DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE locals DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,11 @@
+-- Role-based access control for Vistara Backend
+-- Merchants own the listings they create, admins can manage every listing and user role
+ALTER TABLE users
+    ADD COLUMN role VARCHAR NOT NULL DEFAULT 'tourist'
+    CHECK (role IN ('tourist', 'merchant', 'tour_guide', 'admin'));
+
+ALTER TABLE locals ADD COLUMN owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE tourist_attractions ADD COLUMN owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_locals_owner ON locals(owner_id);
+CREATE INDEX idx_tourist_attractions_owner ON tourist_attractions(owner_id);
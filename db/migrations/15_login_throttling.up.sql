@@ -0,0 +1,26 @@
+-- Login brute-force protection for Vistara Backend
+-- Attempts are counted per email and per IP address, lockouts are kept as an audit trail
+CREATE TABLE login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR NOT NULL,
+    ip_address VARCHAR NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_attempts_email ON login_attempts(email, created_at);
+CREATE INDEX idx_login_attempts_ip ON login_attempts(ip_address, created_at);
+
+CREATE TABLE login_lockouts (
+    id UUID PRIMARY KEY,
+    scope VARCHAR NOT NULL CHECK (scope IN ('account', 'ip')),
+    subject VARCHAR NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    failed_attempts INT NOT NULL,
+    locked_until TIMESTAMP NOT NULL,
+    ip_address VARCHAR NOT NULL,
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_lockouts_subject ON login_lockouts(scope, subject, locked_until);
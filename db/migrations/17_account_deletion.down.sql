@@ -0,0 +1,9 @@
+-- subscription_orders.user_id stays nullable, orders of deleted accounts can't be linked back to a user
+ALTER TABLE subscription_orders DROP CONSTRAINT IF EXISTS subscription_orders_user_id_fkey;
+ALTER TABLE subscription_orders ADD CONSTRAINT subscription_orders_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE tourguide_bookings DROP CONSTRAINT IF EXISTS tourguide_bookings_user_id_fkey;
+ALTER TABLE tourguide_bookings ADD CONSTRAINT tourguide_bookings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- Self-service account deletion for Vistara Backend
+-- Reviews, bookings and subscription orders are kept for accounting when a user is deleted, only the link to the user is cleared
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE tourguide_bookings DROP CONSTRAINT IF EXISTS tourguide_bookings_user_id_fkey;
+ALTER TABLE tourguide_bookings ADD CONSTRAINT tourguide_bookings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE subscription_orders ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE subscription_orders DROP CONSTRAINT IF EXISTS subscription_orders_user_id_fkey;
+ALTER TABLE subscription_orders ADD CONSTRAINT subscription_orders_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
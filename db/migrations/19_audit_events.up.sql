@@ -0,0 +1,32 @@
+-- Security audit log for Vistara Backend
+-- Every event stores the hash of the previous one, so editing or removing a row breaks the chain.
+-- The table is append-only, updates, deletes and truncates are rejected by triggers.
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP NOT NULL,
+    actor_type VARCHAR NOT NULL,
+    actor_id VARCHAR NOT NULL DEFAULT '',
+    action VARCHAR NOT NULL,
+    target_type VARCHAR NOT NULL,
+    target_id VARCHAR NOT NULL,
+    changes JSON NOT NULL,
+    ip_address VARCHAR NOT NULL DEFAULT '',
+    request_id VARCHAR NOT NULL DEFAULT '',
+    prev_hash VARCHAR NOT NULL,
+    hash VARCHAR NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, id DESC);
+CREATE INDEX idx_audit_events_target ON audit_events(target_type, target_id, id DESC);
+CREATE INDEX idx_audit_events_action ON audit_events(action, id DESC);
+
+CREATE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+CREATE TRIGGER audit_events_no_truncate BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_reviews_local;
+DROP INDEX IF EXISTS idx_tourist_attractions_price;
+DROP INDEX IF EXISTS idx_tourist_attractions_name;
+DROP INDEX IF EXISTS idx_tourist_attractions_created_at;
+DROP INDEX IF EXISTS idx_locals_name;
+DROP INDEX IF EXISTS idx_locals_created_at;
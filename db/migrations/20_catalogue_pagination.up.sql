@@ -0,0 +1,11 @@
+-- Keyset pagination for the catalogue lists
+-- Each sortable column is indexed together with id, the tie breaker of every cursor
+CREATE INDEX idx_locals_created_at ON locals(created_at, id);
+CREATE INDEX idx_locals_name ON locals(name, id);
+
+CREATE INDEX idx_tourist_attractions_created_at ON tourist_attractions(created_at, id);
+CREATE INDEX idx_tourist_attractions_name ON tourist_attractions(name, id);
+CREATE INDEX idx_tourist_attractions_price ON tourist_attractions(price, id);
+
+-- Ratings are averaged per listing when sorting by rating
+CREATE INDEX idx_reviews_local ON reviews(local_id);
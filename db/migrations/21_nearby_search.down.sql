@@ -0,0 +1,4 @@
+ALTER TABLE tourist_attractions DROP CONSTRAINT IF EXISTS chk_tourist_attractions_coordinates;
+ALTER TABLE locals DROP CONSTRAINT IF EXISTS chk_locals_coordinates;
+DROP INDEX IF EXISTS idx_tourist_attractions_coordinates;
+DROP INDEX IF EXISTS idx_locals_coordinates;
@@ -0,0 +1,11 @@
+-- Nearby search for the catalogue
+-- A bounding box on these indexes narrows the rows before the exact haversine distance is computed
+CREATE INDEX idx_locals_coordinates ON locals(latitude, longitude);
+CREATE INDEX idx_tourist_attractions_coordinates ON tourist_attractions(latitude, longitude);
+
+ALTER TABLE locals
+    ADD CONSTRAINT chk_locals_coordinates
+    CHECK (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180);
+ALTER TABLE tourist_attractions
+    ADD CONSTRAINT chk_tourist_attractions_coordinates
+    CHECK (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180);
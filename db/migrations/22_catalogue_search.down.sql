@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_tourist_attractions_name_trgm;
+DROP INDEX IF EXISTS idx_locals_name_trgm;
+DROP INDEX IF EXISTS idx_tourist_attractions_search;
+DROP INDEX IF EXISTS idx_locals_search;
+ALTER TABLE tourist_attractions DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE locals DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS vistara_unaccent(TEXT);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS vistara_search;
+DROP EXTENSION IF EXISTS pg_trgm;
+DROP EXTENSION IF EXISTS unaccent;
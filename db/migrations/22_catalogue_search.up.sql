@@ -0,0 +1,33 @@
+-- Full-text and typo tolerant search over the catalogue
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Indonesian stemming on unaccented words
+CREATE TEXT SEARCH CONFIGURATION vistara_search (COPY = pg_catalog.indonesian);
+ALTER TEXT SEARCH CONFIGURATION vistara_search
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, indonesian_stem;
+
+-- unaccent() is only STABLE because its dictionary could change, pinning the dictionary lets names be indexed
+CREATE FUNCTION vistara_unaccent(TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Names weigh most, then where the listing is, then the description
+ALTER TABLE locals ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('vistara_search', name), 'A') ||
+    setweight(to_tsvector('vistara_search', label || ' ' || city || ' ' || province), 'B') ||
+    setweight(to_tsvector('vistara_search', description), 'C')
+) STORED;
+
+ALTER TABLE tourist_attractions ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('vistara_search', name), 'A') ||
+    setweight(to_tsvector('vistara_search', city || ' ' || province), 'B') ||
+    setweight(to_tsvector('vistara_search', description), 'C')
+) STORED;
+
+CREATE INDEX idx_locals_search ON locals USING GIN (search_vector);
+CREATE INDEX idx_tourist_attractions_search ON tourist_attractions USING GIN (search_vector);
+
+-- Trigram indexes catch misspelt names the stemmer can't match
+CREATE INDEX idx_locals_name_trgm ON locals USING GIN (vistara_unaccent(LOWER(name)) gin_trgm_ops);
+CREATE INDEX idx_tourist_attractions_name_trgm ON tourist_attractions USING GIN (vistara_unaccent(LOWER(name)) gin_trgm_ops);
@@ -0,0 +1,9 @@
+DROP FUNCTION IF EXISTS local_is_open(UUID, VARCHAR, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS local_intervals_on(UUID, DATE);
+DROP TABLE IF EXISTS public_holidays;
+DROP TABLE IF EXISTS local_opening_exceptions;
+DROP TABLE IF EXISTS local_opening_hours;
+ALTER TABLE locals
+    ALTER COLUMN opened_time DROP DEFAULT,
+    DROP COLUMN IF EXISTS closed_on_public_holidays,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,79 @@
+-- Structured opening hours for local businesses, replacing the free-text opened_time
+-- Times are local to the business, its time zone follows its province (WIB, WITA or WIT)
+ALTER TABLE locals
+    ADD COLUMN timezone VARCHAR NOT NULL DEFAULT 'Asia/Jakarta'
+    CHECK (timezone IN ('Asia/Jakarta', 'Asia/Makassar', 'Asia/Jayapura')),
+    ADD COLUMN closed_on_public_holidays BOOLEAN NOT NULL DEFAULT FALSE,
+    ALTER COLUMN opened_time SET DEFAULT '';
+
+UPDATE locals SET timezone = CASE
+    WHEN province ~* '(papua|maluku|moluccas)' THEN 'Asia/Jayapura'
+    WHEN province ~* '(bali|nusa tenggara|sulawesi|celebes|gorontalo)'
+        OR (province ~* 'kalimantan' AND province ~* '(selatan|timur|utara|south|east|north)') THEN 'Asia/Makassar'
+    ELSE 'Asia/Jakarta'
+END;
+
+-- Weekly intervals, weekday 0 is Sunday. closes_at before opens_at runs past midnight, 24:00 closes at midnight.
+CREATE TABLE local_opening_hours (
+    id BIGSERIAL PRIMARY KEY,
+    local_id UUID NOT NULL REFERENCES locals(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    CHECK (opens_at <> closes_at)
+);
+
+CREATE INDEX idx_local_opening_hours_local ON local_opening_hours(local_id, weekday);
+
+-- Date specific hours replacing the weekly ones, a row without times closes the whole day
+CREATE TABLE local_opening_exceptions (
+    id BIGSERIAL PRIMARY KEY,
+    local_id UUID NOT NULL REFERENCES locals(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    opens_at TIME,
+    closes_at TIME,
+    note VARCHAR NOT NULL DEFAULT '',
+    CHECK ((opens_at IS NULL) = (closes_at IS NULL)),
+    CHECK (opens_at IS NULL OR opens_at <> closes_at)
+);
+
+CREATE INDEX idx_local_opening_exceptions_local ON local_opening_exceptions(local_id, date);
+
+-- National public holidays, businesses with closed_on_public_holidays stay closed on them
+CREATE TABLE public_holidays (
+    date DATE PRIMARY KEY,
+    name VARCHAR NOT NULL
+);
+
+-- Intervals a business follows on a date: its exceptions if it has any for that date, none on a public
+-- holiday it closes for, otherwise its weekly hours
+CREATE FUNCTION local_intervals_on(p_local_id UUID, p_date DATE)
+RETURNS TABLE (opens_at TIME, closes_at TIME)
+LANGUAGE sql STABLE AS $$
+    SELECT e.opens_at, e.closes_at
+    FROM local_opening_exceptions e
+    WHERE e.local_id = p_local_id AND e.date = p_date AND e.opens_at IS NOT NULL
+    UNION ALL
+    SELECT h.opens_at, h.closes_at
+    FROM local_opening_hours h
+    WHERE h.local_id = p_local_id AND h.weekday = EXTRACT(DOW FROM p_date)
+        AND NOT EXISTS (SELECT 1 FROM local_opening_exceptions e WHERE e.local_id = p_local_id AND e.date = p_date)
+        AND NOT EXISTS (
+            SELECT 1 FROM public_holidays ph, locals l
+            WHERE ph.date = p_date AND l.id = p_local_id AND l.closed_on_public_holidays
+        )
+$$;
+
+-- Whether a business is open at an instant, including overnight intervals started the day before
+CREATE FUNCTION local_is_open(p_local_id UUID, p_timezone VARCHAR, p_at TIMESTAMPTZ)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM local_intervals_on(p_local_id, CAST(p_at AT TIME ZONE p_timezone AS DATE)) i
+        WHERE i.opens_at <= CAST(p_at AT TIME ZONE p_timezone AS TIME)
+            AND (CAST(p_at AT TIME ZONE p_timezone AS TIME) < i.closes_at OR i.closes_at < i.opens_at)
+    ) OR EXISTS (
+        SELECT 1 FROM local_intervals_on(p_local_id, CAST(p_at AT TIME ZONE p_timezone AS DATE) - 1) i
+        WHERE i.closes_at < i.opens_at AND CAST(p_at AT TIME ZONE p_timezone AS TIME) < i.closes_at
+    )
+$$;
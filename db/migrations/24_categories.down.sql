@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tourist_attraction_categories;
+DROP TABLE IF EXISTS local_categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,55 @@
+-- Managed category taxonomy for local businesses and tourist attractions
+-- Categories form a tree, for example culinary > warung > padang. Names are keyed by locale and
+-- always include the default locale "id".
+CREATE TABLE categories (
+    id UUID PRIMARY KEY,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    slug VARCHAR NOT NULL UNIQUE,
+    names JSONB NOT NULL CHECK (jsonb_typeof(names) = 'object' AND names ? 'id'),
+    icon VARCHAR NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id, position);
+
+CREATE TABLE local_categories (
+    local_id UUID NOT NULL REFERENCES locals(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (local_id, category_id)
+);
+
+CREATE INDEX idx_local_categories_category ON local_categories(category_id);
+
+CREATE TABLE tourist_attraction_categories (
+    tourist_attraction_id UUID NOT NULL REFERENCES tourist_attractions(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (tourist_attraction_id, category_id)
+);
+
+CREATE INDEX idx_tourist_attraction_categories_category ON tourist_attraction_categories(category_id);
+
+-- Starting taxonomy, admins manage the rest
+INSERT INTO categories (id, parent_id, slug, names, icon, position) VALUES
+    (gen_random_uuid(), NULL, 'culinary', '{"id": "Kuliner", "en": "Culinary"}', 'utensils', 0),
+    (gen_random_uuid(), NULL, 'handicraft', '{"id": "Kerajinan", "en": "Handicraft"}', 'scissors', 1),
+    (gen_random_uuid(), NULL, 'nature', '{"id": "Wisata Alam", "en": "Nature"}', 'mountain', 2),
+    (gen_random_uuid(), NULL, 'culture', '{"id": "Budaya", "en": "Culture"}', 'landmark', 3);
+
+INSERT INTO categories (id, parent_id, slug, names, icon, position)
+SELECT gen_random_uuid(), p.id, c.slug, CAST(c.names AS JSONB), c.icon, c.position
+FROM (VALUES
+    ('culinary', 'warung', '{"id": "Warung", "en": "Warung"}', 'store', 0),
+    ('culinary', 'cafe', '{"id": "Kafe", "en": "Cafe"}', 'coffee', 1),
+    ('handicraft', 'batik', '{"id": "Batik", "en": "Batik"}', 'shirt', 0),
+    ('handicraft', 'woodcarving', '{"id": "Ukiran Kayu", "en": "Woodcarving"}', 'hammer', 1),
+    ('nature', 'beach', '{"id": "Pantai", "en": "Beach"}', 'umbrella-beach', 0),
+    ('culture', 'temple', '{"id": "Candi dan Pura", "en": "Temple"}', 'place-of-worship', 0)
+) AS c(parent_slug, slug, names, icon, position)
+JOIN categories p ON p.slug = c.parent_slug;
+
+INSERT INTO categories (id, parent_id, slug, names, icon, position)
+SELECT gen_random_uuid(), p.id, 'padang', '{"id": "Masakan Padang", "en": "Padang"}', 'bowl-rice', 0
+FROM categories p WHERE p.slug = 'warung';
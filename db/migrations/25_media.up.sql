@@ -0,0 +1,24 @@
+-- Photo galleries of local businesses and tourist attractions
+-- Every photo belongs to exactly one listing and is stored in the Supabase bucket at object_path.
+-- At most one photo per listing is the cover, its URL is also kept in the listing's photo_url.
+CREATE TABLE media (
+    id UUID PRIMARY KEY,
+    local_id UUID REFERENCES locals(id) ON DELETE CASCADE,
+    tourist_attraction_id UUID REFERENCES tourist_attractions(id) ON DELETE CASCADE,
+    object_path VARCHAR NOT NULL,
+    url TEXT NOT NULL,
+    caption VARCHAR NOT NULL DEFAULT '',
+    alt_text VARCHAR NOT NULL DEFAULT '',
+    attribution VARCHAR NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(local_id, tourist_attraction_id) = 1)
+);
+
+CREATE INDEX idx_media_local ON media(local_id, position) WHERE local_id IS NOT NULL;
+CREATE INDEX idx_media_tourist_attraction ON media(tourist_attraction_id, position) WHERE tourist_attraction_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_media_local_cover ON media(local_id) WHERE is_cover AND local_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_media_tourist_attraction_cover ON media(tourist_attraction_id) WHERE is_cover AND tourist_attraction_id IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_media_unprocessed;
+ALTER TABLE media
+    DROP COLUMN IF EXISTS processing_started_at,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS variants,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+-- Uploaded photos are processed in the background into upright, metadata free WebP renditions
+-- variants maps a rendition name (original, large, medium, thumbnail) to its url, width and height.
+-- Photos already in a gallery start out pending so they get processed too.
+ALTER TABLE media
+    ADD COLUMN status VARCHAR NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+    ADD COLUMN variants JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN processing_started_at TIMESTAMP;
+
+CREATE INDEX idx_media_unprocessed ON media(created_at) WHERE status IN ('pending', 'processing');
@@ -0,0 +1,8 @@
+-- Refresh tokens become random secrets of which only the SHA-256 hash is stored.
+-- Tokens issued before were derived from the session ID, which access tokens expose,
+-- so every session still active is revoked and its user has to sign in again.
+ALTER TABLE sessions ADD COLUMN token_hash VARCHAR;
+
+UPDATE sessions SET revoked_at = NOW() WHERE revoked_at IS NULL;
+
+CREATE UNIQUE INDEX idx_sessions_token_hash ON sessions(token_hash);
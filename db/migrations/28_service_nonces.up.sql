@@ -0,0 +1,7 @@
+-- Nonces of signed service requests, shared by every instance so a request can't be replayed against another one
+CREATE TABLE IF NOT EXISTS service_nonces (
+    nonce VARCHAR PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_service_nonces_expires_at ON service_nonces(expires_at);
@@ -0,0 +1,8 @@
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS personal_digest;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS personal_salt;
@@ -0,0 +1,21 @@
+-- The hash of new audit events covers a salted digest of the actor ID and IP address instead of the values,
+-- so both can be erased when an account is deleted. Clearing them together with the salt is the only
+-- update the append-only trigger lets through; events written before this migration keep their values.
+ALTER TABLE audit_events ADD COLUMN personal_salt VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN personal_digest VARCHAR NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.personal_digest <> ''
+        AND NEW.actor_id = '' AND NEW.ip_address = '' AND NEW.personal_salt = ''
+        AND (NEW.id, NEW.occurred_at, NEW.actor_type, NEW.action, NEW.target_type, NEW.target_id,
+            NEW.changes::TEXT, NEW.request_id, NEW.personal_digest, NEW.prev_hash, NEW.hash)
+        IS NOT DISTINCT FROM (OLD.id, OLD.occurred_at, OLD.actor_type, OLD.action, OLD.target_type, OLD.target_id,
+            OLD.changes::TEXT, OLD.request_id, OLD.personal_digest, OLD.prev_hash, OLD.hash) THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
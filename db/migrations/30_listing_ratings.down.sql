@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS tourguide_bookings_rating ON tourguide_bookings;
+DROP FUNCTION IF EXISTS tourguide_bookings_rate_attraction();
+DROP TRIGGER IF EXISTS reviews_rating ON reviews;
+DROP FUNCTION IF EXISTS reviews_rate_local();
+
+DROP INDEX IF EXISTS idx_tourist_attractions_rating;
+DROP INDEX IF EXISTS idx_locals_rating;
+
+ALTER TABLE tourist_attractions DROP COLUMN IF EXISTS rating, DROP COLUMN IF EXISTS rating_count, DROP COLUMN IF EXISTS rating_sum;
+ALTER TABLE locals DROP COLUMN IF EXISTS rating, DROP COLUMN IF EXISTS rating_count, DROP COLUMN IF EXISTS rating_sum;
@@ -0,0 +1,68 @@
+-- Stored listing ratings for the catalogue
+-- Sorting by an average computed per row can't use an index, so each listing keeps the sum and count of its
+-- ratings, kept up to date by triggers, and the average is a generated column indexed together with id.
+-- Triggers add and subtract instead of recomputing the average, so concurrent reviews are never lost.
+ALTER TABLE locals
+    ADD COLUMN rating_sum BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tourist_attractions
+    ADD COLUMN rating_sum BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE locals l SET rating_sum = r.total, rating_count = r.count
+FROM (SELECT local_id, SUM(star) AS total, COUNT(*) AS count FROM reviews GROUP BY local_id) r
+WHERE r.local_id = l.id;
+
+UPDATE tourist_attractions a SET rating_sum = b.total, rating_count = b.count
+FROM (
+    SELECT tourist_attraction_id, SUM(star) AS total, COUNT(*) AS count
+    FROM tourguide_bookings
+    WHERE star IS NOT NULL
+    GROUP BY tourist_attraction_id
+) b
+WHERE b.tourist_attraction_id = a.id;
+
+ALTER TABLE locals ADD COLUMN rating DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN rating_count = 0 THEN 0 ELSE CAST(rating_sum AS DOUBLE PRECISION) / rating_count END
+) STORED;
+ALTER TABLE tourist_attractions ADD COLUMN rating DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN rating_count = 0 THEN 0 ELSE CAST(rating_sum AS DOUBLE PRECISION) / rating_count END
+) STORED;
+
+CREATE INDEX idx_locals_rating ON locals(rating, id);
+CREATE INDEX idx_tourist_attractions_rating ON tourist_attractions(rating, id);
+
+CREATE FUNCTION reviews_rate_local() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.local_id IS NOT NULL THEN
+        UPDATE locals SET rating_sum = rating_sum - OLD.star, rating_count = rating_count - 1
+        WHERE id = OLD.local_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.local_id IS NOT NULL THEN
+        UPDATE locals SET rating_sum = rating_sum + NEW.star, rating_count = rating_count + 1
+        WHERE id = NEW.local_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_rating AFTER INSERT OR DELETE OR UPDATE OF star, local_id ON reviews
+    FOR EACH ROW EXECUTE FUNCTION reviews_rate_local();
+
+-- A booking only counts once it has been rated
+CREATE FUNCTION tourguide_bookings_rate_attraction() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.tourist_attraction_id IS NOT NULL AND OLD.star IS NOT NULL THEN
+        UPDATE tourist_attractions SET rating_sum = rating_sum - OLD.star, rating_count = rating_count - 1
+        WHERE id = OLD.tourist_attraction_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.tourist_attraction_id IS NOT NULL AND NEW.star IS NOT NULL THEN
+        UPDATE tourist_attractions SET rating_sum = rating_sum + NEW.star, rating_count = rating_count + 1
+        WHERE id = NEW.tourist_attraction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tourguide_bookings_rating AFTER INSERT OR DELETE OR UPDATE OF star, tourist_attraction_id ON tourguide_bookings
+    FOR EACH ROW EXECUTE FUNCTION tourguide_bookings_rate_attraction();
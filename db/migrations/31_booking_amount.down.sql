@@ -0,0 +1,2 @@
+UPDATE tourguide_bookings SET status = 'cancelled' WHERE status = 'flagged';
+ALTER TABLE tourguide_bookings DROP COLUMN IF EXISTS amount;
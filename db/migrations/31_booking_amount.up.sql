@@ -0,0 +1,8 @@
+-- The tour guide price charged for a booking, fixed when the slot is reserved so a payment notification
+-- can be checked against it even after the attraction's price changes.
+-- Existing bookings take the attraction's current price.
+ALTER TABLE tourguide_bookings ADD COLUMN amount BIGINT NOT NULL DEFAULT 0;
+
+UPDATE tourguide_bookings tb SET amount = ta.tour_guide_price
+FROM tourist_attractions ta
+WHERE ta.id = tb.tourist_attraction_id;
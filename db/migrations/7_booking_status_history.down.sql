@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS booking_status_history CASCADE;
+ALTER TABLE tourguide_bookings ALTER COLUMN status SET DEFAULT 'pending';
@@ -0,0 +1,16 @@
+-- Create booking_status_history table for Vistara Backend
+-- This table records every status transition of a tour guide booking for support and auditing
+UPDATE tourguide_bookings SET status = 'pending_payment' WHERE status = 'pending';
+ALTER TABLE tourguide_bookings ALTER COLUMN status SET DEFAULT 'pending_payment';
+
+CREATE TABLE booking_status_history (
+    id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES tourguide_bookings (id) ON DELETE CASCADE,
+    from_status VARCHAR,
+    to_status VARCHAR NOT NULL,
+    actor VARCHAR NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_booking_status_history_booking_id ON booking_status_history (booking_id, created_at);
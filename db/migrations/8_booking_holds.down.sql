@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tourguide_bookings_capacity;
+ALTER TABLE tourguide_bookings DROP COLUMN IF EXISTS expires_at;
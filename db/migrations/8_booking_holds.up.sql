@@ -0,0 +1,7 @@
+-- Add hold expiry to tourguide_bookings for Vistara Backend
+-- Unpaid bookings hold a tour guide slot until expires_at passes
+ALTER TABLE tourguide_bookings ADD COLUMN expires_at TIMESTAMP;
+
+UPDATE tourguide_bookings SET expires_at = created_at + INTERVAL '24 hours' WHERE status = 'pending_payment';
+
+CREATE INDEX idx_tourguide_bookings_capacity ON tourguide_bookings (tourist_attraction_id, booked_at, status);
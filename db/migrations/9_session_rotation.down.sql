@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP INDEX IF EXISTS idx_sessions_family_id;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS replaced_by,
+    DROP COLUMN IF EXISTS revoked_at,
+    DROP COLUMN IF EXISTS expires_at;
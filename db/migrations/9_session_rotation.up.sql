@@ -0,0 +1,16 @@
+-- Add refresh token rotation to sessions for Vistara Backend
+-- Every refresh replaces the session row with a new one in the same family
+ALTER TABLE sessions
+    ADD COLUMN family_id UUID,
+    ADD COLUMN replaced_by UUID,
+    ADD COLUMN revoked_at TIMESTAMP,
+    ADD COLUMN expires_at TIMESTAMP;
+
+UPDATE sessions SET family_id = id, expires_at = created_at + INTERVAL '7 days';
+
+ALTER TABLE sessions
+    ALTER COLUMN family_id SET NOT NULL,
+    ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX idx_sessions_family_id ON sessions (family_id);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
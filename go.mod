@@ -17,6 +17,7 @@ require (
 	github.com/midtrans/midtrans-go v1.3.8
 	github.com/rs/zerolog v1.34.0
 	golang.org/x/crypto v0.40.0
+	golang.org/x/image v0.29.0
 )
 
 require (
@@ -1,19 +1,29 @@
 package bootstrap
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+
 	"github.com/vistara-studio/vistara-be/internal/infra/ai"
 	"github.com/vistara-studio/vistara-be/internal/infra/config"
 	"github.com/vistara-studio/vistara-be/internal/infra/db"
 	"github.com/vistara-studio/vistara-be/internal/infra/http"
 	"github.com/vistara-studio/vistara-be/internal/infra/logger"
+	"github.com/vistara-studio/vistara-be/internal/infra/mailer"
+	"github.com/vistara-studio/vistara-be/internal/infra/oauth"
 	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+	"github.com/vistara-studio/vistara-be/internal/infra/scheduler"
 	"github.com/vistara-studio/vistara-be/internal/infra/storage"
 	"github.com/vistara-studio/vistara-be/pkg/jwt"
+	"github.com/vistara-studio/vistara-be/pkg/secretbox"
+	"github.com/vistara-studio/vistara-be/pkg/signature"
 	_validator "github.com/vistara-studio/vistara-be/pkg/validator"
 	
 	supabasestorageuploader "github.com/adityarizkyramadhan/supabase-storage-uploader"
@@ -47,8 +57,66 @@ type App struct {
 	handlers  []Handler
 	jwt       *jwt.JWTStruct
 	storage   *supabasestorageuploader.Client
+	remover   *storage.SupabaseRemover
+	objects   *storage.SupabaseObjects
 	payment   paymentMidtrans
 	aiClient  *ai.Client
+	services  *signature.Verifier
+	nonces    *db.NonceStore
+	mfa       session.MFAConfig
+	mfaRoles  []user.Role
+	google    *oauth.GoogleVerifier
+	mailer    mailer.Mailer
+	scheduler *scheduler.Scheduler
+}
+
+// newJWT loads the signing key and any retired verification keys for access tokens
+func newJWT(env *config.Env) (*jwt.JWTStruct, error) {
+	privateKey, err := jwt.LoadPEM(env.JWTPrivateKey, env.JWTPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("jwt signing key: %w", err)
+	}
+
+	verificationKeys, err := jwt.LoadKeyFiles(env.JWTVerificationKeyFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.New(jwt.Config{
+		Algorithm:        env.JWTAlgorithm,
+		KeyID:            env.JWTKeyID,
+		PrivateKeyPEM:    privateKey,
+		VerificationKeys: verificationKeys,
+		Issuer:           env.JWTIssuer,
+		Audience:         env.JWTAudience,
+	})
+}
+
+// newMFA builds the TOTP secret encryption and the roles that have to use two-factor authentication
+func newMFA(env *config.Env) (session.MFAConfig, []user.Role, error) {
+	mfa := session.MFAConfig{Issuer: env.MFAIssuer}
+	if env.MFAEncryptionKey != "" {
+		secrets, err := secretbox.New(env.MFAEncryptionKey)
+		if err != nil {
+			return session.MFAConfig{}, nil, fmt.Errorf("mfa encryption key: %w", err)
+		}
+		mfa.Secrets = secrets
+	}
+
+	var roles []user.Role
+	for _, raw := range env.MFARequiredRoles {
+		role := user.Role(strings.TrimSpace(raw))
+		if !role.IsValid() {
+			return session.MFAConfig{}, nil, fmt.Errorf("MFA_REQUIRED_ROLES: unknown role %q", raw)
+		}
+		roles = append(roles, role)
+	}
+
+	if len(roles) > 0 && mfa.Secrets == nil {
+		return session.MFAConfig{}, nil, errors.New("MFA_REQUIRED_ROLES needs MFA_ENCRYPTION_KEY to be set")
+	}
+
+	return mfa, roles, nil
 }
 
 // Initialize starts the application with all dependencies
@@ -66,12 +134,47 @@ func Initialize() error {
 	}
 
 	// Initialize dependencies
-	jwt := jwt.New(env.JWTSecret)
+	jwt, err := newJWT(env)
+	if err != nil {
+		return err
+	}
 	validator := _validator.New()
-	httpServer := http.NewFiber()
+	httpServer := http.NewFiber(env.TrustedProxies)
+	remover := storage.NewRemover(env.StorageURL, env.StorageToken, env.StorageBucket)
+	objects := storage.NewObjects(env.StorageURL, env.StorageToken, env.StorageBucket)
 	storage := storage.New(env.StorageURL, env.StorageToken, env.StorageBucket)
 	paymentSnap, paymentCore := payment.New(env.MidtransKey)
-	aiClient := ai.NewClient(env.VistaraAIURL, env.VistaraAIKey)
+
+	// Service keys sign outgoing AI requests and verify incoming ones, several keys allow rotation
+	serviceKeys, err := signature.ParseKeys(env.ServiceAuthKeys)
+	if err != nil {
+		return err
+	}
+	signingKey, err := signature.SigningKey(serviceKeys, env.ServiceAuthSigningKeyID)
+	if err != nil {
+		return err
+	}
+	aiClient := ai.NewClient(env.VistaraAIURL, signature.NewSigner(signingKey))
+	nonces := db.NewNonceStore(postgres)
+
+	mfa, mfaRoles, err := newMFA(env)
+	if err != nil {
+		return err
+	}
+
+	google := oauth.NewGoogleVerifier(env.GoogleJWKSURL, env.GoogleClientID)
+	mailer, err := mailer.New(mailer.Config{
+		Driver:       env.MailerDriver,
+		From:         env.MailFrom,
+		SMTPHost:     env.SMTPHost,
+		SMTPPort:     env.SMTPPort,
+		SMTPUsername: env.SMTPUsername,
+		SMTPPassword: env.SMTPPassword,
+		FileDir:      env.MailerFileDir,
+	})
+	if err != nil {
+		return err
+	}
 
 	// Create app instance
 	app = &App{
@@ -81,17 +184,29 @@ func Initialize() error {
 		validator: validator,
 		jwt:       jwt,
 		storage:   storage,
+		remover:   remover,
+		objects:   objects,
 		payment: paymentMidtrans{
 			snap:    paymentSnap,
 			coreapi: paymentCore,
 		},
-		aiClient: aiClient,
+		aiClient:  aiClient,
+		services:  signature.NewVerifier(serviceKeys, env.ServiceAuthWindow, nonces),
+		nonces:    nonces,
+		mfa:       mfa,
+		mfaRoles:  mfaRoles,
+		google:    google,
+		mailer:    mailer,
+		scheduler: scheduler.New(),
 	}
 
 	// Initialize logger and handlers
 	logger.New()
 	app.InitHandlers()
 
+	// Start background jobs
+	app.scheduler.Start()
+
 	// Start graceful shutdown listener
 	go shutdown()
 
@@ -108,6 +223,8 @@ func shutdown() {
 	log.Info().Msg("Received shutdown signal")
 	log.Info().Msg("Shutting down gracefully...")
 
-	_ = app.postgres.Close()
+	// Let running jobs finish before their database connections go away
+	app.scheduler.Stop()
 	_ = app.http.Shutdown()
+	_ = app.postgres.Close()
 }
@@ -1,23 +1,49 @@
 package bootstrap
 
 import (
+	"time"
+
 	aiHandler "github.com/vistara-studio/vistara-be/internal/domain/ai/handler/rest"
+	apiKeyHandler "github.com/vistara-studio/vistara-be/internal/domain/apikey/handler/rest"
+	apiKeyRepository "github.com/vistara-studio/vistara-be/internal/domain/apikey/repository"
+	apiKeyService "github.com/vistara-studio/vistara-be/internal/domain/apikey/service"
+	auditHandler "github.com/vistara-studio/vistara-be/internal/domain/audit/handler/rest"
+	auditRepository "github.com/vistara-studio/vistara-be/internal/domain/audit/repository"
+	auditService "github.com/vistara-studio/vistara-be/internal/domain/audit/service"
+	categoryHandler "github.com/vistara-studio/vistara-be/internal/domain/category/handler/rest"
+	categoryRepository "github.com/vistara-studio/vistara-be/internal/domain/category/repository"
+	categoryService "github.com/vistara-studio/vistara-be/internal/domain/category/service"
 	"github.com/vistara-studio/vistara-be/internal/domain/local/handler/rest"
 	localRepository "github.com/vistara-studio/vistara-be/internal/domain/local/repository"
 	localService "github.com/vistara-studio/vistara-be/internal/domain/local/service"
+	mediaHandler "github.com/vistara-studio/vistara-be/internal/domain/media/handler/rest"
+	mediaRepository "github.com/vistara-studio/vistara-be/internal/domain/media/repository"
+	mediaService "github.com/vistara-studio/vistara-be/internal/domain/media/service"
+	paymentHandler "github.com/vistara-studio/vistara-be/internal/domain/payment/handler/rest"
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
 	sessionHandler "github.com/vistara-studio/vistara-be/internal/domain/session/handler/rest"
 	sessionRepository "github.com/vistara-studio/vistara-be/internal/domain/session/repository"
 	sessionService "github.com/vistara-studio/vistara-be/internal/domain/session/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	subscriptionHandler "github.com/vistara-studio/vistara-be/internal/domain/subscription/handler/rest"
+	subscriptionRepository "github.com/vistara-studio/vistara-be/internal/domain/subscription/repository"
+	subscriptionService "github.com/vistara-studio/vistara-be/internal/domain/subscription/service"
+	userHandler "github.com/vistara-studio/vistara-be/internal/domain/user/handler/rest"
 	userRepository "github.com/vistara-studio/vistara-be/internal/domain/user/repository"
+	userService "github.com/vistara-studio/vistara-be/internal/domain/user/service"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
 	"github.com/vistara-studio/vistara-be/pkg/jwt"
 	"github.com/gofiber/fiber/v2"
 )
 
 // InitHandlers initializes all application handlers and routes
 func (app *App) InitHandlers() {
+	app.http.Use(middleware.RequestMeta())
 	app.registerRoutes(app.jwt)
 	app.MountRoutes()
 	app.registerHealthCheck()
+	app.registerJWKS()
 }
 
 // registerRoutes initializes repositories, services, and handlers
@@ -26,18 +52,55 @@ func (app *App) registerRoutes(jwt *jwt.JWTStruct) {
 	userRepo := userRepository.New(app.postgres)
 	sessionRepo := sessionRepository.New(app.postgres)
 	localRepo := localRepository.New(app.postgres)
+	subscriptionRepo := subscriptionRepository.New(app.postgres)
+	apiKeyRepo := apiKeyRepository.New(app.postgres)
+	auditRepo := auditRepository.New(app.postgres)
+	categoryRepo := categoryRepository.New(app.postgres)
+	mediaRepo := mediaRepository.New(app.postgres)
+
+	// Initialize services, the audit log comes first as the others record into it
+	auditService := auditService.New(auditRepo)
+	authService := sessionService.New(userRepo, sessionRepo, jwt, app.google, app.mailer, app.storage, app.remover, app.config.AppURL, app.config.DefaultPhotoURL, session.LoginThrottle{
+		MaxAccountFailures: app.config.LoginMaxAccountFailures,
+		MaxIPFailures:      app.config.LoginMaxIPFailures,
+		Window:             app.config.LoginFailureWindow,
+		LockoutDuration:    app.config.LoginLockoutDuration,
+	}, app.mfa, auditService)
+	userService := userService.New(userRepo, sessionRepo, app.storage, app.remover, app.aiClient, auditService)
+	localBusinessService := localService.New(localRepo, app.payment.snap, app.payment.coreapi, auditService, app.remover)
+	subscriptionService := subscriptionService.New(subscriptionRepo, app.payment.snap, app.payment.coreapi)
+	apiKeyService := apiKeyService.New(apiKeyRepo, auditService)
+	categoryService := categoryService.New(categoryRepo, auditService)
+	mediaService := mediaService.New(mediaRepo, app.objects, app.remover, auditService)
+
+	// Route Midtrans notifications by order ID, bookings use their bare ID
+	paymentDispatcher := payment.NewDispatcher(localBusinessService)
+	paymentDispatcher.Handle(subscription.OrderIDPrefix, subscriptionService)
+
+	// Register background jobs
+	app.scheduler.Register("expire-stale-bookings", app.config.BookingExpiryInterval, localBusinessService.ExpireStaleBookings)
+	app.scheduler.Register("downgrade-expired-premium", app.config.PremiumDowngradeInterval, subscriptionService.DowngradeExpiredPremium)
+	app.scheduler.Register("prune-login-attempts", time.Hour, authService.PruneLoginAttempts)
+	app.scheduler.Register("prune-service-nonces", time.Hour, app.nonces.Prune)
+	app.scheduler.Register("process-media", app.config.MediaProcessingInterval, mediaService.ProcessPendingMedia)
 
-	// Initialize services
-	authService := sessionService.New(userRepo, sessionRepo, jwt)
-	localBusinessService := localService.New(localRepo, app.payment.snap, app.payment.coreapi)
+	// Initialize middlewares
+	middleware := middleware.New(jwt, authService, apiKeyService, app.services, app.mfaRoles)
 
 	// Initialize handlers
-	authHandler := sessionHandler.New(authService, app.validator)
-	localHandler := rest.New(localBusinessService, app.validator, app.jwt)
-	aiHandler := aiHandler.NewAIHandler(app.aiClient, app.validator, app.jwt)
+	authHandler := sessionHandler.New(authService, app.validator, middleware)
+	userHandler := userHandler.New(userService, app.validator, middleware)
+	localHandler := rest.New(localBusinessService, app.validator, middleware)
+	aiHandler := aiHandler.NewAIHandler(app.aiClient, app.validator, middleware)
+	subscriptionHandler := subscriptionHandler.New(subscriptionService, app.validator, middleware)
+	paymentHandler := paymentHandler.New(paymentDispatcher, app.validator)
+	apiKeyHandler := apiKeyHandler.New(apiKeyService, app.validator, middleware)
+	auditHandler := auditHandler.New(auditService, app.validator, middleware)
+	categoryHandler := categoryHandler.New(categoryService, app.validator, middleware)
+	mediaHandler := mediaHandler.New(mediaService, app.validator, middleware)
 
 	// Register handlers
-	app.handlers = append(app.handlers, authHandler, localHandler, aiHandler)
+	app.handlers = append(app.handlers, authHandler, userHandler, localHandler, aiHandler, subscriptionHandler, paymentHandler, apiKeyHandler, auditHandler, categoryHandler, mediaHandler)
 }
 
 // MountRoutes mounts all registered handlers on the router
@@ -54,3 +117,11 @@ func (app *App) registerHealthCheck() {
 		return ctx.SendString("Everything is good!")
 	})
 }
+
+// registerJWKS publishes the access token verification keys for other services
+func (app *App) registerJWKS() {
+	app.http.Get("/.well-known/jwks.json", func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return ctx.JSON(app.jwt.JWKS())
+	})
+}
@@ -5,22 +5,21 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/vistara-studio/vistara-be/internal/infra/ai"
 	"github.com/vistara-studio/vistara-be/internal/middleware"
-	"github.com/vistara-studio/vistara-be/pkg/jwt"
 )
 
 // AIHandler handles AI-related HTTP requests
 type AIHandler struct {
-	aiClient  *ai.Client
-	validator *validator.Validate
-	jwt       *jwt.JWTStruct
+	aiClient   *ai.Client
+	validator  *validator.Validate
+	middleware *middleware.Middleware
 }
 
 // NewAIHandler creates a new AI handler instance
-func NewAIHandler(aiClient *ai.Client, validator *validator.Validate, jwt *jwt.JWTStruct) *AIHandler {
+func NewAIHandler(aiClient *ai.Client, validator *validator.Validate, middleware *middleware.Middleware) *AIHandler {
 	return &AIHandler{
-		aiClient:  aiClient,
-		validator: validator,
-		jwt:       jwt,
+		aiClient:   aiClient,
+		validator:  validator,
+		middleware: middleware,
 	}
 }
 
@@ -29,21 +28,21 @@ func (h *AIHandler) Mount(router fiber.Router) {
 	// Protected AI endpoints (requires JWT authentication) - using /v1/user pattern like port 5000
 	v1Group := router.Group("/v1")
 	userGroup := v1Group.Group("/user")
-	userGroup.Use(middleware.Authentication(h.jwt))
+	userGroup.Use(h.middleware.Authentication())
 	userGroup.Post("/smart-planner", h.GenerateSmartPlan)
 	userGroup.Post("/nusalingo", h.GenerateNusaLingo)
 	userGroup.Post("/historical-story", h.GenerateHistoricalStory)
 
 	// Legacy AI endpoints (for backward compatibility)
 	aiGroup := router.Group("/ai")
-	aiGroup.Use(middleware.Authentication(h.jwt))
+	aiGroup.Use(h.middleware.Authentication())
 	aiGroup.Post("/smart-planner", h.GenerateSmartPlan)
 	aiGroup.Post("/nusalingo", h.GenerateNusaLingo)
 	aiGroup.Post("/historical-story", h.GenerateHistoricalStory)
 
 	// Service-to-service endpoints (requires service authentication)  
 	serviceGroup := router.Group("/service")
-	serviceGroup.Use(middleware.ServiceAuthentication())
+	serviceGroup.Use(h.middleware.ServiceAuthentication())
 	serviceGroup.Post("/ai/notify", h.ReceiveNotification)
 }
 
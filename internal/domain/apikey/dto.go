@@ -0,0 +1,54 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateKeyRequest struct {
+	UserID    uuid.UUID  `json:"user_id" validate:"required"`
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []Scope    `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type KeyResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	Scopes       []string   `json:"scopes"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	RequestCount int64      `json:"request_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreateKeyResponse carries the only copy of the plain text key
+type CreateKeyResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+type UsageResponse struct {
+	Day      string `json:"day"`
+	Requests int64  `json:"requests"`
+}
+
+// NewKeyResponse builds the view of a key without its hash
+func NewKeyResponse(data Key) KeyResponse {
+	return KeyResponse{
+		ID:           data.ID,
+		UserID:       data.UserID,
+		Name:         data.Name,
+		Prefix:       data.Prefix,
+		Scopes:       data.Scopes,
+		ExpiresAt:    data.ExpiresAt,
+		RevokedAt:    data.RevokedAt,
+		LastUsedAt:   data.LastUsedAt,
+		RequestCount: data.RequestCount,
+		CreatedAt:    data.CreatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Key is a partner API key, the key itself is only shown once and stored as a hash
+type Key struct {
+	ID           uuid.UUID      `db:"id"`
+	UserID       uuid.UUID      `db:"user_id"`
+	Name         string         `db:"name"`
+	Prefix       string         `db:"prefix"`
+	KeyHash      string         `db:"key_hash"`
+	Scopes       pq.StringArray `db:"scopes"`
+	ExpiresAt    *time.Time     `db:"expires_at"`
+	RevokedAt    *time.Time     `db:"revoked_at"`
+	LastUsedAt   *time.Time     `db:"last_used_at"`
+	RequestCount int64          `db:"request_count"`
+	CreatedBy    *uuid.UUID     `db:"created_by"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// Usage is the number of authenticated requests made with a key on one day
+type Usage struct {
+	Day      time.Time `db:"day"`
+	Requests int64     `db:"requests"`
+}
+
+// Principal is the account an active API key acts for
+type Principal struct {
+	KeyID         uuid.UUID      `db:"id"`
+	UserID        uuid.UUID      `db:"user_id"`
+	Scopes        pq.StringArray `db:"scopes"`
+	EmailVerified bool           `db:"email_verified"`
+	IsPremium     bool           `db:"is_premium"`
+}
+
+// HasScope reports whether the key was granted scope
+func (p Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if Scope(granted) == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package apikey
+
+// KeyPrefix starts every partner API key so leaked keys are easy to recognise
+const KeyPrefix = "vsk_"
+
+// DisplayPrefixLength is how much of a key is kept in plain text to tell keys apart
+const DisplayPrefixLength = 12
+
+// Scope is a permission granted to a partner API key
+type Scope string
+
+const (
+	ScopeReadCatalogue Scope = "read:catalogue"
+	ScopeReadBookings  Scope = "read:bookings"
+	ScopeWriteBookings Scope = "write:bookings"
+)
+
+var scopes = []Scope{ScopeReadCatalogue, ScopeReadBookings, ScopeWriteBookings}
+
+// Valid reports whether s is a known scope
+func (s Scope) Valid() bool {
+	for _, scope := range scopes {
+		if scope == s {
+			return true
+		}
+	}
+
+	return false
+}
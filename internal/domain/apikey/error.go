@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrInvalidAPIKey  = cerr.New(fiber.ErrUnauthorized.Code, "api key is invalid, expired or revoked", errors.New("api key not found, expired or revoked"))
+	ErrMissingScope   = cerr.New(fiber.ErrForbidden.Code, "api key doesn't have the scope required for this endpoint", errors.New("api key scope missing"))
+	ErrAPIKeyNotFound = cerr.New(fiber.ErrNotFound.Code, "api key not found", errors.New("api key not found"))
+	ErrOwnerNotFound  = cerr.New(fiber.ErrNotFound.Code, "account not found", errors.New("api key owner not found"))
+	ErrInvalidScope   = cerr.New(fiber.ErrBadRequest.Code, "scopes must be read:catalogue, read:bookings or write:bookings", errors.New("invalid api key scope"))
+	ErrInvalidExpiry  = cerr.New(fiber.ErrBadRequest.Code, "expires_at must be in the future", errors.New("api key expiry in the past"))
+)
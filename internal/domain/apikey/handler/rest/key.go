@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *APIKeyHandler) createKey(ctx *fiber.Ctx) error {
+	adminID, _ := ctx.Locals("user_id").(string)
+
+	var request apikey.CreateKeyRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.CreateKey(ctx.Context(), adminID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "api key created, store the key now, it won't be shown again",
+		"payload": response,
+	})
+}
+
+// listKeys lists every key, or a single partner's keys with ?user_id=
+func (h *APIKeyHandler) listKeys(ctx *fiber.Ctx) error {
+	response, err := h.service.ListKeys(ctx.Context(), ctx.Query("user_id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get api keys successful",
+		"payload": response,
+	})
+}
+
+// getUsage returns daily request counters, ?days= defaults to and is capped at 90
+func (h *APIKeyHandler) getUsage(ctx *fiber.Ctx) error {
+	response, err := h.service.GetUsage(ctx.Context(), ctx.Params("keyID"), ctx.QueryInt("days"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get api key usage successful",
+		"payload": response,
+	})
+}
+
+func (h *APIKeyHandler) revokeKey(ctx *fiber.Ctx) error {
+	response, err := h.service.RevokeKey(ctx.Context(), ctx.Params("keyID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "api key revoked",
+		"payload": response,
+	})
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	service    service.APIKeyServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+func New(service service.APIKeyServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *APIKeyHandler {
+	return &APIKeyHandler{service: service, validator: validator, middleware: middleware}
+}
+
+func (h *APIKeyHandler) Mount(router fiber.Router) {
+	adminGroup := router.Group("/admin/api-keys")
+
+	authentication := h.middleware.Authentication()
+	requireAdmin := h.middleware.RequireRole(user.RoleAdmin)
+	adminGroup.Post("/", authentication, requireAdmin, h.createKey)
+	adminGroup.Get("/", authentication, requireAdmin, h.listKeys)
+	adminGroup.Get("/:keyID/usage", authentication, requireAdmin, h.getUsage)
+	adminGroup.Delete("/:keyID", authentication, requireAdmin, h.revokeKey)
+}
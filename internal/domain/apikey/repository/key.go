@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const keyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, revoked_at,
+	last_used_at, request_count, created_by, created_at`
+
+func (r *apiKeyRepository) CreateKey(ctx context.Context, data apikey.Key) error {
+	query := `INSERT INTO api_keys (
+		id, user_id, name, prefix, key_hash, scopes, expires_at, created_by
+	) VALUES (
+		:id, :user_id, :name, :prefix, :key_hash, :scopes, :expires_at, :created_by
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "foreign_key_violation" {
+			return apikey.ErrOwnerNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) GetKeyByID(ctx context.Context, data *apikey.Key) error {
+	query := `SELECT ` + keyColumns + ` FROM api_keys WHERE id = $1`
+
+	row := r.q.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apikey.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetKeys lists keys newest first, all keys when userID is nil
+func (r *apiKeyRepository) GetKeys(ctx context.Context, userID *uuid.UUID, out *[]apikey.Key) error {
+	query := `SELECT ` + keyColumns + ` FROM api_keys
+	WHERE $1::uuid IS NULL OR user_id = $1
+	ORDER BY created_at DESC
+	`
+
+	rows, err := r.q.QueryxContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	result := []apikey.Key{}
+	for rows.Next() {
+		var item apikey.Key
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
+
+// RevokeKey stamps revoked_at once, revoking an already revoked key keeps the original time
+func (r *apiKeyRepository) RevokeKey(ctx context.Context, data *apikey.Key) error {
+	query := `UPDATE api_keys
+	SET revoked_at = COALESCE(revoked_at, NOW())
+	WHERE id = $1
+	RETURNING ` + keyColumns
+
+	row := r.q.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apikey.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetPrincipalByHash finds an unexpired, unrevoked key together with the state of its owner
+func (r *apiKeyRepository) GetPrincipalByHash(ctx context.Context, keyHash string, out *apikey.Principal) error {
+	query := `SELECT k.id, k.user_id, k.scopes,
+	u.email_verified_at IS NOT NULL AS email_verified,
+	u.is_premium AND COALESCE(u.expired_at > NOW(), FALSE) AS is_premium
+	FROM api_keys k
+	INNER JOIN users u ON u.id = k.user_id
+	WHERE k.key_hash = $1
+		AND k.revoked_at IS NULL
+		AND (k.expires_at IS NULL OR k.expires_at > NOW())
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, keyHash)
+	if err := row.StructScan(out); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apikey.ErrInvalidAPIKey
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RecordUsage bumps the key's total and today's counter in one statement
+func (r *apiKeyRepository) RecordUsage(ctx context.Context, keyID uuid.UUID) error {
+	query := `WITH used AS (
+		UPDATE api_keys
+		SET request_count = request_count + 1, last_used_at = NOW()
+		WHERE id = $1
+		RETURNING id
+	)
+	INSERT INTO api_key_usage (api_key_id, day, requests)
+	SELECT id, CURRENT_DATE, 1 FROM used
+	ON CONFLICT (api_key_id, day) DO UPDATE SET requests = api_key_usage.requests + 1
+	`
+
+	_, err := r.q.ExecContext(ctx, query, keyID)
+	return err
+}
+
+func (r *apiKeyRepository) GetUsage(ctx context.Context, keyID uuid.UUID, since time.Time, out *[]apikey.Usage) error {
+	query := `SELECT day, requests FROM api_key_usage
+	WHERE api_key_id = $1 AND day >= $2::date
+	ORDER BY day DESC
+	`
+
+	rows, err := r.q.QueryxContext(ctx, query, keyID, since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	result := []apikey.Usage{}
+	for rows.Next() {
+		var item apikey.Usage
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	errFailedToCommit   = errors.New("FAILED_TO_COMMIT_TRANSACTION")
+	errFailedToRollback = errors.New("FAILED_TO_ROLLBACK_TRANSACTION")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+type RepositoryItf interface {
+	NewClient(tx bool) (apiKeyRepositoryItf, error)
+}
+
+type apiKeyRepository struct {
+	q namedExt
+}
+
+type apiKeyRepositoryItf interface {
+	Commit() error
+	Rollback() error
+	AuditTx() audit.Tx
+	CreateKey(ctx context.Context, data apikey.Key) error
+	GetKeyByID(ctx context.Context, data *apikey.Key) error
+	GetKeys(ctx context.Context, userID *uuid.UUID, out *[]apikey.Key) error
+	RevokeKey(ctx context.Context, data *apikey.Key) error
+	GetPrincipalByHash(ctx context.Context, keyHash string, out *apikey.Principal) error
+	RecordUsage(ctx context.Context, keyID uuid.UUID) error
+	GetUsage(ctx context.Context, keyID uuid.UUID, since time.Time, out *[]apikey.Usage) error
+}
+
+type namedExt interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+func New(db *sqlx.DB) RepositoryItf {
+	return &repository{db}
+}
+
+func (r *repository) NewClient(tx bool) (apiKeyRepositoryItf, error) {
+	var db namedExt
+
+	db = r.DB
+	if tx {
+		var err error
+		db, err = r.DB.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &apiKeyRepository{db}, nil
+}
+
+func (r *apiKeyRepository) Commit() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+
+	return errFailedToCommit
+}
+
+func (r *apiKeyRepository) Rollback() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+
+	return errFailedToRollback
+}
+
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (r *apiKeyRepository) AuditTx() audit.Tx {
+	return r.q
+}
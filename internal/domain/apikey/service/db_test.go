@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+)
+
+// openTestDB connects to the migrated database given as a DSN in TEST_DATABASE_URL and skips the test without one
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// discardRecorder drops audit entries, the append-only audit table can't be cleaned up after a test
+type discardRecorder struct{}
+
+func (discardRecorder) Record(context.Context, audit.Tx, audit.Entry) error {
+	return nil
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// maxUsageDays bounds how far back the usage report goes
+const maxUsageDays = 90
+
+// CreateKey issues a key for a partner account, the plain text key is only returned here
+func (s *apiKeyService) CreateKey(ctx context.Context, adminID string, request apikey.CreateKeyRequest) (response apikey.CreateKeyResponse, err error) {
+	scopes := pq.StringArray{}
+	for _, scope := range request.Scopes {
+		if !scope.Valid() {
+			return apikey.CreateKeyResponse{}, apikey.ErrInvalidScope
+		}
+		scopes = append(scopes, string(scope))
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return apikey.CreateKeyResponse{}, apikey.ErrInvalidExpiry
+	}
+
+	rawKey, err := generateKey()
+	if err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	key := apikey.Key{
+		ID:        id,
+		UserID:    request.UserID,
+		Name:      request.Name,
+		Prefix:    rawKey[:apikey.DisplayPrefixLength],
+		KeyHash:   hashKey(rawKey),
+		Scopes:    scopes,
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if createdBy, err := uuid.Parse(adminID); err == nil {
+		key.CreatedBy = &createdBy
+	}
+
+	apiKeyRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = apiKeyRepository.Rollback()
+		}
+	}()
+
+	if err = apiKeyRepository.CreateKey(ctx, key); err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, apiKeyRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(adminID),
+		Action:     audit.ActionAPIKeyCreated,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   key.ID.String(),
+		Changes: audit.Changes{
+			"name":       {To: key.Name},
+			"scopes":     {To: key.Scopes},
+			"expires_at": {To: key.ExpiresAt},
+		},
+	})
+	if err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	if err = apiKeyRepository.Commit(); err != nil {
+		return apikey.CreateKeyResponse{}, err
+	}
+
+	return apikey.CreateKeyResponse{KeyResponse: apikey.NewKeyResponse(key), Key: rawKey}, nil
+}
+
+// ListKeys returns every key, or only the keys of userID when it is set
+func (s *apiKeyService) ListKeys(ctx context.Context, userID string) ([]apikey.KeyResponse, error) {
+	var owner *uuid.UUID
+	if userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, apikey.ErrOwnerNotFound
+		}
+		owner = &id
+	}
+
+	apiKeyRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []apikey.Key
+	if err := apiKeyRepository.GetKeys(ctx, owner, &keys); err != nil {
+		return nil, err
+	}
+
+	response := make([]apikey.KeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, apikey.NewKeyResponse(key))
+	}
+
+	return response, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, keyID string) (response apikey.KeyResponse, err error) {
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return apikey.KeyResponse{}, apikey.ErrAPIKeyNotFound
+	}
+
+	apiKeyRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return apikey.KeyResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = apiKeyRepository.Rollback()
+		}
+	}()
+
+	key := &apikey.Key{ID: id}
+	if err = apiKeyRepository.RevokeKey(ctx, key); err != nil {
+		return apikey.KeyResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, apiKeyRepository.AuditTx(), audit.Entry{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     audit.ActionAPIKeyRevoked,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   key.ID.String(),
+		Changes:    audit.Changes{"revoked_at": {To: key.RevokedAt}},
+	})
+	if err != nil {
+		return apikey.KeyResponse{}, err
+	}
+
+	if err = apiKeyRepository.Commit(); err != nil {
+		return apikey.KeyResponse{}, err
+	}
+
+	return apikey.NewKeyResponse(*key), nil
+}
+
+// GetUsage returns the daily request counters of a key for the last days days, newest first
+func (s *apiKeyService) GetUsage(ctx context.Context, keyID string, days int) ([]apikey.UsageResponse, error) {
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return nil, apikey.ErrAPIKeyNotFound
+	}
+
+	if days <= 0 || days > maxUsageDays {
+		days = maxUsageDays
+	}
+
+	apiKeyRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apiKeyRepository.GetKeyByID(ctx, &apikey.Key{ID: id}); err != nil {
+		return nil, err
+	}
+
+	var usage []apikey.Usage
+	since := time.Now().AddDate(0, 0, -(days - 1))
+	if err := apiKeyRepository.GetUsage(ctx, id, since, &usage); err != nil {
+		return nil, err
+	}
+
+	response := make([]apikey.UsageResponse, 0, len(usage))
+	for _, day := range usage {
+		response = append(response, apikey.UsageResponse{Day: day.Day.Format("2006-01-02"), Requests: day.Requests})
+	}
+
+	return response, nil
+}
+
+// AuthenticateAPIKey resolves an active key and counts the request against it
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, key string) (apikey.Principal, error) {
+	if !strings.HasPrefix(key, apikey.KeyPrefix) {
+		return apikey.Principal{}, apikey.ErrInvalidAPIKey
+	}
+
+	apiKeyRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return apikey.Principal{}, err
+	}
+
+	var principal apikey.Principal
+	if err := apiKeyRepository.GetPrincipalByHash(ctx, hashKey(key), &principal); err != nil {
+		return apikey.Principal{}, err
+	}
+
+	// A failed counter update shouldn't turn away a valid partner request
+	if err := apiKeyRepository.RecordUsage(ctx, principal.KeyID); err != nil {
+		log.Warn().Err(err).Str("api_key_id", principal.KeyID.String()).Msg("failed to record api key usage")
+	}
+
+	return principal, nil
+}
+
+func generateKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return apikey.KeyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashKey(rawKey string) string {
+	digest := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(digest[:])
+}
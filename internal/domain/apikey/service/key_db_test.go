@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey/repository"
+)
+
+// createTestOwner inserts a partner account and deletes it, and with it its keys, when the test ends
+func createTestOwner(t *testing.T, db *sqlx.DB) uuid.UUID {
+	t.Helper()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	_, err := db.ExecContext(ctx, `INSERT INTO users (id, full_name, email, auth_provider, photo_url, email_verified_at) VALUES ($1, 'Key Test', $2, 'email', '', NOW())`,
+		userID, userID.String()+"@example.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID) })
+
+	return userID
+}
+
+// TestCreateKeyStoresOnlyTheHash needs a migrated database given in TEST_DATABASE_URL
+func TestCreateKeyStoresOnlyTheHash(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	s := &apiKeyService{repository: repository.New(db), audit: discardRecorder{}}
+	userID := createTestOwner(t, db)
+
+	created, err := s.CreateKey(ctx, "", apikey.CreateKeyRequest{
+		UserID: userID,
+		Name:   "partner",
+		Scopes: []apikey.Scope{apikey.ScopeReadCatalogue},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var row struct {
+		Prefix  string `db:"prefix"`
+		KeyHash string `db:"key_hash"`
+		Name    string `db:"name"`
+	}
+	if err := db.GetContext(ctx, &row, `SELECT prefix, key_hash, name FROM api_keys WHERE id = $1`, created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if row.KeyHash != hashKey(created.Key) {
+		t.Fatalf("key_hash = %q, want the hash of the issued key", row.KeyHash)
+	}
+	if row.Prefix != created.Key[:apikey.DisplayPrefixLength] {
+		t.Fatalf("prefix = %q, want the first %d characters of the key", row.Prefix, apikey.DisplayPrefixLength)
+	}
+	for column, value := range map[string]string{"prefix": row.Prefix, "key_hash": row.KeyHash, "name": row.Name} {
+		if strings.Contains(value, created.Key) {
+			t.Fatalf("%s holds the plain text key", column)
+		}
+	}
+}
+
+// TestAuthenticateAPIKey needs a migrated database given in TEST_DATABASE_URL
+func TestAuthenticateAPIKey(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	s := &apiKeyService{repository: repository.New(db), audit: discardRecorder{}}
+	userID := createTestOwner(t, db)
+
+	issue := func(t *testing.T) apikey.CreateKeyResponse {
+		t.Helper()
+
+		created, err := s.CreateKey(ctx, "", apikey.CreateKeyRequest{
+			UserID: userID,
+			Name:   "partner",
+			Scopes: []apikey.Scope{apikey.ScopeReadBookings},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return created
+	}
+
+	t.Run("active key", func(t *testing.T) {
+		created := issue(t)
+
+		principal, err := s.AuthenticateAPIKey(ctx, created.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if principal.KeyID != created.ID || principal.UserID != userID {
+			t.Fatalf("principal = %+v, want key %s of user %s", principal, created.ID, userID)
+		}
+		if !principal.EmailVerified {
+			t.Fatal("principal doesn't carry the owner's verified email")
+		}
+		if !principal.HasScope(apikey.ScopeReadBookings) || principal.HasScope(apikey.ScopeWriteBookings) {
+			t.Fatalf("scopes = %v, want only %s", principal.Scopes, apikey.ScopeReadBookings)
+		}
+	})
+
+	t.Run("usage is counted per request", func(t *testing.T) {
+		created := issue(t)
+
+		const requests = 3
+		for i := 0; i < requests; i++ {
+			if _, err := s.AuthenticateAPIKey(ctx, created.Key); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var key struct {
+			RequestCount int64      `db:"request_count"`
+			LastUsedAt   *time.Time `db:"last_used_at"`
+		}
+		if err := db.GetContext(ctx, &key, `SELECT request_count, last_used_at FROM api_keys WHERE id = $1`, created.ID); err != nil {
+			t.Fatal(err)
+		}
+		if key.RequestCount != requests || key.LastUsedAt == nil {
+			t.Fatalf("request_count = %d, last_used_at = %v, want %d and a time", key.RequestCount, key.LastUsedAt, requests)
+		}
+
+		usage, err := s.GetUsage(ctx, created.ID.String(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(usage) != 1 || usage[0].Requests != requests {
+			t.Fatalf("usage = %+v, want one day with %d requests", usage, requests)
+		}
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		created := issue(t)
+		if _, err := s.RevokeKey(ctx, created.ID.String()); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.AuthenticateAPIKey(ctx, created.Key); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+			t.Fatalf("error = %v, want %v", err, apikey.ErrInvalidAPIKey)
+		}
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		created := issue(t)
+		// CreateKey refuses a past expiry, so the key is expired in place
+		if _, err := db.ExecContext(ctx, `UPDATE api_keys SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, created.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.AuthenticateAPIKey(ctx, created.Key); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+			t.Fatalf("error = %v, want %v", err, apikey.ErrInvalidAPIKey)
+		}
+	})
+
+	t.Run("unknown and malformed keys", func(t *testing.T) {
+		for _, key := range []string{apikey.KeyPrefix + "unknown", "not-a-key"} {
+			if _, err := s.AuthenticateAPIKey(ctx, key); !errors.Is(err, apikey.ErrInvalidAPIKey) {
+				t.Fatalf("key %q: error = %v, want %v", key, err, apikey.ErrInvalidAPIKey)
+			}
+		}
+	})
+}
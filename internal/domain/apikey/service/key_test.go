@@ -0,0 +1,58 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+)
+
+func TestGenerateKey(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 16; i++ {
+		key, err := generateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(key, apikey.KeyPrefix) {
+			t.Fatalf("key %q doesn't start with %q", key, apikey.KeyPrefix)
+		}
+
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(key, apikey.KeyPrefix))
+		if err != nil {
+			t.Fatalf("key %q isn't unpadded base64url after the prefix: %v", key, err)
+		}
+		if len(raw) != 32 {
+			t.Fatalf("key carries %d random bytes, want 32", len(raw))
+		}
+
+		if len(key) <= apikey.DisplayPrefixLength {
+			t.Fatalf("key %q is no longer than the displayed prefix", key)
+		}
+
+		if seen[key] {
+			t.Fatalf("key %q generated twice", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	key := apikey.KeyPrefix + "abc"
+	digest := sha256.Sum256([]byte(key))
+
+	got := hashKey(key)
+	if got != hex.EncodeToString(digest[:]) {
+		t.Fatalf("hashKey() = %q, want the hex SHA-256 of the key", got)
+	}
+	if strings.Contains(got, key) {
+		t.Fatal("hash contains the key")
+	}
+	if hashKey(key+"d") == got {
+		t.Fatal("different keys hash the same")
+	}
+}
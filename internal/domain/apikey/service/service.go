@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	apiKeyRepository "github.com/vistara-studio/vistara-be/internal/domain/apikey/repository"
+)
+
+type apiKeyService struct {
+	repository apiKeyRepository.RepositoryItf
+	audit      audit.Recorder
+}
+
+type APIKeyServiceItf interface {
+	CreateKey(ctx context.Context, adminID string, request apikey.CreateKeyRequest) (apikey.CreateKeyResponse, error)
+	ListKeys(ctx context.Context, userID string) ([]apikey.KeyResponse, error)
+	RevokeKey(ctx context.Context, keyID string) (apikey.KeyResponse, error)
+	GetUsage(ctx context.Context, keyID string, days int) ([]apikey.UsageResponse, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (apikey.Principal, error)
+}
+
+func New(repository apiKeyRepository.RepositoryItf, recorder audit.Recorder) APIKeyServiceItf {
+	return &apiKeyService{repository: repository, audit: recorder}
+}
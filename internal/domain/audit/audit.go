@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Locals the request ID middleware sets on every request, services read them through the request context
+const (
+	RequestIDKey = "request_id"
+	ClientIPKey  = "client_ip"
+)
+
+// Tx is the transaction of the audited change, repository clients expose theirs so the event commits with it
+type Tx interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// Recorder appends entries to the audit log on the transaction of the change, so a change is never
+// committed without its event. Callers record right before committing since appends are serialised.
+type Recorder interface {
+	Record(ctx context.Context, tx Tx, entry Entry) error
+}
+
+// Actor is who performed an audited action
+type Actor struct {
+	Type ActorType
+	ID   string
+}
+
+// Change holds the value of a field before and after an action, From is empty on create and To on delete
+type Change struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Changes maps column names to their change
+type Changes map[string]Change
+
+// Entry is what callers record, the recorder adds the time, client IP, request ID and hash chain
+type Entry struct {
+	Actor      Actor
+	Action     string
+	TargetType string
+	TargetID   string
+	Changes    Changes
+}
+
+// UserActor is a signed in user
+func UserActor(userID string) Actor {
+	return Actor{Type: ActorUser, ID: userID}
+}
+
+// SystemActor is a background job or an external system such as the payment gateway
+func SystemActor(name string) Actor {
+	return Actor{Type: ActorSystem, ID: name}
+}
+
+// ActorFromContext returns the partner API key or user that authenticated the request.
+// Fiber stores locals on the fasthttp request context, which serves them through Value.
+func ActorFromContext(ctx context.Context) Actor {
+	if keyID, ok := ctx.Value("api_key_id").(string); ok && keyID != "" {
+		return Actor{Type: ActorAPIKey, ID: keyID}
+	}
+
+	userID, _ := ctx.Value("user_id").(string)
+	return UserActor(userID)
+}
+
+// RequestMeta returns the client IP and request ID of the request ctx belongs to, both empty outside requests
+func RequestMeta(ctx context.Context) (ipAddress, requestID string) {
+	ipAddress, _ = ctx.Value(ClientIPKey).(string)
+	requestID, _ = ctx.Value(RequestIDKey).(string)
+	return ipAddress, requestID
+}
+
+// ignoredFields change on every write and would only add noise
+var ignoredFields = map[string]bool{"updated_at": true}
+
+// personalFields name a user account. Changes are covered by the hash and can't be erased when the account is
+// deleted, so they're left out and the user only appears as the erasable actor.
+var personalFields = map[string]bool{"user_id": true, "owner_id": true, "uploaded_by": true}
+
+// Diff compares two values of the same struct type by their db columns and returns the columns that differ,
+// leaving out personalFields. Pass nil as before for a create and nil as after for a delete.
+func Diff(before, after interface{}) Changes {
+	beforeFields := columns(before)
+	afterFields := columns(after)
+
+	changes := Changes{}
+	for name, value := range afterFields {
+		previous, existed := beforeFields[name]
+		if existed && sameValue(previous, value) {
+			continue
+		}
+		changes[name] = Change{From: previous, To: value}
+	}
+	for name, value := range beforeFields {
+		if _, exists := afterFields[name]; !exists {
+			changes[name] = Change{From: value}
+		}
+	}
+
+	return changes
+}
+
+// columns flattens a struct, or a pointer to one, into its db tagged fields
+func columns(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if value == nil {
+		return fields
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fields
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fields
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("db")
+		if name == "" || name == "-" || ignoredFields[name] || personalFields[name] || !t.Field(i).IsExported() {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				fields[name] = nil
+				continue
+			}
+			field = field.Elem()
+		}
+		fields[name] = field.Interface()
+	}
+
+	return fields
+}
+
+// sameValue compares through JSON so times and UUIDs compare by what ends up in the log
+func sameValue(a, b interface{}) bool {
+	first, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	second, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(first, second)
+}
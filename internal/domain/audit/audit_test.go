@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// booking mirrors the columns of a tour guide booking and an uploaded photo
+type booking struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	OwnerID    *uuid.UUID `db:"owner_id"`
+	UploadedBy *uuid.UUID `db:"uploaded_by"`
+	Status     string     `db:"status"`
+	Amount     int64      `db:"amount"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
+
+func TestDiff(t *testing.T) {
+	userID := uuid.New()
+	before := booking{ID: uuid.New(), UserID: userID, OwnerID: &userID, UploadedBy: &userID, Status: "pending", Amount: 150000}
+	after := before
+	after.Status = "paid"
+	after.UpdatedAt = time.Now()
+
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   []string
+	}{
+		{name: "create", after: before, want: []string{"id", "status", "amount"}},
+		{name: "update", before: before, after: &after, want: []string{"status"}},
+		{name: "delete", before: &before, want: []string{"id", "status", "amount"}},
+		{name: "nothing changed", before: before, after: before},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(tt.before, tt.after)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("changes = %v, want the columns %v", changes, tt.want)
+			}
+			for _, name := range tt.want {
+				if _, ok := changes[name]; !ok {
+					t.Fatalf("changes = %v, want the columns %v", changes, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnonymizedEventHoldsNoUserID(t *testing.T) {
+	userID := uuid.New()
+	changes, err := json.Marshal(Diff(nil, booking{ID: uuid.New(), UserID: userID, UploadedBy: &userID, Status: "pending"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := Event{
+		OccurredAt:   time.Now(),
+		ActorType:    ActorUser,
+		ActorID:      userID.String(),
+		Action:       ActionBookingCreated,
+		TargetType:   TargetBooking,
+		TargetID:     uuid.NewString(),
+		Changes:      changes,
+		IPAddress:    "203.0.113.7",
+		PersonalSalt: "00112233445566778899aabbccddeeff",
+	}
+	event.PersonalDigest = event.ComputePersonalDigest()
+	event.Hash = event.ComputeHash()
+
+	// What deleting the account does to the events the user performed
+	event.ActorID, event.IPAddress, event.PersonalSalt = "", "", ""
+
+	if !event.Anonymized() {
+		t.Fatal("event isn't anonymized")
+	}
+	if event.ComputeHash() != event.Hash {
+		t.Fatal("erasing the personal fields broke the hash")
+	}
+
+	stored, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(stored), userID.String()) {
+		t.Fatalf("erased event still holds the user ID: %s", stored)
+	}
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventFilter narrows the audit log query, empty fields match everything.
+// Results are newest first, pass the last ID of a page as before_id to get the next one.
+type EventFilter struct {
+	ActorType  string `query:"actor_type"`
+	ActorID    string `query:"actor_id"`
+	Action     string `query:"action"`
+	TargetType string `query:"target_type"`
+	TargetID   string `query:"target_id"`
+	RequestID  string `query:"request_id"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	BeforeID   int64  `query:"before_id"`
+	Limit      int    `query:"limit"`
+}
+
+type EventResponse struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ActorType  ActorType       `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Changes    json.RawMessage `json:"changes"`
+	IPAddress  string          `json:"ip_address"`
+	RequestID  string          `json:"request_id"`
+	Hash       string          `json:"hash"`
+}
+
+type EventListResponse struct {
+	Events       []EventResponse `json:"events"`
+	NextBeforeID *int64          `json:"next_before_id"`
+}
+
+// VerifyResponse reports whether the hash chain is intact, BrokenAtID is the first event that doesn't match
+type VerifyResponse struct {
+	Valid      bool   `json:"valid"`
+	Checked    int64  `json:"checked"`
+	BrokenAtID *int64 `json:"broken_at_id,omitempty"`
+}
+
+// NewEventResponse builds the API view of an event
+func NewEventResponse(data Event) EventResponse {
+	return EventResponse{
+		ID:         data.ID,
+		OccurredAt: data.OccurredAt,
+		ActorType:  data.ActorType,
+		ActorID:    data.ActorID,
+		Action:     data.Action,
+		TargetType: data.TargetType,
+		TargetID:   data.TargetID,
+		Changes:    data.Changes,
+		IPAddress:  data.IPAddress,
+		RequestID:  data.RequestID,
+		Hash:       data.Hash,
+	}
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Event is a stored audit log entry, Hash covers every column except ID so rows can't be edited unnoticed.
+// The actor ID and IP address are covered through PersonalDigest, a salted hash of both, so they can be
+// erased when the account is deleted by clearing them and the salt without breaking the chain.
+type Event struct {
+	ID             int64           `db:"id"`
+	OccurredAt     time.Time       `db:"occurred_at"`
+	ActorType      ActorType       `db:"actor_type"`
+	ActorID        string          `db:"actor_id"`
+	Action         string          `db:"action"`
+	TargetType     string          `db:"target_type"`
+	TargetID       string          `db:"target_id"`
+	Changes        json.RawMessage `db:"changes"`
+	IPAddress      string          `db:"ip_address"`
+	RequestID      string          `db:"request_id"`
+	PersonalSalt   string          `db:"personal_salt"`
+	PersonalDigest string          `db:"personal_digest"`
+	PrevHash       string          `db:"prev_hash"`
+	Hash           string          `db:"hash"`
+}
+
+// NewPersonalSalt draws the salt of an event's personal digest
+func NewPersonalSalt() (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(salt), nil
+}
+
+// ComputePersonalDigest hashes the actor ID and IP address with the event's salt
+func (e Event) ComputePersonalDigest() string {
+	payload, _ := json.Marshal([]string{e.PersonalSalt, e.ActorID, e.IPAddress})
+
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:])
+}
+
+// Anonymized reports whether the personal fields were erased, only their digest is left
+func (e Event) Anonymized() bool {
+	return e.PersonalDigest != "" && e.PersonalSalt == ""
+}
+
+// ComputeHash chains the event to PrevHash, the first event of the log has an empty PrevHash.
+// Events written before personal digests existed hash the actor ID and IP address directly.
+func (e Event) ComputeHash() string {
+	if e.PersonalDigest != "" {
+		payload, _ := json.Marshal([]interface{}{
+			e.PrevHash,
+			e.OccurredAt.UTC().Format(time.RFC3339Nano),
+			e.ActorType,
+			e.PersonalDigest,
+			e.Action,
+			e.TargetType,
+			e.TargetID,
+			e.Changes,
+			e.RequestID,
+		})
+
+		digest := sha256.Sum256(payload)
+		return hex.EncodeToString(digest[:])
+	}
+
+	payload, _ := json.Marshal([]interface{}{
+		e.PrevHash,
+		e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		e.ActorType,
+		e.ActorID,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.Changes,
+		e.IPAddress,
+		e.RequestID,
+	})
+
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEventComputeHash(t *testing.T) {
+	base := Event{
+		OccurredAt: time.Date(2025, time.March, 1, 8, 30, 0, 0, time.UTC),
+		ActorType:  ActorUser,
+		ActorID:    "6f1c1d3e-0000-4000-8000-000000000001",
+		Action:     "user.password_changed",
+		TargetType: "user",
+		TargetID:   "6f1c1d3e-0000-4000-8000-000000000001",
+		Changes:    json.RawMessage(`{}`),
+		IPAddress:  "203.0.113.7",
+		RequestID:  "req-1",
+		PrevHash:   "abc",
+	}
+
+	salted := base
+	salted.PersonalSalt = "00112233445566778899aabbccddeeff"
+	salted.PersonalDigest = salted.ComputePersonalDigest()
+
+	anonymized := salted
+	anonymized.ActorID = ""
+	anonymized.IPAddress = ""
+	anonymized.PersonalSalt = ""
+
+	tests := []struct {
+		name     string
+		event    Event
+		modify   func(*Event)
+		wantSame bool
+	}{
+		{
+			name:     "legacy event covers the actor ID",
+			event:    base,
+			modify:   func(e *Event) { e.ActorID = "someone-else" },
+			wantSame: false,
+		},
+		{
+			name:     "legacy event covers the IP address",
+			event:    base,
+			modify:   func(e *Event) { e.IPAddress = "198.51.100.1" },
+			wantSame: false,
+		},
+		{
+			name:     "digested event survives erasing the personal fields",
+			event:    salted,
+			modify:   func(e *Event) { *e = anonymized },
+			wantSame: true,
+		},
+		{
+			name:     "digested event covers the digest",
+			event:    salted,
+			modify:   func(e *Event) { e.PersonalDigest = "0000" },
+			wantSame: false,
+		},
+		{
+			name:     "digested event covers the changes",
+			event:    salted,
+			modify:   func(e *Event) { e.Changes = json.RawMessage(`{"role":{"to":"admin"}}`) },
+			wantSame: false,
+		},
+		{
+			name:     "hash is chained to the previous one",
+			event:    salted,
+			modify:   func(e *Event) { e.PrevHash = "def" },
+			wantSame: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modified := tt.event
+			tt.modify(&modified)
+
+			if same := tt.event.ComputeHash() == modified.ComputeHash(); same != tt.wantSame {
+				t.Errorf("hash unchanged = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestEventPersonalDigest(t *testing.T) {
+	event := Event{ActorID: "user-1", IPAddress: "203.0.113.7", PersonalSalt: "salt"}
+	event.PersonalDigest = event.ComputePersonalDigest()
+
+	if event.Anonymized() {
+		t.Error("event with its salt reported as anonymized")
+	}
+
+	other := event
+	other.PersonalSalt = "other-salt"
+	if other.ComputePersonalDigest() == event.PersonalDigest {
+		t.Error("digest does not depend on the salt")
+	}
+
+	// Splitting the same characters differently between the fields must not collide
+	shifted := Event{ActorID: "user-12", IPAddress: "03.0.113.7", PersonalSalt: "salt"}
+	if shifted.ComputePersonalDigest() == event.PersonalDigest {
+		t.Error("digest collides when characters move between fields")
+	}
+
+	event.ActorID, event.IPAddress, event.PersonalSalt = "", "", ""
+	if !event.Anonymized() {
+		t.Error("erased event not reported as anonymized")
+	}
+
+	if (Event{}).Anonymized() {
+		t.Error("legacy event without a digest reported as anonymized")
+	}
+}
+
+func TestNewPersonalSalt(t *testing.T) {
+	first, err := NewPersonalSalt()
+	if err != nil {
+		t.Fatalf("NewPersonalSalt() error = %v", err)
+	}
+	second, err := NewPersonalSalt()
+	if err != nil {
+		t.Fatalf("NewPersonalSalt() error = %v", err)
+	}
+
+	if len(first) != 32 {
+		t.Errorf("salt length = %d, want 32", len(first))
+	}
+	if first == second {
+		t.Error("two salts are equal")
+	}
+}
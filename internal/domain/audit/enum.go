@@ -0,0 +1,47 @@
+package audit
+
+// ActorType tells what kind of principal performed an action
+type ActorType string
+
+const (
+	ActorUser   ActorType = "user"
+	ActorAPIKey ActorType = "api_key"
+	ActorSystem ActorType = "system"
+)
+
+// Actions recorded in the audit log, named <target>.<verb>
+const (
+	ActionLocalBusinessCreated     = "local_business.created"
+	ActionLocalBusinessUpdated     = "local_business.updated"
+	ActionLocalBusinessDeleted     = "local_business.deleted"
+	ActionTouristAttractionCreated = "tourist_attraction.created"
+	ActionTouristAttractionUpdated = "tourist_attraction.updated"
+	ActionTouristAttractionDeleted = "tourist_attraction.deleted"
+	ActionBookingCreated           = "booking.created"
+	ActionBookingStatusChanged     = "booking.status_changed"
+	ActionPasswordChanged          = "user.password_changed"
+	ActionPasswordReset            = "user.password_reset"
+	ActionRoleChanged              = "user.role_changed"
+	ActionMFAEnabled               = "user.mfa_enabled"
+	ActionMFADisabled              = "user.mfa_disabled"
+	ActionAccountDeleted           = "user.deleted"
+	ActionAPIKeyCreated            = "api_key.created"
+	ActionAPIKeyRevoked            = "api_key.revoked"
+	ActionCategoryCreated          = "category.created"
+	ActionCategoryUpdated          = "category.updated"
+	ActionCategoryDeleted          = "category.deleted"
+	ActionMediaUploaded            = "media.uploaded"
+	ActionMediaUpdated             = "media.updated"
+	ActionMediaDeleted             = "media.deleted"
+)
+
+// Target types of audited rows
+const (
+	TargetLocalBusiness     = "local_business"
+	TargetTouristAttraction = "tourist_attraction"
+	TargetBooking           = "booking"
+	TargetUser              = "user"
+	TargetAPIKey            = "api_key"
+	TargetCategory          = "category"
+	TargetMedia             = "media"
+)
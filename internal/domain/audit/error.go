@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrInvalidTimeRange = cerr.New(fiber.ErrBadRequest.Code, "from and to must be RFC 3339 timestamps", errors.New("invalid audit time range"))
+)
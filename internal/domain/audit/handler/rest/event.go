@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuditHandler) listEvents(ctx *fiber.Ctx) error {
+	var filter audit.EventFilter
+	if err := ctx.QueryParser(&filter); err != nil {
+		return err
+	}
+
+	response, err := h.service.ListEvents(ctx.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get audit events successful",
+		"payload": response,
+	})
+}
+
+func (h *AuditHandler) verifyChain(ctx *fiber.Ctx) error {
+	response, err := h.service.VerifyChain(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "audit chain verified",
+		"payload": response,
+	})
+}
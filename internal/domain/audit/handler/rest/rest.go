@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/audit/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	service    service.AuditServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+func New(service service.AuditServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *AuditHandler {
+	return &AuditHandler{service: service, validator: validator, middleware: middleware}
+}
+
+func (h *AuditHandler) Mount(router fiber.Router) {
+	adminGroup := router.Group("/admin/audit-events")
+
+	authentication := h.middleware.Authentication()
+	requireAdmin := h.middleware.RequireRole(user.RoleAdmin)
+	adminGroup.Get("/", authentication, requireAdmin, h.listEvents)
+	adminGroup.Get("/verify", authentication, requireAdmin, h.verifyChain)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+)
+
+// chainLockID is the advisory lock that serialises appends so every event links to the one before it
+const chainLockID = 7_318_462_019
+
+const eventColumns = `id, occurred_at, actor_type, actor_id, action, target_type, target_id,
+	changes, ip_address, request_id, personal_salt, personal_digest, prev_hash, hash`
+
+// LockChain holds the append lock until the transaction ends, must run on a transaction client
+func (r *auditRepository) LockChain(ctx context.Context) error {
+	_, err := r.q.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, chainLockID)
+	return err
+}
+
+// GetLastHash returns the hash of the newest event, or an empty string for an empty log
+func (r *auditRepository) GetLastHash(ctx context.Context, hash *string) error {
+	query := `SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`
+
+	if err := r.q.QueryRowxContext(ctx, query).Scan(hash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			*hash = ""
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *auditRepository) CreateEvent(ctx context.Context, data *audit.Event) error {
+	query := `INSERT INTO audit_events (
+		occurred_at, actor_type, actor_id, action, target_type, target_id,
+		changes, ip_address, request_id, personal_salt, personal_digest, prev_hash, hash
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+	) RETURNING id`
+
+	row := r.q.QueryRowxContext(ctx, query,
+		data.OccurredAt, data.ActorType, data.ActorID, data.Action, data.TargetType, data.TargetID,
+		string(data.Changes), data.IPAddress, data.RequestID, data.PersonalSalt, data.PersonalDigest, data.PrevHash, data.Hash,
+	)
+
+	return row.Scan(&data.ID)
+}
+
+// GetEvents returns the newest events matching the filter, filter.Limit must already be bounded
+func (r *auditRepository) GetEvents(ctx context.Context, filter audit.EventFilter, from, to *time.Time, out *[]audit.Event) error {
+	query := `SELECT ` + eventColumns + ` FROM audit_events
+	WHERE ($1 = '' OR actor_type = $1)
+		AND ($2 = '' OR actor_id = $2)
+		AND ($3 = '' OR action = $3)
+		AND ($4 = '' OR target_type = $4)
+		AND ($5 = '' OR target_id = $5)
+		AND ($6 = '' OR request_id = $6)
+		AND ($7::timestamp IS NULL OR occurred_at >= $7)
+		AND ($8::timestamp IS NULL OR occurred_at < $8)
+		AND ($9 = 0 OR id < $9)
+	ORDER BY id DESC
+	LIMIT $10
+	`
+
+	return r.selectEvents(ctx, out, query,
+		filter.ActorType, filter.ActorID, filter.Action, filter.TargetType, filter.TargetID, filter.RequestID,
+		from, to, filter.BeforeID, filter.Limit,
+	)
+}
+
+// GetEventsAfter returns events in chain order starting after afterID
+func (r *auditRepository) GetEventsAfter(ctx context.Context, afterID int64, limit int, out *[]audit.Event) error {
+	query := `SELECT ` + eventColumns + ` FROM audit_events
+	WHERE id > $1
+	ORDER BY id ASC
+	LIMIT $2
+	`
+
+	return r.selectEvents(ctx, out, query, afterID, limit)
+}
+
+func (r *auditRepository) selectEvents(ctx context.Context, out *[]audit.Event, query string, args ...interface{}) error {
+	rows, err := r.q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	result := []audit.Event{}
+	for rows.Next() {
+		var item audit.Event
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	errFailedToCommit   = errors.New("FAILED_TO_COMMIT_TRANSACTION")
+	errFailedToRollback = errors.New("FAILED_TO_ROLLBACK_TRANSACTION")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+type RepositoryItf interface {
+	NewClient(tx bool) (auditRepositoryItf, error)
+	WithTx(tx audit.Tx) auditRepositoryItf
+}
+
+type auditRepository struct {
+	q namedExt
+}
+
+type auditRepositoryItf interface {
+	Commit() error
+	Rollback() error
+	LockChain(ctx context.Context) error
+	GetLastHash(ctx context.Context, hash *string) error
+	CreateEvent(ctx context.Context, data *audit.Event) error
+	GetEvents(ctx context.Context, filter audit.EventFilter, from, to *time.Time, out *[]audit.Event) error
+	GetEventsAfter(ctx context.Context, afterID int64, limit int, out *[]audit.Event) error
+}
+
+type namedExt interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+func New(db *sqlx.DB) RepositoryItf {
+	return &repository{db}
+}
+
+func (r *repository) NewClient(tx bool) (auditRepositoryItf, error) {
+	var db namedExt
+
+	db = r.DB
+	if tx {
+		var err error
+		db, err = r.DB.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &auditRepository{db}, nil
+}
+
+// WithTx returns a client on a transaction opened by another repository, the caller commits it
+func (r *repository) WithTx(tx audit.Tx) auditRepositoryItf {
+	return &auditRepository{tx}
+}
+
+func (r *auditRepository) Commit() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+
+	return errFailedToCommit
+}
+
+func (r *auditRepository) Rollback() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+
+	return errFailedToRollback
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/jmoiron/sqlx"
+)
+
+// errNotInTransaction guards against recording outside a transaction, where the chain lock would not hold
+var errNotInTransaction = errors.New("audit events must be recorded on a transaction")
+
+const (
+	defaultEventLimit = 50
+	maxEventLimit     = 200
+	verifyBatchSize   = 1000
+)
+
+// Record appends the entry to the hash chain on tx, the client IP and request ID come from the request context.
+// The chain stays locked until tx ends, so it should be the last write before the commit.
+func (s *auditService) Record(ctx context.Context, tx audit.Tx, entry audit.Entry) error {
+	if _, ok := tx.(*sqlx.Tx); !ok {
+		return errNotInTransaction
+	}
+
+	changes := entry.Changes
+	if changes == nil {
+		changes = audit.Changes{}
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	salt, err := audit.NewPersonalSalt()
+	if err != nil {
+		return err
+	}
+
+	ipAddress, requestID := audit.RequestMeta(ctx)
+	event := &audit.Event{
+		// Postgres keeps microseconds, the hash has to cover what is stored
+		OccurredAt:   time.Now().UTC().Truncate(time.Microsecond),
+		ActorType:    entry.Actor.Type,
+		ActorID:      entry.Actor.ID,
+		Action:       entry.Action,
+		TargetType:   entry.TargetType,
+		TargetID:     entry.TargetID,
+		Changes:      changesJSON,
+		IPAddress:    ipAddress,
+		RequestID:    requestID,
+		PersonalSalt: salt,
+	}
+	event.PersonalDigest = event.ComputePersonalDigest()
+
+	auditRepository := s.repository.WithTx(tx)
+
+	if err := auditRepository.LockChain(ctx); err != nil {
+		return err
+	}
+
+	if err := auditRepository.GetLastHash(ctx, &event.PrevHash); err != nil {
+		return err
+	}
+
+	event.Hash = event.ComputeHash()
+	if err := auditRepository.CreateEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to record audit event %s: %w", entry.Action, err)
+	}
+
+	return nil
+}
+
+func (s *auditService) ListEvents(ctx context.Context, filter audit.EventFilter) (audit.EventListResponse, error) {
+	from, err := parseTime(filter.From)
+	if err != nil {
+		return audit.EventListResponse{}, err
+	}
+	to, err := parseTime(filter.To)
+	if err != nil {
+		return audit.EventListResponse{}, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEventLimit
+	}
+	if filter.Limit > maxEventLimit {
+		filter.Limit = maxEventLimit
+	}
+
+	auditRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return audit.EventListResponse{}, err
+	}
+
+	var events []audit.Event
+	if err := auditRepository.GetEvents(ctx, filter, from, to, &events); err != nil {
+		return audit.EventListResponse{}, err
+	}
+
+	response := audit.EventListResponse{Events: make([]audit.EventResponse, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, audit.NewEventResponse(event))
+	}
+
+	if len(events) == filter.Limit {
+		next := events[len(events)-1].ID
+		response.NextBeforeID = &next
+	}
+
+	return response, nil
+}
+
+// VerifyChain recomputes every hash from the first event, any edited, removed or reordered row breaks the chain
+func (s *auditService) VerifyChain(ctx context.Context) (audit.VerifyResponse, error) {
+	auditRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return audit.VerifyResponse{}, err
+	}
+
+	response := audit.VerifyResponse{Valid: true}
+	previousHash := ""
+	var lastID int64
+
+	for {
+		var events []audit.Event
+		if err := auditRepository.GetEventsAfter(ctx, lastID, verifyBatchSize, &events); err != nil {
+			return audit.VerifyResponse{}, err
+		}
+
+		for _, event := range events {
+			// Erased events only keep the digest of their personal fields, which the hash still covers
+			personalValid := event.PersonalDigest == "" || event.Anonymized() || event.ComputePersonalDigest() == event.PersonalDigest
+			if event.PrevHash != previousHash || !personalValid || event.ComputeHash() != event.Hash {
+				brokenAt := event.ID
+				response.Valid = false
+				response.BrokenAtID = &brokenAt
+				return response, nil
+			}
+
+			response.Checked++
+			previousHash = event.Hash
+			lastID = event.ID
+		}
+
+		if len(events) < verifyBatchSize {
+			return response, nil
+		}
+	}
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, audit.ErrInvalidTimeRange
+	}
+
+	// occurred_at is stored in UTC
+	parsed = parsed.UTC()
+	return &parsed, nil
+}
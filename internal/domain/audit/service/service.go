@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	auditRepository "github.com/vistara-studio/vistara-be/internal/domain/audit/repository"
+)
+
+type auditService struct {
+	repository auditRepository.RepositoryItf
+}
+
+type AuditServiceItf interface {
+	audit.Recorder
+	ListEvents(ctx context.Context, filter audit.EventFilter) (audit.EventListResponse, error)
+	VerifyChain(ctx context.Context) (audit.VerifyResponse, error)
+}
+
+func New(repository auditRepository.RepositoryItf) AuditServiceItf {
+	return &auditService{repository: repository}
+}
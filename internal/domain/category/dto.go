@@ -0,0 +1,55 @@
+package category
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCategoryRequest struct {
+	ParentID *uuid.UUID        `json:"parent_id"`
+	Slug     string            `json:"slug" validate:"required,min=2,max=50"`
+	Names    map[string]string `json:"names" validate:"required,min=1,max=10,dive,keys,len=2,endkeys,required,max=100"`
+	Icon     string            `json:"icon" validate:"max=255"`
+	Position int               `json:"position" validate:"min=0"`
+}
+
+// UpdateCategoryRequest changes the given fields, parent_id "" moves the category to the top level
+type UpdateCategoryRequest struct {
+	ParentID *string           `json:"parent_id"`
+	Slug     *string           `json:"slug" validate:"omitempty,min=2,max=50"`
+	Names    map[string]string `json:"names" validate:"omitempty,max=10,dive,keys,len=2,endkeys,required,max=100"`
+	Icon     *string           `json:"icon" validate:"omitempty,max=255"`
+	Position *int              `json:"position" validate:"omitempty,min=0"`
+}
+
+// ListCategoriesQuery picks the locale of name, the default locale when empty or missing
+type ListCategoriesQuery struct {
+	Lang string `query:"lang" validate:"omitempty,len=2"`
+}
+
+type CategoryResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	ParentID  *uuid.UUID         `json:"parent_id"`
+	Slug      string             `json:"slug"`
+	Name      string             `json:"name"`
+	Names     LocalizedNames     `json:"names"`
+	Icon      string             `json:"icon"`
+	Position  int                `json:"position"`
+	CreatedAt time.Time          `json:"created_at"`
+	Children  []CategoryResponse `json:"children,omitempty"`
+}
+
+// NewCategoryResponse builds the view of a category with its name in locale
+func NewCategoryResponse(data Category, locale string) CategoryResponse {
+	return CategoryResponse{
+		ID:        data.ID,
+		ParentID:  data.ParentID,
+		Slug:      data.Slug,
+		Name:      data.Names.In(locale),
+		Names:     data.Names,
+		Icon:      data.Icon,
+		Position:  data.Position,
+		CreatedAt: data.CreatedAt,
+	}
+}
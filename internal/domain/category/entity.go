@@ -0,0 +1,64 @@
+package category
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalizedNames maps a locale such as "id" or "en" to a name, stored as a JSONB object
+type LocalizedNames map[string]string
+
+// Value encodes the names for a JSONB column
+func (n LocalizedNames) Value() (driver.Value, error) {
+	if n == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(n))
+}
+
+// Scan decodes a JSONB column into the names
+func (n *LocalizedNames) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	case nil:
+		*n = LocalizedNames{}
+		return nil
+	default:
+		return errors.New("localized names must be a JSON object")
+	}
+
+	names := LocalizedNames{}
+	if err := json.Unmarshal(raw, &names); err != nil {
+		return err
+	}
+	*n = names
+	return nil
+}
+
+// In returns the name in locale, falling back to the default locale
+func (n LocalizedNames) In(locale string) string {
+	if name, ok := n[locale]; ok && name != "" {
+		return name
+	}
+	return n[DefaultLocale]
+}
+
+// Category is a node of the taxonomy, top level categories have no parent
+type Category struct {
+	ID        uuid.UUID      `db:"id"`
+	ParentID  *uuid.UUID     `db:"parent_id"`
+	Slug      string         `db:"slug"`
+	Names     LocalizedNames `db:"names"`
+	Icon      string         `db:"icon"`
+	Position  int            `db:"position"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
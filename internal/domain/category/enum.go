@@ -0,0 +1,9 @@
+package category
+
+import "regexp"
+
+// DefaultLocale is the locale every category has a name in and the fallback for missing translations
+const DefaultLocale = "id"
+
+// SlugPattern allows lower case words joined by hyphens, such as "masakan-padang"
+var SlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
@@ -0,0 +1,18 @@
+package category
+
+import (
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrCategoryNotFound    = cerr.New(fiber.ErrNotFound.Code, "category not found", errors.New("category not found"))
+	ErrParentNotFound      = cerr.New(fiber.ErrBadRequest.Code, "parent category not found", errors.New("parent category not found"))
+	ErrSlugTaken           = cerr.New(fiber.ErrConflict.Code, "a category with this slug already exists", errors.New("category slug taken"))
+	ErrInvalidSlug         = cerr.New(fiber.ErrBadRequest.Code, "slug must be lower case letters and digits joined by hyphens", errors.New("invalid category slug"))
+	ErrMissingDefaultName  = cerr.New(fiber.ErrBadRequest.Code, "names must include an \"id\" name", errors.New("category default name missing"))
+	ErrCategoryCycle       = cerr.New(fiber.ErrBadRequest.Code, "a category can't be moved under itself or one of its subcategories", errors.New("category cycle"))
+	ErrCategoryHasChildren = cerr.New(fiber.ErrConflict.Code, "delete or move the subcategories first", errors.New("category has subcategories"))
+)
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	"github.com/gofiber/fiber/v2"
+)
+
+// listCategories returns the taxonomy tree, ?lang= picks the locale of each name
+func (h *CategoryHandler) listCategories(ctx *fiber.Ctx) error {
+	var query category.ListCategoriesQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return err
+	}
+
+	response, err := h.service.ListCategories(ctx.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get categories successful",
+		"payload": response,
+	})
+}
+
+func (h *CategoryHandler) createCategory(ctx *fiber.Ctx) error {
+	var request category.CreateCategoryRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.CreateCategory(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "create category successful",
+		"payload": response,
+	})
+}
+
+func (h *CategoryHandler) updateCategory(ctx *fiber.Ctx) error {
+	var request category.UpdateCategoryRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateCategory(ctx.Context(), ctx.Params("categoryID"), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "update category successful",
+		"payload": response,
+	})
+}
+
+func (h *CategoryHandler) deleteCategory(ctx *fiber.Ctx) error {
+	if err := h.service.DeleteCategory(ctx.Context(), ctx.Params("categoryID")); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "delete category successful",
+	})
+}
@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/category/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	service    service.CategoryServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+func New(service service.CategoryServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *CategoryHandler {
+	return &CategoryHandler{service: service, validator: validator, middleware: middleware}
+}
+
+func (h *CategoryHandler) Mount(router fiber.Router) {
+	router.Get("/categories", h.middleware.AuthenticationOrAPIKey(apikey.ScopeReadCatalogue), h.listCategories)
+
+	adminGroup := router.Group("/admin/categories")
+
+	authentication := h.middleware.Authentication()
+	requireAdmin := h.middleware.RequireRole(user.RoleAdmin)
+	adminGroup.Post("/", authentication, requireAdmin, h.createCategory)
+	adminGroup.Put("/:categoryID", authentication, requireAdmin, h.updateCategory)
+	adminGroup.Delete("/:categoryID", authentication, requireAdmin, h.deleteCategory)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const categoryColumns = `id, parent_id, slug, names, icon, position, created_at, updated_at`
+
+// categoryError turns constraint violations into the errors callers can act on
+func categoryError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		return category.ErrSlugTaken
+	case "foreign_key_violation":
+		return category.ErrParentNotFound
+	}
+
+	return err
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, data category.Category) error {
+	query := `INSERT INTO categories (
+		id, parent_id, slug, names, icon, position, created_at, updated_at
+	) VALUES (
+		:id, :parent_id, :slug, :names, :icon, :position, :created_at, :updated_at
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return nil
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, data *category.Category) error {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	row := r.q.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category.ErrCategoryNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetCategories lists the whole taxonomy by position then slug, callers group it by parent and siblings keep that order
+func (r *categoryRepository) GetCategories(ctx context.Context, out *[]category.Category) error {
+	query := `SELECT ` + categoryColumns + ` FROM categories
+	ORDER BY position, slug
+	`
+
+	rows, err := r.q.QueryxContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	result := []category.Category{}
+	for rows.Next() {
+		var item category.Category
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
+
+// LockTree serialises moves within the category tree until the transaction ends, so two concurrent
+// re-parents can't each pass the cycle check and create a cycle together
+func (r *categoryRepository) LockTree(ctx context.Context) error {
+	_, err := r.q.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('categories:tree'))`)
+	return err
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, data *category.Category) error {
+	query := `UPDATE categories SET
+		parent_id = :parent_id,
+		slug = :slug,
+		names = :names,
+		icon = :icon,
+		position = :position,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	result, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return category.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// DeleteCategory removes a category without subcategories, its listing links go with it
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	result, err := r.q.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+			return category.ErrCategoryHasChildren
+		}
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return category.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// IsDescendant reports whether id is ancestorID itself or one of its subcategories at any depth
+func (r *categoryRepository) IsDescendant(ctx context.Context, ancestorID, id uuid.UUID, out *bool) error {
+	query := `WITH RECURSIVE tree AS (
+		SELECT id FROM categories WHERE id = $1
+		UNION
+		SELECT c.id FROM categories c INNER JOIN tree ON c.parent_id = tree.id
+	)
+	SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)
+	`
+
+	return r.q.QueryRowxContext(ctx, query, ancestorID, id).Scan(out)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	errFailedToCommit   = errors.New("FAILED_TO_COMMIT_TRANSACTION")
+	errFailedToRollback = errors.New("FAILED_TO_ROLLBACK_TRANSACTION")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+type RepositoryItf interface {
+	NewClient(tx bool) (categoryRepositoryItf, error)
+}
+
+type categoryRepository struct {
+	q namedExt
+}
+
+type categoryRepositoryItf interface {
+	Commit() error
+	Rollback() error
+	AuditTx() audit.Tx
+	CreateCategory(ctx context.Context, data category.Category) error
+	GetCategoryByID(ctx context.Context, data *category.Category) error
+	GetCategories(ctx context.Context, out *[]category.Category) error
+	UpdateCategory(ctx context.Context, data *category.Category) error
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	LockTree(ctx context.Context) error
+	IsDescendant(ctx context.Context, ancestorID, id uuid.UUID, out *bool) error
+}
+
+type namedExt interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+func New(db *sqlx.DB) RepositoryItf {
+	return &repository{db}
+}
+
+func (r *repository) NewClient(tx bool) (categoryRepositoryItf, error) {
+	var db namedExt
+
+	db = r.DB
+	if tx {
+		var err error
+		db, err = r.DB.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &categoryRepository{db}, nil
+}
+
+func (r *categoryRepository) Commit() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+
+	return errFailedToCommit
+}
+
+func (r *categoryRepository) Rollback() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+
+	return errFailedToRollback
+}
+
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (r *categoryRepository) AuditTx() audit.Tx {
+	return r.q
+}
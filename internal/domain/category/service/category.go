@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	"github.com/google/uuid"
+)
+
+// ListCategories returns the taxonomy as a tree of top level categories with their subcategories
+func (s *categoryService) ListCategories(ctx context.Context, query category.ListCategoriesQuery) ([]category.CategoryResponse, error) {
+	locale := query.Lang
+	if locale == "" {
+		locale = category.DefaultLocale
+	}
+
+	categoryRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []category.Category
+	if err := categoryRepository.GetCategories(ctx, &categories); err != nil {
+		return nil, err
+	}
+
+	children := map[uuid.UUID][]category.Category{}
+	roots := []category.Category{}
+	for _, item := range categories {
+		if item.ParentID == nil {
+			roots = append(roots, item)
+			continue
+		}
+		children[*item.ParentID] = append(children[*item.ParentID], item)
+	}
+
+	var build func(items []category.Category) []category.CategoryResponse
+	build = func(items []category.Category) []category.CategoryResponse {
+		response := make([]category.CategoryResponse, 0, len(items))
+		for _, item := range items {
+			node := category.NewCategoryResponse(item, locale)
+			node.Children = build(children[item.ID])
+			response = append(response, node)
+		}
+		return response
+	}
+
+	return build(roots), nil
+}
+
+// validateNames requires a name in the default locale
+func validateNames(names map[string]string) error {
+	if names[category.DefaultLocale] == "" {
+		return category.ErrMissingDefaultName
+	}
+	return nil
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, request category.CreateCategoryRequest) (response category.CategoryResponse, err error) {
+	if !category.SlugPattern.MatchString(request.Slug) {
+		return category.CategoryResponse{}, category.ErrInvalidSlug
+	}
+	if err := validateNames(request.Names); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	now := time.Now()
+	data := category.Category{
+		ID:        id,
+		ParentID:  request.ParentID,
+		Slug:      request.Slug,
+		Names:     request.Names,
+		Icon:      request.Icon,
+		Position:  request.Position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	categoryRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = categoryRepository.Rollback()
+		}
+	}()
+
+	if err = categoryRepository.CreateCategory(ctx, data); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, categoryRepository.AuditTx(), audit.Entry{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     audit.ActionCategoryCreated,
+		TargetType: audit.TargetCategory,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(nil, data),
+	})
+	if err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	if err = categoryRepository.Commit(); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	return category.NewCategoryResponse(data, category.DefaultLocale), nil
+}
+
+// UpdateCategory changes the given fields, moving a category under itself or one of its subcategories is refused
+func (s *categoryService) UpdateCategory(ctx context.Context, categoryID string, request category.UpdateCategoryRequest) (response category.CategoryResponse, err error) {
+	id, err := uuid.Parse(categoryID)
+	if err != nil {
+		return category.CategoryResponse{}, category.ErrCategoryNotFound
+	}
+
+	categoryRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = categoryRepository.Rollback()
+		}
+	}()
+
+	// Moves are checked for cycles one at a time, the lock is held until commit
+	if request.ParentID != nil {
+		if err = categoryRepository.LockTree(ctx); err != nil {
+			return category.CategoryResponse{}, err
+		}
+	}
+
+	data := &category.Category{ID: id}
+	if err = categoryRepository.GetCategoryByID(ctx, data); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	before := *data
+
+	if request.ParentID != nil {
+		data.ParentID = nil
+		if *request.ParentID != "" {
+			parentID, parseErr := uuid.Parse(*request.ParentID)
+			if parseErr != nil {
+				err = category.ErrParentNotFound
+				return category.CategoryResponse{}, err
+			}
+
+			var cycle bool
+			if err = categoryRepository.IsDescendant(ctx, id, parentID, &cycle); err != nil {
+				return category.CategoryResponse{}, err
+			}
+			if cycle {
+				err = category.ErrCategoryCycle
+				return category.CategoryResponse{}, err
+			}
+			data.ParentID = &parentID
+		}
+	}
+	if request.Slug != nil {
+		if !category.SlugPattern.MatchString(*request.Slug) {
+			err = category.ErrInvalidSlug
+			return category.CategoryResponse{}, err
+		}
+		data.Slug = *request.Slug
+	}
+	if request.Names != nil {
+		if err = validateNames(request.Names); err != nil {
+			return category.CategoryResponse{}, err
+		}
+		data.Names = request.Names
+	}
+	if request.Icon != nil {
+		data.Icon = *request.Icon
+	}
+	if request.Position != nil {
+		data.Position = *request.Position
+	}
+	data.UpdatedAt = time.Now()
+
+	if err = categoryRepository.UpdateCategory(ctx, data); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, categoryRepository.AuditTx(), audit.Entry{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     audit.ActionCategoryUpdated,
+		TargetType: audit.TargetCategory,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(before, data),
+	})
+	if err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	if err = categoryRepository.Commit(); err != nil {
+		return category.CategoryResponse{}, err
+	}
+
+	return category.NewCategoryResponse(*data, category.DefaultLocale), nil
+}
+
+// DeleteCategory removes a category without subcategories and unlinks it from every listing
+func (s *categoryService) DeleteCategory(ctx context.Context, categoryID string) (err error) {
+	id, err := uuid.Parse(categoryID)
+	if err != nil {
+		return category.ErrCategoryNotFound
+	}
+
+	categoryRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = categoryRepository.Rollback()
+		}
+	}()
+
+	data := &category.Category{ID: id}
+	if err = categoryRepository.GetCategoryByID(ctx, data); err != nil {
+		return err
+	}
+
+	if err = categoryRepository.DeleteCategory(ctx, id); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, categoryRepository.AuditTx(), audit.Entry{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     audit.ActionCategoryDeleted,
+		TargetType: audit.TargetCategory,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(data, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	return categoryRepository.Commit()
+}
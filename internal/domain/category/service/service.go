@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	categoryRepository "github.com/vistara-studio/vistara-be/internal/domain/category/repository"
+)
+
+type categoryService struct {
+	repository categoryRepository.RepositoryItf
+	audit      audit.Recorder
+}
+
+type CategoryServiceItf interface {
+	ListCategories(ctx context.Context, query category.ListCategoriesQuery) ([]category.CategoryResponse, error)
+	CreateCategory(ctx context.Context, request category.CreateCategoryRequest) (category.CategoryResponse, error)
+	UpdateCategory(ctx context.Context, categoryID string, request category.UpdateCategoryRequest) (category.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, categoryID string) error
+}
+
+func New(repository categoryRepository.RepositoryItf, recorder audit.Recorder) CategoryServiceItf {
+	return &categoryService{repository: repository, audit: recorder}
+}
@@ -1,9 +1,15 @@
 package local
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
 )
 
 type ResponseGetLocalBusinesses struct {
@@ -13,14 +19,99 @@ type ResponseGetLocalBusinesses struct {
 	Address     string            `json:"address"`
 	City        string            `json:"city"`
 	Province    string            `json:"province"`
-	Longitude   string            `json:"longitude"`
-	Latitude    string            `json:"latitude"`
+	Longitude   float64           `json:"longitude"`
+	Latitude    float64           `json:"latitude"`
 	Label       string            `json:"label"`
 	OpenedTime  string            `json:"opened_time"`
 	PhotoUrl    string            `json:"photo_url"`
 	IsBusiness  bool              `json:"is_business"`
 	CreatedAt   time.Time         `json:"created_at"`
+	Rating      float64           `json:"rating"`
 	Reviews     []ResponseReviews `json:"reviews,omitempty"`
+
+	Categories []ResponseListingCategory `json:"categories,omitempty"`
+
+	// OpenNow and NextChangeAt are null when the business has no opening hours
+	Timezone     string                `json:"timezone"`
+	OpenNow      *bool                 `json:"open_now"`
+	NextChangeAt *time.Time            `json:"next_change_at"`
+	OpeningHours *ResponseOpeningHours `json:"opening_hours,omitempty"`
+}
+
+// RequestOpeningInterval is an opening period in HH:MM, a closing time before the opening time runs past midnight
+type RequestOpeningInterval struct {
+	Opens  string `json:"opens" validate:"required"`
+	Closes string `json:"closes" validate:"required"`
+}
+
+type RequestWeeklyInterval struct {
+	Day string `json:"day" validate:"required,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	RequestOpeningInterval
+}
+
+// RequestOpeningHours replaces the weekly schedule of a local business
+type RequestOpeningHours struct {
+	Weekly                 []RequestWeeklyInterval `json:"weekly" validate:"max=70,dive"`
+	ClosedOnPublicHolidays bool                    `json:"closed_on_public_holidays"`
+}
+
+// RequestOpeningException sets the hours of one date, no intervals closes the whole day
+type RequestOpeningException struct {
+	Intervals []RequestOpeningInterval `json:"intervals" validate:"max=10,dive"`
+	Note      string                   `json:"note" validate:"max=200"`
+}
+
+type RequestPublicHoliday struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+type ResponseOpeningInterval struct {
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+}
+
+type ResponseWeeklyInterval struct {
+	Day string `json:"day"`
+	ResponseOpeningInterval
+}
+
+type ResponseOpeningException struct {
+	Date      string                    `json:"date"`
+	Closed    bool                      `json:"closed"`
+	Intervals []ResponseOpeningInterval `json:"intervals"`
+	Note      string                    `json:"note,omitempty"`
+}
+
+// ResponseOpeningHours is the weekly schedule of a local business with its upcoming exceptions and public holidays
+type ResponseOpeningHours struct {
+	Timezone               string                     `json:"timezone"`
+	Weekly                 []ResponseWeeklyInterval   `json:"weekly"`
+	ClosedOnPublicHolidays bool                       `json:"closed_on_public_holidays"`
+	Exceptions             []ResponseOpeningException `json:"exceptions"`
+	PublicHolidays         []ResponsePublicHoliday    `json:"public_holidays,omitempty"`
+}
+
+type ResponsePublicHoliday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// ResponseOpeningHoursImport reports how many free-text opening hours were converted and which could not be understood
+type ResponseOpeningHoursImport struct {
+	Imported int                                 `json:"imported"`
+	Failed   []ResponseOpeningHoursImportFailure `json:"failed"`
+}
+
+type ResponseOpeningHoursImportFailure struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	OpenedTime string    `json:"opened_time"`
+	Error      string    `json:"error"`
+}
+
+// QueryParamRequestPublicHolidays lists the holidays of a year, the current one by default
+type QueryParamRequestPublicHolidays struct {
+	Year int `query:"year" validate:"omitempty,min=2000,max=2100"`
 }
 
 type ResponseGetTourGuide struct {
@@ -39,7 +130,24 @@ type ResponseGetTourGuide struct {
 	Price                       int64             `json:"price"`
 	DiscountPercentage          float32           `json:"discount_percentage"`
 	CreatedAt                   time.Time         `json:"created_at"`
+	Rating                      float64           `json:"rating"`
 	Reviews                     []ResponseReviews `json:"reviews,omitempty"`
+
+	Categories []ResponseListingCategory `json:"categories,omitempty"`
+}
+
+// ResponseListingCategory is a category a listing is filed under, names are keyed by locale
+type ResponseListingCategory struct {
+	ID       uuid.UUID               `json:"id"`
+	ParentID *uuid.UUID              `json:"parent_id"`
+	Slug     string                  `json:"slug"`
+	Names    category.LocalizedNames `json:"names"`
+	Icon     string                  `json:"icon"`
+}
+
+// RequestSetCategories replaces the categories of a listing, an empty list removes them all
+type RequestSetCategories struct {
+	CategoryIDs []uuid.UUID `json:"category_ids" validate:"max=20"`
 }
 
 type ResponseReviews struct {
@@ -50,9 +158,129 @@ type ResponseReviews struct {
 	PhotoURL  string    `json:"photo_url"`
 }
 
+// QueryParamRequestGetLocals filters and pages the local business catalogue
 type QueryParamRequestGetLocals struct {
-	City string
-	Type string
+	City     string `query:"city"`
+	Type     string `query:"type" validate:"omitempty,oneof=business individual all"`
+	Province string `query:"province"`
+	Label    string `query:"label"`
+	Category string `query:"category"`
+	OpenAt   string `query:"open_at"`
+	Sort     string `query:"sort"`
+	Order    string `query:"order"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit"`
+
+	// After is the decoded cursor and OpenAtTime the parsed open_at, both set by the service
+	After      *pagination.Cursor `query:"-"`
+	OpenAtTime *time.Time         `query:"-"`
+}
+
+// QueryParamRequestGetTouristAttractions filters and pages the tourist attraction catalogue.
+// Prices are the entrance price, HasDiscount matches an entrance or tour guide discount.
+type QueryParamRequestGetTouristAttractions struct {
+	City        string `query:"city"`
+	Province    string `query:"province"`
+	MinPrice    *int64 `query:"min_price"`
+	MaxPrice    *int64 `query:"max_price"`
+	HasDiscount *bool  `query:"has_discount"`
+	Category    string `query:"category"`
+	Sort        string `query:"sort"`
+	Order       string `query:"order"`
+	Cursor      string `query:"cursor"`
+	Limit       int    `query:"limit"`
+
+	// After is the decoded cursor, set by the service
+	After *pagination.Cursor `query:"-"`
+}
+
+// QueryParamRequestNearby locates listings around a point, Radius is in metres and Category a category slug
+type QueryParamRequestNearby struct {
+	Latitude  *float64 `query:"lat" validate:"required,latitude"`
+	Longitude *float64 `query:"lng" validate:"required,longitude"`
+	Radius    float64  `query:"radius" validate:"omitempty,gt=0,max=50000"`
+	Category  string   `query:"category"`
+	Cursor    string   `query:"cursor"`
+	Limit     int      `query:"limit"`
+
+	// After is the decoded cursor, set by the service
+	After *pagination.Cursor `query:"-"`
+}
+
+// ResponseNearbyLocalBusiness is a local business with its distance from the searched point in metres
+type ResponseNearbyLocalBusiness struct {
+	ResponseGetLocalBusinesses
+	Distance float64 `json:"distance"`
+}
+
+// ResponseNearbyTouristAttraction is a tourist attraction with its distance from the searched point in metres
+type ResponseNearbyTouristAttraction struct {
+	ResponseGetTourGuide
+	Distance float64 `json:"distance"`
+}
+
+// QueryParamRequestSearch is a full-text search over the catalogue, Type, Province and Label narrow the matches
+type QueryParamRequestSearch struct {
+	Query    string `query:"q" validate:"required,min=2,max=200"`
+	Type     string `query:"type" validate:"omitempty,oneof=local_business tourist_attraction"`
+	Province string `query:"province"`
+	Label    string `query:"label"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit"`
+
+	// After is the decoded cursor, set by the service
+	After *pagination.Cursor `query:"-"`
+}
+
+// ResponseSearchResult is a search match, highlights wrap matched words in <mark> with the rest HTML escaped
+type ResponseSearchResult struct {
+	Type          ListingType `json:"type"`
+	ID            uuid.UUID   `json:"id"`
+	Name          string      `json:"name"`
+	NameHighlight string      `json:"name_highlight"`
+	Snippet       string      `json:"snippet"`
+	City          string      `json:"city"`
+	Province      string      `json:"province"`
+	Label         string      `json:"label,omitempty"`
+	PhotoUrl      string      `json:"photo_url"`
+	Score         float64     `json:"score"`
+}
+
+type ResponseFacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ResponseSearchFacets counts every match, not only the current page, by listing type, province and label
+type ResponseSearchFacets struct {
+	Types     []ResponseFacetCount `json:"type"`
+	Provinces []ResponseFacetCount `json:"province"`
+	Labels    []ResponseFacetCount `json:"label"`
+}
+
+// ResponseSearch is a page of search results, facets are only computed for the first page
+type ResponseSearch struct {
+	pagination.Page[ResponseSearchResult]
+	Facets *ResponseSearchFacets `json:"facets,omitempty"`
+}
+
+// Coordinate is a latitude or longitude in degrees. Older clients send local business coordinates
+// as strings, so a numeric string is accepted as well as a number.
+type Coordinate float64
+
+func (c *Coordinate) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.Trim(string(data), `"`)), 64)
+	if err != nil {
+		return errors.New("coordinate must be a number")
+	}
+
+	*c = Coordinate(value)
+	return nil
+}
+
+// Degrees rounds the coordinate to the 6 decimals the database keeps, about 11 cm
+func (c Coordinate) Degrees() float64 {
+	return math.Round(float64(c)*1e6) / 1e6
 }
 
 // RequestCreateLocalBusiness represents the request body for creating a new local business
@@ -62,12 +290,15 @@ type RequestCreateLocalBusiness struct {
 	Address     string  `json:"address" validate:"required,min=10,max=200"`
 	City        string  `json:"city" validate:"required,min=2,max=50"`
 	Province    string  `json:"province" validate:"required,min=2,max=50"`
-	Longitude   string  `json:"longitude" validate:"required"`
-	Latitude    string  `json:"latitude" validate:"required"`
+	Longitude   *Coordinate `json:"longitude" validate:"required,longitude"`
+	Latitude    *Coordinate `json:"latitude" validate:"required,latitude"`
 	Label       string  `json:"label" validate:"required,min=2,max=50"`
-	OpenedTime  string  `json:"opened_time" validate:"required"`
+	OpenedTime  string  `json:"opened_time" validate:"omitempty,max=200"`
 	PhotoUrl    string  `json:"photo_url" validate:"required,url"`
 	IsBusiness  bool    `json:"is_business"`
+
+	// OpeningHours is the structured schedule, opened_time is only kept as free text for display
+	OpeningHours *RequestOpeningHours `json:"opening_hours,omitempty"`
 }
 
 // RequestUpdateLocalBusiness represents the request body for updating a local business
@@ -77,8 +308,8 @@ type RequestUpdateLocalBusiness struct {
 	Address     *string `json:"address,omitempty" validate:"omitempty,min=10,max=200"`
 	City        *string `json:"city,omitempty" validate:"omitempty,min=2,max=50"`
 	Province    *string `json:"province,omitempty" validate:"omitempty,min=2,max=50"`
-	Longitude   *string `json:"longitude,omitempty"`
-	Latitude    *string `json:"latitude,omitempty"`
+	Longitude   *Coordinate `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	Latitude    *Coordinate `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	Label       *string `json:"label,omitempty" validate:"omitempty,min=2,max=50"`
 	OpenedTime  *string `json:"opened_time,omitempty"`
 	PhotoUrl    *string `json:"photo_url,omitempty" validate:"omitempty,url"`
@@ -92,8 +323,8 @@ type RequestCreateTouristAttraction struct {
 	Address                      string  `json:"address" validate:"required,min=10,max=200"`
 	City                         string  `json:"city" validate:"required,min=2,max=50"`
 	Province                     string  `json:"province" validate:"required,min=2,max=50"`
-	Longitude                    float64 `json:"longitude" validate:"required"`
-	Latitude                     float64 `json:"latitude" validate:"required"`
+	Longitude                    *Coordinate `json:"longitude" validate:"required,longitude"`
+	Latitude                     *Coordinate `json:"latitude" validate:"required,latitude"`
 	PhotoUrl                     string  `json:"photo_url" validate:"required,url"`
 	TourGuidePrice               int64   `json:"tour_guide_price" validate:"required,min=0"`
 	TourGuideCount               int     `json:"tour_guide_count" validate:"required,min=1"`
@@ -109,8 +340,8 @@ type RequestUpdateTouristAttraction struct {
 	Address                      *string  `json:"address,omitempty" validate:"omitempty,min=10,max=200"`
 	City                         *string  `json:"city,omitempty" validate:"omitempty,min=2,max=50"`
 	Province                     *string  `json:"province,omitempty" validate:"omitempty,min=2,max=50"`
-	Longitude                    *float64 `json:"longitude,omitempty"`
-	Latitude                     *float64 `json:"latitude,omitempty"`
+	Longitude                    *Coordinate `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	Latitude                     *Coordinate `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	PhotoUrl                     *string  `json:"photo_url,omitempty" validate:"omitempty,url"`
 	TourGuidePrice               *int64   `json:"tour_guide_price,omitempty" validate:"omitempty,min=0"`
 	TourGuideCount               *int     `json:"tour_guide_count,omitempty" validate:"omitempty,min=1"`
@@ -129,3 +360,17 @@ type ResponseGenerateSnapLink struct {
 	TAID       string `json:"ta_id"`
 	PaymentUrl string `json:"payment_url"`
 }
+
+// Actor identifies the user performing a catalogue write, used for ownership checks
+type Actor struct {
+	UserID  uuid.UUID
+	IsAdmin bool
+}
+
+type ResponseBookingStatusHistory struct {
+	FromStatus BookingStatus `json:"from_status,omitempty"`
+	ToStatus   BookingStatus `json:"to_status"`
+	Actor      string        `json:"actor"`
+	Reason     string        `json:"reason"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
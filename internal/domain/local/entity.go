@@ -4,58 +4,133 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/category"
 )
 
 type TourGuideBookings struct {
-	ID                   uuid.UUID `db:"id"`
-	PaymentURL           string    `db:"payment_url"`
-	Star                 int       `db:"star"`
-	Content              string    `db:"content"`
-	BookedAt             time.Time `db:"booked_at"`
-	CreatedAt            time.Time `db:"created_at"`
-	UpdatedAt            time.Time `db:"updated_at"`
-	Status               string    `db:"status"`
-	UserID               uuid.UUID `db:"user_id"`
-	TouristAttractionsID uuid.UUID `db:"tourist_attraction_id"`
-	PhotoURL             string    `db:"photo_url"`
+	ID                   uuid.UUID     `db:"id"`
+	PaymentURL           string        `db:"payment_url"`
+	Star                 int           `db:"star"`
+	Content              string        `db:"content"`
+	BookedAt             time.Time     `db:"booked_at"`
+	ExpiresAt            *time.Time    `db:"expires_at"`
+	CreatedAt            time.Time     `db:"created_at"`
+	UpdatedAt            time.Time     `db:"updated_at"`
+	Status               BookingStatus `db:"status"`
+	Amount               int64         `db:"amount"`
+	UserID               uuid.UUID     `db:"user_id"`
+	TouristAttractionsID uuid.UUID     `db:"tourist_attraction_id"`
+	PhotoURL             string        `db:"photo_url"`
+}
+
+type BookingStatusHistory struct {
+	ID         uuid.UUID     `db:"id"`
+	BookingID  uuid.UUID     `db:"booking_id"`
+	FromStatus BookingStatus `db:"from_status"`
+	ToStatus   BookingStatus `db:"to_status"`
+	Actor      string        `db:"actor"`
+	Reason     string        `db:"reason"`
+	CreatedAt  time.Time     `db:"created_at"`
 }
 
 type TouristAttractions struct {
-	ID                          uuid.UUID `db:"id"`
-	Name                        string    `db:"name"`
-	Description                 string    `db:"description"`
-	Address                     string    `db:"address"`
-	City                        string    `db:"city"`
-	Province                    string    `db:"province"`
-	Longitude                   float64   `db:"longitude"`
-	Latitude                    float64   `db:"latitude"`
-	PhotoURL                    string    `db:"photo_url"`
-	TourGuidePrice              int64     `db:"tour_guide_price"`
-	TourGuideCount              int       `db:"tour_guide_count"`
-	TourGuideDiscountPercentage float32   `db:"tour_guide_discount_percentage"`
-	Price                       int64     `db:"price"`
-	DiscountPercentage          float32   `db:"discount_percentage"`
-	CreatedAt                   time.Time `db:"created_at"`
-	UpdatedAt                   time.Time `db:"updated_at"`
+	ID                          uuid.UUID  `db:"id"`
+	Name                        string     `db:"name"`
+	Description                 string     `db:"description"`
+	Address                     string     `db:"address"`
+	City                        string     `db:"city"`
+	Province                    string     `db:"province"`
+	Longitude                   float64    `db:"longitude"`
+	Latitude                    float64    `db:"latitude"`
+	PhotoURL                    string     `db:"photo_url"`
+	TourGuidePrice              int64      `db:"tour_guide_price"`
+	TourGuideCount              int        `db:"tour_guide_count"`
+	TourGuideDiscountPercentage float32    `db:"tour_guide_discount_percentage"`
+	Price                       int64      `db:"price"`
+	DiscountPercentage          float32    `db:"discount_percentage"`
+	OwnerID                     *uuid.UUID `db:"owner_id"`
+	CreatedAt                   time.Time  `db:"created_at"`
+	UpdatedAt                   time.Time  `db:"updated_at"`
+	Rating                      float64    `db:"rating"`
+	Distance                    float64    `db:"distance"`
 	Bookings                    []TourGuideBookings
 }
 
 type Locals struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	Address     string    `db:"address"`
-	City        string    `db:"city"`
-	Province    string    `db:"province"`
-	Longitude   string    `db:"longitude"`
-	Latitude    string    `db:"latitude"`
-	Label       string    `db:"label"`
-	OpenedTime  string    `db:"opened_time"`
-	PhotoUrl    string    `db:"photo_url"`
-	IsBusiness  bool      `db:"is_business"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	Reviews     []Review
+	ID                     uuid.UUID  `db:"id"`
+	Name                   string     `db:"name"`
+	Description            string     `db:"description"`
+	Address                string     `db:"address"`
+	City                   string     `db:"city"`
+	Province               string     `db:"province"`
+	Longitude              float64    `db:"longitude"`
+	Latitude               float64    `db:"latitude"`
+	Label                  string     `db:"label"`
+	OpenedTime             string     `db:"opened_time"`
+	PhotoUrl               string     `db:"photo_url"`
+	IsBusiness             bool       `db:"is_business"`
+	Timezone               string     `db:"timezone"`
+	ClosedOnPublicHolidays bool       `db:"closed_on_public_holidays"`
+	OwnerID                *uuid.UUID `db:"owner_id"`
+	CreatedAt              time.Time  `db:"created_at"`
+	UpdatedAt              time.Time  `db:"updated_at"`
+	Rating                 float64    `db:"rating"`
+	Distance               float64    `db:"distance"`
+	Reviews                []Review
+}
+
+// OpeningHours is one interval of a local business's weekly schedule, times are HH:MM:SS in the business's time zone
+type OpeningHours struct {
+	LocalID  uuid.UUID `db:"local_id"`
+	Weekday  int       `db:"weekday"`
+	OpensAt  string    `db:"opens_at"`
+	ClosesAt string    `db:"closes_at"`
+}
+
+// OpeningException replaces the weekly hours of a date, a row without times closes the whole day
+type OpeningException struct {
+	LocalID  uuid.UUID `db:"local_id"`
+	Date     time.Time `db:"date"`
+	OpensAt  *string   `db:"opens_at"`
+	ClosesAt *string   `db:"closes_at"`
+	Note     string    `db:"note"`
+}
+
+type PublicHoliday struct {
+	Date time.Time `db:"date"`
+	Name string    `db:"name"`
+}
+
+// SearchResult is a local business or tourist attraction matching a search, NameHighlight and Snippet
+// mark the matched words with the search highlight delimiters
+type SearchResult struct {
+	Type          ListingType `db:"type"`
+	ID            uuid.UUID   `db:"id"`
+	Name          string      `db:"name"`
+	City          string      `db:"city"`
+	Province      string      `db:"province"`
+	Label         string      `db:"label"`
+	PhotoURL      string      `db:"photo_url"`
+	Rank          float64     `db:"rank"`
+	NameHighlight string      `db:"name_highlight"`
+	Snippet       string      `db:"snippet"`
+}
+
+// SearchFacetCount is the number of search matches sharing a value of a facet
+type SearchFacetCount struct {
+	Facet string `db:"facet"`
+	Value string `db:"value"`
+	Count int    `db:"count"`
+}
+
+// ListingCategory is a category linked to the local business or tourist attraction ListingID
+type ListingCategory struct {
+	ListingID uuid.UUID               `db:"listing_id"`
+	ID        uuid.UUID               `db:"id"`
+	ParentID  *uuid.UUID              `db:"parent_id"`
+	Slug      string                  `db:"slug"`
+	Names     category.LocalizedNames `db:"names"`
+	Icon      string                  `db:"icon"`
 }
 
 type Review struct {
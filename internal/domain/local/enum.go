@@ -0,0 +1,108 @@
+package local
+
+import "github.com/vistara-studio/vistara-be/pkg/pagination"
+
+// BookingStatus is the lifecycle state of a tour guide booking
+type BookingStatus string
+
+const (
+	BookingStatusPendingPayment BookingStatus = "pending_payment"
+	BookingStatusPaid           BookingStatus = "paid"
+	BookingStatusConfirmed      BookingStatus = "confirmed"
+	BookingStatusCompleted      BookingStatus = "completed"
+	BookingStatusCancelled      BookingStatus = "cancelled"
+	BookingStatusExpired        BookingStatus = "expired"
+	BookingStatusRefunded       BookingStatus = "refunded"
+	BookingStatusFlagged        BookingStatus = "flagged" // paid with the wrong amount, needs a manual review
+)
+
+// bookingTransitions is the allowed transition graph between booking statuses.
+// Statuses without an entry are terminal.
+var bookingTransitions = map[BookingStatus][]BookingStatus{
+	BookingStatusPendingPayment: {BookingStatusPaid, BookingStatusCancelled, BookingStatusExpired, BookingStatusFlagged},
+	BookingStatusPaid:           {BookingStatusConfirmed, BookingStatusCancelled, BookingStatusRefunded},
+	BookingStatusConfirmed:      {BookingStatusCompleted, BookingStatusCancelled, BookingStatusRefunded},
+	BookingStatusCancelled:      {BookingStatusRefunded},
+	BookingStatusFlagged:        {BookingStatusConfirmed, BookingStatusCancelled, BookingStatusRefunded},
+}
+
+// CanTransitionTo reports whether a booking may move from s to next
+func (s BookingStatus) CanTransitionTo(next BookingStatus) bool {
+	for _, allowed := range bookingTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further transition is possible from s
+func (s BookingStatus) IsTerminal() bool {
+	return len(bookingTransitions[s]) == 0
+}
+
+// Actors recorded in the booking status history besides individual users
+const (
+	BookingActorSystem   = "system"
+	BookingActorMidtrans = "midtrans"
+)
+
+// CatalogueSort is a column the catalogue lists can be ordered by
+type CatalogueSort string
+
+const (
+	SortName      CatalogueSort = "name"
+	SortCreatedAt CatalogueSort = "created_at"
+	SortPrice     CatalogueSort = "price"
+	SortRating    CatalogueSort = "rating"
+	SortDistance  CatalogueSort = "distance"
+	SortRelevance CatalogueSort = "relevance"
+)
+
+// Local businesses have no price, tourist attractions can be sorted by every column
+var (
+	LocalBusinessSorts     = []CatalogueSort{SortName, SortCreatedAt, SortRating}
+	TouristAttractionSorts = []CatalogueSort{SortName, SortCreatedAt, SortPrice, SortRating}
+)
+
+// DefaultOrder is ascending for names and distances and descending, newest or highest first, for everything else
+func (s CatalogueSort) DefaultOrder() string {
+	if s == SortName || s == SortDistance {
+		return pagination.OrderAsc
+	}
+	return pagination.OrderDesc
+}
+
+// Catalogue page sizes
+const (
+	DefaultCatalogueLimit = 20
+	MaxCatalogueLimit     = 100
+)
+
+// Nearby search radius in metres
+const (
+	DefaultNearbyRadius = 5000
+	MaxNearbyRadius     = 50000
+)
+
+// ListingType tells search results from the two catalogue tables apart
+type ListingType string
+
+const (
+	ListingLocalBusiness     ListingType = "local_business"
+	ListingTouristAttraction ListingType = "tourist_attraction"
+)
+
+// Facets counted over the search matches
+const (
+	FacetType     = "type"
+	FacetProvince = "province"
+	FacetLabel    = "label"
+)
+
+// Search highlight delimiters, private use characters that won't appear in listing text.
+// They are swapped for <mark> tags once the text around them is HTML escaped.
+const (
+	SearchHighlightStart = "\uE000"
+	SearchHighlightStop  = "\uE001"
+)
@@ -0,0 +1,58 @@
+package local
+
+import "testing"
+
+func TestBookingTransitions(t *testing.T) {
+	statuses := []BookingStatus{
+		BookingStatusPendingPayment,
+		BookingStatusPaid,
+		BookingStatusConfirmed,
+		BookingStatusCompleted,
+		BookingStatusCancelled,
+		BookingStatusExpired,
+		BookingStatusRefunded,
+		BookingStatusFlagged,
+	}
+
+	// Every allowed transition, anything else must be refused
+	allowed := map[BookingStatus]map[BookingStatus]bool{
+		BookingStatusPendingPayment: {BookingStatusPaid: true, BookingStatusCancelled: true, BookingStatusExpired: true, BookingStatusFlagged: true},
+		BookingStatusPaid:           {BookingStatusConfirmed: true, BookingStatusCancelled: true, BookingStatusRefunded: true},
+		BookingStatusConfirmed:      {BookingStatusCompleted: true, BookingStatusCancelled: true, BookingStatusRefunded: true},
+		BookingStatusCancelled:      {BookingStatusRefunded: true},
+		BookingStatusFlagged:        {BookingStatusConfirmed: true, BookingStatusCancelled: true, BookingStatusRefunded: true},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got, want := from.CanTransitionTo(to), allowed[from][to]; got != want {
+				t.Errorf("%s -> %s allowed = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestBookingStatusIsTerminal(t *testing.T) {
+	tests := []struct {
+		status BookingStatus
+		want   bool
+	}{
+		{status: BookingStatusPendingPayment},
+		{status: BookingStatusPaid},
+		{status: BookingStatusConfirmed},
+		{status: BookingStatusCancelled},
+		{status: BookingStatusFlagged},
+		{status: BookingStatusCompleted, want: true},
+		{status: BookingStatusExpired, want: true},
+		{status: BookingStatusRefunded, want: true},
+		{status: "pending", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsTerminal(); got != tt.want {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,5 +8,21 @@ import (
 )
 
 var (
-	ErrLBNotFound = cerr.New(fiber.ErrNotFound.Code, "local business not found", errors.New("account not found"))
+	ErrLBNotFound               = cerr.New(fiber.ErrNotFound.Code, "local business not found", errors.New("account not found"))
+	ErrBookingNotFound          = cerr.New(fiber.ErrNotFound.Code, "booking not found", errors.New("booking not found"))
+	ErrFullyBooked              = cerr.New(fiber.ErrConflict.Code, "all tour guides are booked on this date", errors.New("tour guide capacity reached"))
+	ErrInvalidBookingDate       = cerr.New(fiber.ErrBadRequest.Code, "booking date must be today or later in YYYY-MM-DD format", errors.New("invalid booking date"))
+	ErrInvalidBookingTransition = cerr.New(fiber.ErrConflict.Code, "booking status transition not allowed", errors.New("invalid booking status transition"))
+	ErrInvalidPaymentSignature  = cerr.New(fiber.ErrForbidden.Code, "invalid payment signature", errors.New("signature key mismatch"))
+	ErrPaymentStatusUnavailable = cerr.New(fiber.ErrBadGateway.Code, "failed to verify payment status", errors.New("midtrans status check failed"))
+	ErrNotListingOwner          = cerr.New(fiber.ErrForbidden.Code, "you can only manage listings you own", errors.New("listing belongs to another user"))
+	ErrInvalidSort              = cerr.New(fiber.ErrBadRequest.Code, "unsupported sort, order must be asc or desc", errors.New("invalid sort"))
+	ErrInvalidCursor            = cerr.New(fiber.ErrBadRequest.Code, "cursor is invalid or was issued for another sort", errors.New("invalid cursor"))
+	ErrInvalidPriceRange        = cerr.New(fiber.ErrBadRequest.Code, "min_price must not be greater than max_price", errors.New("invalid price range"))
+	ErrInvalidOpenAt            = cerr.New(fiber.ErrBadRequest.Code, "open_at must be an RFC 3339 time", errors.New("invalid open_at"))
+	ErrInvalidOpeningHours      = cerr.New(fiber.ErrBadRequest.Code, "opening hours must be HH:MM with different opening and closing times", errors.New("invalid opening hours"))
+	ErrInvalidDate              = cerr.New(fiber.ErrBadRequest.Code, "date must be in YYYY-MM-DD format", errors.New("invalid date"))
+	ErrExceptionNotFound        = cerr.New(fiber.ErrNotFound.Code, "no opening hours exception on this date", errors.New("opening exception not found"))
+	ErrUnknownCategory          = cerr.New(fiber.ErrBadRequest.Code, "one of the categories doesn't exist", errors.New("unknown category"))
+	ErrHolidayNotFound          = cerr.New(fiber.ErrNotFound.Code, "public holiday not found", errors.New("public holiday not found"))
 )
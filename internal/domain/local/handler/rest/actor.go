@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+)
+
+// actorFromContext builds the catalogue write actor from the authenticated user
+func actorFromContext(ctx *fiber.Ctx) (local.Actor, bool) {
+	userIDRaw, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return local.Actor{}, false
+	}
+
+	userID, err := uuid.Parse(userIDRaw)
+	if err != nil {
+		return local.Actor{}, false
+	}
+
+	role, _ := ctx.Locals("role").(string)
+
+	return local.Actor{
+		UserID:  userID,
+		IsAdmin: user.Role(role) == user.RoleAdmin,
+	}, true
+}
+
+// unauthenticatedActor is the response sent when the token carries no usable user ID
+func unauthenticatedActor(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"error":   "Authentication required",
+		"message": "Failed to get user ID from authentication token",
+	})
+}
+
+// notListingOwner is the response sent when a non-admin changes a listing they do not own
+func notListingOwner(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":   "Forbidden",
+		"message": local.ErrNotListingOwner.Message,
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,6 +11,11 @@ import (
 
 // CreateTouristAttraction handles the request to create a new tourist attraction
 func (h *LocalHandler) CreateTouristAttraction(ctx *fiber.Ctx) error {
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
 	var request local.RequestCreateTouristAttraction
 	
 	if err := ctx.BodyParser(&request); err != nil {
@@ -26,7 +32,7 @@ func (h *LocalHandler) CreateTouristAttraction(ctx *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.service.CreateTouristAttraction(ctx.Context(), request)
+	response, err := h.service.CreateTouristAttraction(ctx.Context(), actor, request)
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to create tourist attraction",
@@ -58,6 +64,11 @@ func (h *LocalHandler) UpdateTouristAttraction(ctx *fiber.Ctx) error {
 		})
 	}
 
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
 	var request local.RequestUpdateTouristAttraction
 	
 	if err := ctx.BodyParser(&request); err != nil {
@@ -74,9 +85,12 @@ func (h *LocalHandler) UpdateTouristAttraction(ctx *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.service.UpdateTouristAttraction(ctx.Context(), attractionID, request)
+	response, err := h.service.UpdateTouristAttraction(ctx.Context(), actor, attractionID, request)
 	if err != nil {
-		if err == local.ErrLBNotFound {
+		if errors.Is(err, local.ErrNotListingOwner) {
+			return notListingOwner(ctx)
+		}
+		if errors.Is(err, local.ErrLBNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Tourist attraction not found",
 				"message": "The requested tourist attraction does not exist",
@@ -112,9 +126,17 @@ func (h *LocalHandler) DeleteTouristAttraction(ctx *fiber.Ctx) error {
 		})
 	}
 
-	err = h.service.DeleteTouristAttraction(ctx.Context(), attractionID)
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	err = h.service.DeleteTouristAttraction(ctx.Context(), actor, attractionID)
 	if err != nil {
-		if err == local.ErrLBNotFound {
+		if errors.Is(err, local.ErrNotListingOwner) {
+			return notListingOwner(ctx)
+		}
+		if errors.Is(err, local.ErrLBNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Tourist attraction not found",
 				"message": "The requested tourist attraction does not exist",
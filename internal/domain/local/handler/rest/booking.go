@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// GetBookingStatusHistory handles the request to get the status history of one of the user's bookings
+func (h *LocalHandler) GetBookingStatusHistory(ctx *fiber.Ctx) error {
+	bookingIDStr := ctx.Params("bookingID", "")
+	bookingID, err := uuid.Parse(bookingIDStr)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid UUID format",
+			"message": fmt.Sprintf("Invalid booking ID format: %s", bookingIDStr),
+		})
+	}
+
+	userIDRaw, ok := ctx.Locals("user_id").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Authentication required",
+			"message": "Failed to get user ID from authentication token",
+		})
+	}
+
+	userID, err := uuid.Parse(userIDRaw)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid user ID",
+			"message": "Invalid user ID format in authentication token",
+		})
+	}
+
+	response, err := h.service.GetBookingStatusHistory(ctx.Context(), bookingID, userID)
+	if err != nil {
+		if err == local.ErrBookingNotFound {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "Booking not found",
+				"message": "The requested booking does not exist",
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve booking history",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get booking status history successful",
+		"payload": response,
+	})
+}
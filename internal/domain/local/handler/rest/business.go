@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,19 +9,23 @@ import (
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
 )
 
-// GetAllLocalBusinesses handles the request to get all local businesses with optional filtering
+// GetAllLocalBusinesses handles the request to get a page of local businesses with optional filtering and sorting
 func (h *LocalHandler) GetAllLocalBusinesses(ctx *fiber.Ctx) error {
-	request := local.QueryParamRequestGetLocals{
-		City: ctx.Query("city", ""),
-		Type: ctx.Query("type", "business"), // Default to business type
+	var request local.QueryParamRequestGetLocals
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
+	if request.Type == "" {
+		request.Type = "business" // Default to business type
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
 	}
 
 	response, err := h.service.GetAllLocalBusinessesWithFilters(ctx.Context(), request)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Failed to retrieve local businesses",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -29,6 +34,28 @@ func (h *LocalHandler) GetAllLocalBusinesses(ctx *fiber.Ctx) error {
 	})
 }
 
+// GetNearbyLocalBusinesses handles the request to find local businesses around a point, nearest first
+func (h *LocalHandler) GetNearbyLocalBusinesses(ctx *fiber.Ctx) error {
+	var request local.QueryParamRequestNearby
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.GetNearbyLocalBusinesses(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get nearby local businesses successful",
+		"payload": response,
+	})
+}
+
 // GetLocalBusinessByID handles the request to get a specific local business by its ID
 func (h *LocalHandler) GetLocalBusinessByID(ctx *fiber.Ctx) error {
 	businessIDStr := ctx.Params("localBusinessID", "")
@@ -69,6 +96,11 @@ func (h *LocalHandler) GetLocalBusinessByID(ctx *fiber.Ctx) error {
 
 // CreateLocalBusiness handles the request to create a new local business
 func (h *LocalHandler) CreateLocalBusiness(ctx *fiber.Ctx) error {
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
 	var request local.RequestCreateLocalBusiness
 	
 	if err := ctx.BodyParser(&request); err != nil {
@@ -85,8 +117,11 @@ func (h *LocalHandler) CreateLocalBusiness(ctx *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.service.CreateLocalBusiness(ctx.Context(), request)
+	response, err := h.service.CreateLocalBusiness(ctx.Context(), actor, request)
 	if err != nil {
+		if errors.Is(err, local.ErrInvalidOpeningHours) {
+			return err
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to create local business",
 			"message": err.Error(),
@@ -117,6 +152,11 @@ func (h *LocalHandler) UpdateLocalBusiness(ctx *fiber.Ctx) error {
 		})
 	}
 
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
 	var request local.RequestUpdateLocalBusiness
 	
 	if err := ctx.BodyParser(&request); err != nil {
@@ -133,9 +173,12 @@ func (h *LocalHandler) UpdateLocalBusiness(ctx *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.service.UpdateLocalBusiness(ctx.Context(), businessID, request)
+	response, err := h.service.UpdateLocalBusiness(ctx.Context(), actor, businessID, request)
 	if err != nil {
-		if err == local.ErrLBNotFound {
+		if errors.Is(err, local.ErrNotListingOwner) {
+			return notListingOwner(ctx)
+		}
+		if errors.Is(err, local.ErrLBNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Local business not found",
 				"message": "The requested local business does not exist",
@@ -171,9 +214,17 @@ func (h *LocalHandler) DeleteLocalBusiness(ctx *fiber.Ctx) error {
 		})
 	}
 
-	err = h.service.DeleteLocalBusiness(ctx.Context(), businessID)
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	err = h.service.DeleteLocalBusiness(ctx.Context(), actor, businessID)
 	if err != nil {
-		if err == local.ErrLBNotFound {
+		if errors.Is(err, local.ErrNotListingOwner) {
+			return notListingOwner(ctx)
+		}
+		if errors.Is(err, local.ErrLBNotFound) {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Local business not found",
 				"message": "The requested local business does not exist",
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// SetLocalBusinessCategories handles the request to replace the categories of a local business
+func (h *LocalHandler) SetLocalBusinessCategories(ctx *fiber.Ctx) error {
+	businessID, ok, err := localBusinessIDParam(ctx)
+	if !ok {
+		return err
+	}
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	var request local.RequestSetCategories
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.SetLocalBusinessCategories(ctx.Context(), actor, businessID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "set local business categories successful",
+		"payload": response,
+	})
+}
+
+// SetTouristAttractionCategories handles the request to replace the categories of a tourist attraction
+func (h *LocalHandler) SetTouristAttractionCategories(ctx *fiber.Ctx) error {
+	attractionIDStr := ctx.Params("attractionID", "")
+	attractionID, err := uuid.Parse(attractionIDStr)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid UUID format",
+			"message": fmt.Sprintf("Invalid tourist attraction ID format: %s", attractionIDStr),
+		})
+	}
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	var request local.RequestSetCategories
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.SetTouristAttractionCategories(ctx.Context(), actor, attractionID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "set tourist attraction categories successful",
+		"payload": response,
+	})
+}
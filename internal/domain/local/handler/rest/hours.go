@@ -0,0 +1,198 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// localBusinessIDParam reads the local business ID path parameter, writing the bad request response when it is malformed
+func localBusinessIDParam(ctx *fiber.Ctx) (uuid.UUID, bool, error) {
+	businessIDStr := ctx.Params("localBusinessID", "")
+	businessID, err := uuid.Parse(businessIDStr)
+	if err != nil {
+		return uuid.Nil, false, ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid UUID format",
+			"message": fmt.Sprintf("Invalid local business ID format: %s", businessIDStr),
+		})
+	}
+	return businessID, true, nil
+}
+
+// GetOpeningHours handles the request to get the weekly schedule of a local business
+func (h *LocalHandler) GetOpeningHours(ctx *fiber.Ctx) error {
+	businessID, ok, err := localBusinessIDParam(ctx)
+	if !ok {
+		return err
+	}
+
+	response, err := h.service.GetOpeningHours(ctx.Context(), businessID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get opening hours successful",
+		"payload": response,
+	})
+}
+
+// UpdateOpeningHours handles the request to replace the weekly schedule of a local business
+func (h *LocalHandler) UpdateOpeningHours(ctx *fiber.Ctx) error {
+	businessID, ok, err := localBusinessIDParam(ctx)
+	if !ok {
+		return err
+	}
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	var request local.RequestOpeningHours
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateOpeningHours(ctx.Context(), actor, businessID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "update opening hours successful",
+		"payload": response,
+	})
+}
+
+// SetOpeningException handles the request to set the hours of a local business on one date
+func (h *LocalHandler) SetOpeningException(ctx *fiber.Ctx) error {
+	businessID, ok, err := localBusinessIDParam(ctx)
+	if !ok {
+		return err
+	}
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	var request local.RequestOpeningException
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.SetOpeningException(ctx.Context(), actor, businessID, ctx.Params("date"), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "set opening exception successful",
+		"payload": response,
+	})
+}
+
+// DeleteOpeningException handles the request to restore the weekly hours of a local business on a date
+func (h *LocalHandler) DeleteOpeningException(ctx *fiber.Ctx) error {
+	businessID, ok, err := localBusinessIDParam(ctx)
+	if !ok {
+		return err
+	}
+
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	if err := h.service.DeleteOpeningException(ctx.Context(), actor, businessID, ctx.Params("date")); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "delete opening exception successful",
+	})
+}
+
+// GetPublicHolidays handles the request to list the public holidays of a year
+func (h *LocalHandler) GetPublicHolidays(ctx *fiber.Ctx) error {
+	var request local.QueryParamRequestPublicHolidays
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.GetPublicHolidays(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get public holidays successful",
+		"payload": response,
+	})
+}
+
+// SetPublicHoliday handles the admin request to add or rename a public holiday
+func (h *LocalHandler) SetPublicHoliday(ctx *fiber.Ctx) error {
+	var request local.RequestPublicHoliday
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.SetPublicHoliday(ctx.Context(), ctx.Params("date"), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "set public holiday successful",
+		"payload": response,
+	})
+}
+
+// DeletePublicHoliday handles the admin request to remove a public holiday
+func (h *LocalHandler) DeletePublicHoliday(ctx *fiber.Ctx) error {
+	if err := h.service.DeletePublicHoliday(ctx.Context(), ctx.Params("date")); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "delete public holiday successful",
+	})
+}
+
+// ImportLegacyOpeningHours handles the admin request to convert free-text opened_time into structured hours
+func (h *LocalHandler) ImportLegacyOpeningHours(ctx *fiber.Ctx) error {
+	actor, ok := actorFromContext(ctx)
+	if !ok {
+		return unauthenticatedActor(ctx)
+	}
+
+	response, err := h.service.ImportLegacyOpeningHours(ctx.Context(), actor)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "import opening hours successful",
+		"payload": response,
+	})
+}
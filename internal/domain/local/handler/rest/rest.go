@@ -3,24 +3,25 @@ package rest
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
 	"github.com/vistara-studio/vistara-be/internal/domain/local/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
 	"github.com/vistara-studio/vistara-be/internal/middleware"
-	"github.com/vistara-studio/vistara-be/pkg/jwt"
 )
 
 // LocalHandler handles HTTP requests for local business and tourist attraction endpoints
 type LocalHandler struct {
-	service   service.LocalServiceInterface
-	validator *validator.Validate
-	jwt       *jwt.JWTStruct
+	service    service.LocalServiceInterface
+	validator  *validator.Validate
+	middleware *middleware.Middleware
 }
 
 // New creates a new LocalHandler instance
-func New(service service.LocalServiceInterface, validator *validator.Validate, jwt *jwt.JWTStruct) *LocalHandler {
+func New(service service.LocalServiceInterface, validator *validator.Validate, middleware *middleware.Middleware) *LocalHandler {
 	return &LocalHandler{
-		service:   service,
-		validator: validator,
-		jwt:       jwt,
+		service:    service,
+		validator:  validator,
+		middleware: middleware,
 	}
 }
 
@@ -28,27 +29,57 @@ func New(service service.LocalServiceInterface, validator *validator.Validate, j
 func (h *LocalHandler) Mount(router fiber.Router) {
 	// Service-to-service routes for AI integration (with service authentication)
 	serviceGroup := router.Group("/service")
-	serviceGroup.Use(middleware.ServiceAuthentication())
+	serviceGroup.Use(h.middleware.ServiceAuthentication())
 	serviceGroup.Get("/locals", h.GetAllLocalBusinesses)
 	serviceGroup.Get("/tourist-attractions", h.GetAllTouristAttractions)
 
-	// Local business routes - All require authentication
+	// Catalogue writes are limited to merchants (their own listings) and admins
+	canManageListings := h.middleware.RequireRole(user.RoleMerchant, user.RoleAdmin)
+	requireAdmin := h.middleware.RequireRole(user.RoleAdmin)
+
+	// Catalogue reads and bookings also accept partner API keys with the matching scope
+	readCatalogue := h.middleware.AuthenticationOrAPIKey(apikey.ScopeReadCatalogue)
+	readBookings := h.middleware.AuthenticationOrAPIKey(apikey.ScopeReadBookings)
+	writeBookings := h.middleware.AuthenticationOrAPIKey(apikey.ScopeWriteBookings)
+	authentication := h.middleware.Authentication()
+
+	// Search across both catalogues - requires a user token or a partner API key
+	router.Get("/search", readCatalogue, h.SearchCatalogue)
+
+	// Local business routes - All require a user token or a partner API key
 	localGroup := router.Group("/locals")
-	localGroup.Use(middleware.Authentication(h.jwt))
-	localGroup.Get("/", h.GetAllLocalBusinesses)
-	localGroup.Get("/:localBusinessID", h.GetLocalBusinessByID)
-	localGroup.Post("/", h.CreateLocalBusiness)
-	localGroup.Put("/:localBusinessID", h.UpdateLocalBusiness)
-	localGroup.Delete("/:localBusinessID", h.DeleteLocalBusiness)
-
-	// Tourist attraction routes - All require authentication
+	localGroup.Get("/", readCatalogue, h.GetAllLocalBusinesses)
+	localGroup.Get("/nearby", readCatalogue, h.GetNearbyLocalBusinesses)
+	localGroup.Get("/:localBusinessID", readCatalogue, h.GetLocalBusinessByID)
+	localGroup.Post("/", authentication, canManageListings, h.CreateLocalBusiness)
+	localGroup.Put("/:localBusinessID", authentication, canManageListings, h.UpdateLocalBusiness)
+	localGroup.Delete("/:localBusinessID", authentication, canManageListings, h.DeleteLocalBusiness)
+	localGroup.Get("/:localBusinessID/opening-hours", readCatalogue, h.GetOpeningHours)
+	localGroup.Put("/:localBusinessID/opening-hours", authentication, canManageListings, h.UpdateOpeningHours)
+	localGroup.Put("/:localBusinessID/opening-hours/exceptions/:date", authentication, canManageListings, h.SetOpeningException)
+	localGroup.Delete("/:localBusinessID/opening-hours/exceptions/:date", authentication, canManageListings, h.DeleteOpeningException)
+	localGroup.Put("/:localBusinessID/categories", authentication, canManageListings, h.SetLocalBusinessCategories)
+
+	// Public holidays are read with the catalogue and managed by admins
+	router.Get("/public-holidays", readCatalogue, h.GetPublicHolidays)
+	adminGroup := router.Group("/admin")
+	adminGroup.Put("/public-holidays/:date", authentication, requireAdmin, h.SetPublicHoliday)
+	adminGroup.Delete("/public-holidays/:date", authentication, requireAdmin, h.DeletePublicHoliday)
+	adminGroup.Post("/opening-hours/import", authentication, requireAdmin, h.ImportLegacyOpeningHours)
+
+	// Tourist attraction routes - All require a user token or a partner API key
 	attractionGroup := router.Group("/tourist-attractions")
-	attractionGroup.Use(middleware.Authentication(h.jwt))
-	attractionGroup.Get("/", h.GetAllTouristAttractions)
-	attractionGroup.Get("/:attractionID", h.GetTouristAttractionByID)
-	attractionGroup.Post("/", h.CreateTouristAttraction)
-	attractionGroup.Put("/:attractionID", h.UpdateTouristAttraction)
-	attractionGroup.Delete("/:attractionID", h.DeleteTouristAttraction)
-	attractionGroup.Get("/:attractionID/availability", h.GetFullyBookedDates)
-	attractionGroup.Post("/:attractionID/book", h.CreateTourGuideBooking)
+	attractionGroup.Get("/", readCatalogue, h.GetAllTouristAttractions)
+	attractionGroup.Get("/nearby", readCatalogue, h.GetNearbyTouristAttractions)
+	attractionGroup.Get("/:attractionID", readCatalogue, h.GetTouristAttractionByID)
+	attractionGroup.Post("/", authentication, canManageListings, h.CreateTouristAttraction)
+	attractionGroup.Put("/:attractionID", authentication, canManageListings, h.UpdateTouristAttraction)
+	attractionGroup.Delete("/:attractionID", authentication, canManageListings, h.DeleteTouristAttraction)
+	attractionGroup.Put("/:attractionID/categories", authentication, canManageListings, h.SetTouristAttractionCategories)
+	attractionGroup.Get("/:attractionID/availability", readCatalogue, h.GetFullyBookedDates)
+	attractionGroup.Post("/:attractionID/book", writeBookings, h.middleware.RequireVerifiedEmail(), h.CreateTourGuideBooking)
+
+	// Booking routes - All require a user token or a partner API key
+	bookingGroup := router.Group("/bookings")
+	bookingGroup.Get("/:bookingID/history", readBookings, h.GetBookingStatusHistory)
 }
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// SearchCatalogue handles the full-text search over local businesses and tourist attractions
+func (h *LocalHandler) SearchCatalogue(ctx *fiber.Ctx) error {
+	var request local.QueryParamRequestSearch
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.SearchCatalogue(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "search catalogue successful",
+		"payload": response,
+	})
+}
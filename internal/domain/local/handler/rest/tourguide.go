@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -8,18 +9,19 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
 )
 
-// GetAllTouristAttractions handles the request to get all tourist attractions
+// GetAllTouristAttractions handles the request to get a page of tourist attractions with optional filtering and sorting
 func (h *LocalHandler) GetAllTouristAttractions(ctx *fiber.Ctx) error {
-	city := ctx.Query("city", "")
+	var request local.QueryParamRequestGetTouristAttractions
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
 
-	response, err := h.service.GetAllTouristAttractions(ctx.Context(), city)
+	response, err := h.service.GetAllTouristAttractions(ctx.Context(), request)
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error":   "Failed to retrieve tourist attractions",
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -28,6 +30,28 @@ func (h *LocalHandler) GetAllTouristAttractions(ctx *fiber.Ctx) error {
 	})
 }
 
+// GetNearbyTouristAttractions handles the request to find tourist attractions around a point, nearest first
+func (h *LocalHandler) GetNearbyTouristAttractions(ctx *fiber.Ctx) error {
+	var request local.QueryParamRequestNearby
+	if err := ctx.QueryParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.GetNearbyTouristAttractions(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get nearby tourist attractions successful",
+		"payload": response,
+	})
+}
+
 // GetTouristAttractionByID handles the request to get a specific tourist attraction by ID
 func (h *LocalHandler) GetTouristAttractionByID(ctx *fiber.Ctx) error {
 	attractionIDStr := ctx.Params("attractionID", "")
@@ -175,6 +199,13 @@ func (h *LocalHandler) CreateTourGuideBooking(ctx *fiber.Ctx) error {
 
 	response, err := h.service.GeneratePaymentSnapLink(ctx.Context(), request)
 	if err != nil {
+		var customErr *cerr.CustomError
+		if errors.As(err, &customErr) {
+			return ctx.Status(customErr.Code).JSON(fiber.Map{
+				"error":   customErr.Message,
+				"message": "Failed to generate payment link",
+			})
+		}
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to generate payment link",
 			"message": err.Error(),
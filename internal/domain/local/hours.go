@@ -0,0 +1,222 @@
+package local
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
+)
+
+// Indonesian time zones, a local business follows the one of its province
+const (
+	TimezoneWIB  = "Asia/Jakarta"
+	TimezoneWITA = "Asia/Makassar"
+	TimezoneWIT  = "Asia/Jayapura"
+)
+
+// TimezoneForProvince picks WIB, WITA or WIT from an Indonesian or English province name, unknown provinces fall back to WIB
+func TimezoneForProvince(province string) string {
+	name := strings.ToLower(province)
+	contains := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case contains("papua", "maluku", "moluccas"):
+		return TimezoneWIT
+	case contains("bali", "nusa tenggara", "sulawesi", "celebes", "gorontalo"),
+		contains("kalimantan") && contains("selatan", "timur", "utara", "south", "east", "north"):
+		return TimezoneWITA
+	default:
+		return TimezoneWIB
+	}
+}
+
+// Clock is a time of day in minutes after midnight, EndOfDay closes an interval at midnight
+type Clock int
+
+const EndOfDay Clock = 24 * 60
+
+// ParseClock reads HH:MM, HH.MM or the HH:MM:SS form Postgres returns for TIME columns, 24:00 is the end of the day
+func ParseClock(value string) (Clock, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(value), func(r rune) bool { return r == ':' || r == '.' })
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+
+	clock := Clock(hours*60 + minutes)
+	if hours < 0 || minutes < 0 || minutes > 59 || clock > EndOfDay {
+		return 0, fmt.Errorf("time %q is out of range", value)
+	}
+
+	return clock, nil
+}
+
+// String formats the clock as HH:MM
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+// Interval is an opening period starting on a day, Closes before Opens runs past midnight into the next day
+type Interval struct {
+	Opens  Clock
+	Closes Clock
+}
+
+// NewInterval validates an opening period given as HH:MM strings, a closing time of 00:00 means midnight
+func NewInterval(opens, closes string) (Interval, error) {
+	opensAt, err := ParseClock(opens)
+	if err != nil {
+		return Interval{}, err
+	}
+	closesAt, err := ParseClock(closes)
+	if err != nil {
+		return Interval{}, err
+	}
+	if closesAt == 0 {
+		closesAt = EndOfDay
+	}
+
+	if opensAt == EndOfDay || opensAt == closesAt {
+		return Interval{}, fmt.Errorf("interval %s-%s is empty", opens, closes)
+	}
+
+	return Interval{Opens: opensAt, Closes: closesAt}, nil
+}
+
+// Overnight reports whether the interval ends on the next day
+func (i Interval) Overnight() bool {
+	return i.Closes < i.Opens
+}
+
+// WeeklyInterval is an interval on a day of the week
+type WeeklyInterval struct {
+	Weekday time.Weekday
+	Interval
+}
+
+// SortWeekly orders intervals from Sunday to Saturday and by opening time within a day
+func SortWeekly(intervals []WeeklyInterval) {
+	sort.Slice(intervals, func(i, j int) bool {
+		if intervals[i].Weekday != intervals[j].Weekday {
+			return intervals[i].Weekday < intervals[j].Weekday
+		}
+		return intervals[i].Opens < intervals[j].Opens
+	})
+}
+
+// DateLayout formats the calendar dates of exceptions and public holidays
+const DateLayout = "2006-01-02"
+
+// Schedule is everything deciding when a local business is open. Exceptions replace the weekly hours of their
+// date, an empty list closes the whole day, and public holidays close the day when ClosedOnHolidays is set.
+type Schedule struct {
+	Location         *time.Location
+	Weekly           []WeeklyInterval
+	Exceptions       map[string][]Interval
+	Holidays         map[string]bool
+	ClosedOnHolidays bool
+}
+
+// Known reports whether any hours were ever entered, without them nothing can be said about being open
+func (s Schedule) Known() bool {
+	return len(s.Weekly) > 0 || len(s.Exceptions) > 0
+}
+
+// IntervalsOn returns the intervals starting on the local date of day
+func (s Schedule) IntervalsOn(day time.Time) []Interval {
+	date := day.Format(DateLayout)
+	if exceptions, ok := s.Exceptions[date]; ok {
+		return exceptions
+	}
+	if s.ClosedOnHolidays && s.Holidays[date] {
+		return nil
+	}
+
+	var intervals []Interval
+	for _, weekly := range s.Weekly {
+		if weekly.Weekday == day.Weekday() {
+			intervals = append(intervals, weekly.Interval)
+		}
+	}
+	return intervals
+}
+
+// StatusHorizonDays is how far ahead the next opening or closing is looked for
+const StatusHorizonDays = 7
+
+// Status reports whether the business is open at the given instant and when that next changes,
+// nextChange is nil when it doesn't change within StatusHorizonDays
+func (s Schedule) Status(at time.Time) (open bool, nextChange *time.Time) {
+	local := at.In(s.Location)
+	year, month, day := local.Date()
+	midnight := func(offset int) time.Time {
+		return time.Date(year, month, day+offset, 0, 0, 0, 0, s.Location)
+	}
+	horizon := midnight(StatusHorizonDays + 1)
+
+	// Yesterday is included for its overnight intervals, touching periods are merged so that a
+	// closing at midnight followed by an opening at midnight is no change
+	type period struct{ start, end time.Time }
+	var periods []period
+	for offset := -1; offset <= StatusHorizonDays; offset++ {
+		date := midnight(offset)
+		for _, interval := range s.IntervalsOn(date) {
+			closesOn := offset
+			if interval.Overnight() {
+				closesOn++
+			}
+			periods = append(periods, period{
+				start: date.Add(time.Duration(interval.Opens) * time.Minute),
+				end:   midnight(closesOn).Add(time.Duration(interval.Closes) * time.Minute),
+			})
+		}
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+	var merged []period
+	for _, p := range periods {
+		if last := len(merged) - 1; last >= 0 && !p.start.After(merged[last].end) {
+			if p.end.After(merged[last].end) {
+				merged[last].end = p.end
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+
+	for _, p := range merged {
+		if at.Before(p.start) {
+			return false, changeBefore(p.start, horizon)
+		}
+		if at.Before(p.end) {
+			return true, changeBefore(p.end, horizon)
+		}
+	}
+
+	return false, nil
+}
+
+// changeBefore returns change unless it lies at or past the horizon, where periods are cut off
+func changeBefore(change, horizon time.Time) *time.Time {
+	if !change.Before(horizon) {
+		return nil
+	}
+	return &change
+}
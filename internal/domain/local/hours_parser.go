@@ -0,0 +1,196 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayNames maps Indonesian and English day names and their usual abbreviations to weekdays
+var dayNames = map[string]time.Weekday{
+	"minggu": time.Sunday, "ahad": time.Sunday, "sunday": time.Sunday, "sun": time.Sunday, "min": time.Sunday,
+	"senin": time.Monday, "monday": time.Monday, "mon": time.Monday, "sen": time.Monday,
+	"selasa": time.Tuesday, "tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "sel": time.Tuesday,
+	"rabu": time.Wednesday, "wednesday": time.Wednesday, "wed": time.Wednesday, "rab": time.Wednesday,
+	"kamis": time.Thursday, "thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "kam": time.Thursday,
+	"jumat": time.Friday, "friday": time.Friday, "fri": time.Friday, "jum": time.Friday,
+	"sabtu": time.Saturday, "saturday": time.Saturday, "sat": time.Saturday, "sab": time.Saturday,
+}
+
+var (
+	dayPattern      = `(minggu|ahad|sunday|sun|min|senin|monday|mon|sen|selasa|tuesday|tues|tue|sel|rabu|wednesday|wed|rab|kamis|thursday|thurs|thur|thu|kam|jumat|friday|fri|jum|sabtu|saturday|sat|sab)`
+	clockPattern    = `(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)?`
+	openedTimeToken = regexp.MustCompile(
+		`(?P<range>` + clockPattern + `\s*-\s*` + clockPattern + `)` +
+			`|(?P<days>\b` + dayPattern + `\b\s*-\s*\b` + dayPattern + `\b)` +
+			`|(?P<day>\b` + dayPattern + `\b)` +
+			`|(?P<daily>setiap hari|tiap hari|every\s?day|daily|all days?)` +
+			`|(?P<closed>tutup|closed|libur)`,
+	)
+	clockRangePattern = regexp.MustCompile(`^` + clockPattern + `\s*-\s*` + clockPattern + `$`)
+	allDayPattern     = regexp.MustCompile(`24\s*(jam|hours?|hrs?|h)\b|24\s*/\s*7|24\s*x\s*7|nonstop`)
+	digitPattern      = regexp.MustCompile(`\d`)
+)
+
+// ErrUnparsedOpenedTime is wrapped by every ParseOpenedTime failure
+var ErrUnparsedOpenedTime = errors.New("opening hours not understood")
+
+// ParseOpenedTime reads the free-text opened_time of older listings, such as "Senin-Jumat 08.00-17.00, Sabtu 09:00-14:00",
+// "10am-10pm", "Setiap hari 07.00 - 21.00 WITA" or "Buka 24 jam". Hours without days apply to every day and hours
+// right after other hours share their days. It is best effort, anything it can't account for is an error.
+func ParseOpenedTime(text string) ([]WeeklyInterval, error) {
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	for _, replacement := range [][2]string{
+		{"–", "-"}, {"—", "-"}, {"s/d", "-"}, {"s.d.", "-"}, {"s.d", "-"}, {"sampai", "-"}, {"hingga", "-"},
+		{" to ", " - "}, {" until ", " - "}, {"jum'at", "jumat"}, {"a.m.", "am"}, {"p.m.", "pm"},
+	} {
+		normalized = strings.ReplaceAll(normalized, replacement[0], replacement[1])
+	}
+	if normalized == "" {
+		return nil, fmt.Errorf("%w: empty", ErrUnparsedOpenedTime)
+	}
+
+	// "24 jam" is read as hours covering the whole day, so it can still be given days
+	normalized = allDayPattern.ReplaceAllString(normalized, " 00:00-24:00 ")
+
+	var (
+		intervals []WeeklyInterval
+		pending   []time.Weekday // days named since the last hours
+		current   []time.Weekday // days the last hours applied to
+		leftover  strings.Builder
+		last      int
+
+		// hours given before any day, such as "07.00-21.00 setiap hari", take trailing days instead of every day
+		undated = true
+		ranges  []Interval
+	)
+
+	names := openedTimeToken.SubexpNames()
+	for _, match := range openedTimeToken.FindAllStringSubmatchIndex(normalized, -1) {
+		leftover.WriteString(normalized[last:match[0]])
+		last = match[1]
+
+		group := func(name string) string {
+			for i, subexp := range names {
+				if subexp == name && match[2*i] >= 0 {
+					return normalized[match[2*i]:match[2*i+1]]
+				}
+			}
+			return ""
+		}
+
+		switch {
+		case group("range") != "":
+			interval, err := parseClockRange(group("range"))
+			if err != nil {
+				return nil, err
+			}
+			if len(pending) > 0 {
+				current, pending, undated = pending, nil, false
+			} else if current == nil {
+				current = everyDay()
+			}
+			ranges = append(ranges, interval)
+			for _, day := range current {
+				intervals = append(intervals, WeeklyInterval{Weekday: day, Interval: interval})
+			}
+		case group("days") != "":
+			bounds := strings.Split(group("days"), "-")
+			pending = append(pending, dayRange(dayNames[strings.TrimSpace(bounds[0])], dayNames[strings.TrimSpace(bounds[1])])...)
+		case group("day") != "":
+			pending = append(pending, dayNames[group("day")])
+		case group("daily") != "":
+			pending = append(pending, everyDay()...)
+		case group("closed") != "":
+			// Days without hours are closed anyway, the named days only stop collecting
+			pending, current, undated = nil, []time.Weekday{}, false
+		}
+	}
+	leftover.WriteString(normalized[last:])
+
+	if len(pending) > 0 {
+		if !undated || len(ranges) == 0 {
+			return nil, fmt.Errorf("%w: days without hours in %q", ErrUnparsedOpenedTime, text)
+		}
+
+		intervals = nil
+		for _, day := range pending {
+			for _, interval := range ranges {
+				intervals = append(intervals, WeeklyInterval{Weekday: day, Interval: interval})
+			}
+		}
+	}
+	if digitPattern.MatchString(leftover.String()) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrUnparsedOpenedTime, strings.TrimSpace(leftover.String()))
+	}
+	if len(intervals) == 0 {
+		return nil, fmt.Errorf("%w: no hours in %q", ErrUnparsedOpenedTime, text)
+	}
+
+	SortWeekly(intervals)
+	return intervals, nil
+}
+
+// parseClockRange reads "8-17", "08.00 - 17.00" or "9am-5pm", a lone pm on the closing time makes it afternoon
+func parseClockRange(value string) (Interval, error) {
+	parts := clockRangePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if parts == nil {
+		return Interval{}, fmt.Errorf("%w: hours %q", ErrUnparsedOpenedTime, value)
+	}
+
+	opens, err := clockFromParts(parts[1], parts[2], parts[3])
+	if err != nil {
+		return Interval{}, err
+	}
+	closes, err := clockFromParts(parts[4], parts[5], parts[6])
+	if err != nil {
+		return Interval{}, err
+	}
+
+	interval, err := NewInterval(opens.String(), closes.String())
+	if err != nil {
+		return Interval{}, fmt.Errorf("%w: %v", ErrUnparsedOpenedTime, err)
+	}
+	return interval, nil
+}
+
+func clockFromParts(hours, minutes, meridiem string) (Clock, error) {
+	hour, _ := strconv.Atoi(hours)
+	minute := 0
+	if minutes != "" {
+		minute, _ = strconv.Atoi(minutes)
+	}
+
+	switch meridiem {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 24 || minute > 59 || (hour == 24 && minute > 0) {
+		return 0, fmt.Errorf("%w: time %s:%s out of range", ErrUnparsedOpenedTime, hours, minutes)
+	}
+	return Clock(hour*60 + minute), nil
+}
+
+// dayRange lists the days from first to last, wrapping past Saturday
+func dayRange(first, last time.Weekday) []time.Weekday {
+	days := []time.Weekday{first}
+	for day := first; day != last; {
+		day = (day + 1) % 7
+		days = append(days, day)
+	}
+	return days
+}
+
+func everyDay() []time.Weekday {
+	return dayRange(time.Sunday, time.Saturday)
+}
@@ -0,0 +1,134 @@
+package local
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// formatWeekly writes intervals as "mon 08:00-17:00, tue 08:00-17:00" to keep the expectations readable
+func formatWeekly(intervals []WeeklyInterval) string {
+	parts := make([]string, len(intervals))
+	for i, interval := range intervals {
+		parts[i] = strings.ToLower(interval.Weekday.String()[:3]) + " " + interval.Opens.String() + "-" + interval.Closes.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestParseOpenedTime(t *testing.T) {
+	const weekdays = "mon 08:00-17:00, tue 08:00-17:00, wed 08:00-17:00, thu 08:00-17:00, fri 08:00-17:00"
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "hours without days apply to every day",
+			text: "08.00-17.00",
+			want: "sun 08:00-17:00, mon 08:00-17:00, tue 08:00-17:00, wed 08:00-17:00, thu 08:00-17:00, fri 08:00-17:00, sat 08:00-17:00",
+		},
+		{
+			name: "day range",
+			text: "Senin-Jumat 08.00-17.00",
+			want: weekdays,
+		},
+		{
+			name: "day range with s/d and an en dash",
+			text: "Senin s/d Jumat 08.00 – 17.00",
+			want: weekdays,
+		},
+		{
+			name: "several day groups",
+			text: "Senin-Jumat 08.00-17.00, Sabtu 09:00-14:00",
+			want: weekdays + ", sat 09:00-14:00",
+		},
+		{
+			name: "closed days are left out",
+			text: "Mon-Sat 10:00-22:00, Sunday closed",
+			want: "mon 10:00-22:00, tue 10:00-22:00, wed 10:00-22:00, thu 10:00-22:00, fri 10:00-22:00, sat 10:00-22:00",
+		},
+		{
+			name: "am and pm",
+			text: "10am-10pm",
+			want: "sun 10:00-22:00, mon 10:00-22:00, tue 10:00-22:00, wed 10:00-22:00, thu 10:00-22:00, fri 10:00-22:00, sat 10:00-22:00",
+		},
+		{
+			name: "12am is midnight and 12pm is noon",
+			text: "Sabtu 12pm-12am",
+			want: "sat 12:00-24:00",
+		},
+		{
+			name: "daily with a time zone",
+			text: "Setiap hari 07.00 - 21.00 WITA",
+			want: "sun 07:00-21:00, mon 07:00-21:00, tue 07:00-21:00, wed 07:00-21:00, thu 07:00-21:00, fri 07:00-21:00, sat 07:00-21:00",
+		},
+		{
+			name: "days after the hours",
+			text: "07.00-21.00 setiap hari",
+			want: "sun 07:00-21:00, mon 07:00-21:00, tue 07:00-21:00, wed 07:00-21:00, thu 07:00-21:00, fri 07:00-21:00, sat 07:00-21:00",
+		},
+		{
+			name: "open all day",
+			text: "Buka 24 jam",
+			want: "sun 00:00-24:00, mon 00:00-24:00, tue 00:00-24:00, wed 00:00-24:00, thu 00:00-24:00, fri 00:00-24:00, sat 00:00-24:00",
+		},
+		{
+			name: "overnight hours",
+			text: "Jumat-Sabtu 18:00-02:00",
+			want: "fri 18:00-02:00, sat 18:00-02:00",
+		},
+		{
+			name: "day range wrapping past Saturday",
+			text: "Sabtu-Senin 09:00-15:00",
+			want: "sun 09:00-15:00, mon 09:00-15:00, sat 09:00-15:00",
+		},
+		{
+			name: "split shift on the same days",
+			text: "Senin 08:00-12:00 13:00-16:00",
+			want: "mon 08:00-12:00, mon 13:00-16:00",
+		},
+		{
+			name: "abbreviated English days",
+			text: "Tue-Thu 9-17",
+			want: "tue 09:00-17:00, wed 09:00-17:00, thu 09:00-17:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOpenedTime(tt.text)
+			if err != nil {
+				t.Fatalf("ParseOpenedTime(%q) error = %v", tt.text, err)
+			}
+			if formatWeekly(got) != tt.want {
+				t.Errorf("ParseOpenedTime(%q)\n got  %s\n want %s", tt.text, formatWeekly(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOpenedTimeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "empty", text: "   "},
+		{name: "no hours", text: "Buka setiap hari"},
+		{name: "days without hours after hours", text: "Senin 08:00-17:00, Selasa"},
+		{name: "out of range hour", text: "08.00-25.00"},
+		{name: "out of range minute", text: "08.75-17.00"},
+		{name: "empty interval", text: "08.00-08.00"},
+		{name: "unexplained number", text: "Senin-Jumat 08.00-17.00, lantai 2"},
+		{name: "only a phone number", text: "0812345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOpenedTime(tt.text)
+			if !errors.Is(err, ErrUnparsedOpenedTime) {
+				t.Errorf("ParseOpenedTime(%q) = %s, %v, want %v", tt.text, formatWeekly(got), err, ErrUnparsedOpenedTime)
+			}
+		})
+	}
+}
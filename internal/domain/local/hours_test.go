@@ -0,0 +1,203 @@
+package local
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Clock
+		wantErr bool
+	}{
+		{value: "08:00", want: 8 * 60},
+		{value: "8.30", want: 8*60 + 30},
+		{value: "17:45:00", want: 17*60 + 45},
+		{value: "24:00", want: EndOfDay},
+		{value: " 00:00 ", want: 0},
+		{value: "24:01", wantErr: true},
+		{value: "12:60", wantErr: true},
+		{value: "12:5", wantErr: true},
+		{value: "12", wantErr: true},
+		{value: "ab:cd", wantErr: true},
+		{value: "-1:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseClock(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseClock(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		opens, closes string
+		want          Interval
+		wantOvernight bool
+		wantErr       bool
+	}{
+		{name: "daytime", opens: "08:00", closes: "17:00", want: Interval{Opens: 8 * 60, Closes: 17 * 60}},
+		{name: "midnight close", opens: "18:00", closes: "00:00", want: Interval{Opens: 18 * 60, Closes: EndOfDay}},
+		{name: "whole day", opens: "00:00", closes: "24:00", want: Interval{Opens: 0, Closes: EndOfDay}},
+		{name: "overnight", opens: "22:00", closes: "02:00", want: Interval{Opens: 22 * 60, Closes: 2 * 60}, wantOvernight: true},
+		{name: "empty", opens: "09:00", closes: "09:00", wantErr: true},
+		{name: "opens at the end of the day", opens: "24:00", closes: "02:00", wantErr: true},
+		{name: "malformed", opens: "9", closes: "17:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewInterval(tt.opens, tt.closes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewInterval(%q, %q) error = %v, wantErr %v", tt.opens, tt.closes, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want || got.Overnight() != tt.wantOvernight {
+				t.Errorf("NewInterval(%q, %q) = %+v overnight %v, want %+v overnight %v", tt.opens, tt.closes, got, got.Overnight(), tt.want, tt.wantOvernight)
+			}
+		})
+	}
+}
+
+func TestTimezoneForProvince(t *testing.T) {
+	tests := []struct {
+		province string
+		want     string
+	}{
+		{province: "DKI Jakarta", want: TimezoneWIB},
+		{province: "Kalimantan Barat", want: TimezoneWIB},
+		{province: "Kalimantan Timur", want: TimezoneWITA},
+		{province: "North Kalimantan", want: TimezoneWITA},
+		{province: "Bali", want: TimezoneWITA},
+		{province: "Nusa Tenggara Timur", want: TimezoneWITA},
+		{province: "Sulawesi Selatan", want: TimezoneWITA},
+		{province: "Maluku Utara", want: TimezoneWIT},
+		{province: "Papua Barat Daya", want: TimezoneWIT},
+		{province: "", want: TimezoneWIB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.province, func(t *testing.T) {
+			if got := TimezoneForProvince(tt.province); got != tt.want {
+				t.Errorf("TimezoneForProvince(%q) = %s, want %s", tt.province, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleStatus(t *testing.T) {
+	jakarta, err := time.LoadLocation(TimezoneWIB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// at is a local time in the week starting Monday 3 March 2025, day 0 is that Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.March, 3+day, hour, minute, 0, 0, jakarta)
+	}
+	interval := func(opens, closes string) Interval {
+		i, err := NewInterval(opens, closes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+
+	schedule := Schedule{
+		Location: jakarta,
+		Weekly: []WeeklyInterval{
+			{Weekday: time.Monday, Interval: interval("08:00", "17:00")},
+			{Weekday: time.Tuesday, Interval: interval("08:00", "24:00")},
+			{Weekday: time.Wednesday, Interval: interval("00:00", "06:00")},
+			{Weekday: time.Thursday, Interval: interval("08:00", "17:00")},
+			{Weekday: time.Friday, Interval: interval("08:00", "17:00")},
+			{Weekday: time.Saturday, Interval: interval("20:00", "02:00")},
+		},
+		Exceptions: map[string][]Interval{
+			"2025-03-06": {interval("10:00", "12:00")}, // Thursday, shorter hours
+			"2025-03-07": {},                          // Friday, closed
+		},
+		Holidays:         map[string]bool{"2025-03-10": true}, // the next Monday
+		ClosedOnHolidays: true,
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       time.Time
+		wantOpen bool
+		wantNext *time.Time
+	}{
+		{name: "before opening", schedule: schedule, at: at(0, 7, 0), wantNext: ptr(at(0, 8, 0))},
+		{name: "opening time is open", schedule: schedule, at: at(0, 8, 0), wantOpen: true, wantNext: ptr(at(0, 17, 0))},
+		{name: "closing time is closed", schedule: schedule, at: at(0, 17, 0), wantNext: ptr(at(1, 8, 0))},
+		{name: "closing at midnight and opening at midnight is no change", schedule: schedule, at: at(1, 23, 0), wantOpen: true, wantNext: ptr(at(2, 6, 0))},
+		{name: "after midnight of a merged period", schedule: schedule, at: at(2, 3, 0), wantOpen: true, wantNext: ptr(at(2, 6, 0))},
+		{name: "exception replaces the weekly hours", schedule: schedule, at: at(3, 9, 0), wantNext: ptr(at(3, 10, 0))},
+		{name: "inside the exception hours", schedule: schedule, at: at(3, 11, 0), wantOpen: true, wantNext: ptr(at(3, 12, 0))},
+		{name: "closed exception skips the day", schedule: schedule, at: at(4, 12, 0), wantNext: ptr(at(5, 20, 0))},
+		{name: "overnight interval of the day before", schedule: schedule, at: at(6, 1, 0), wantOpen: true, wantNext: ptr(at(6, 2, 0))},
+		{name: "closed public holiday is skipped", schedule: schedule, at: at(6, 3, 0), wantNext: ptr(at(8, 8, 0))},
+		{name: "instants in other zones are converted", schedule: schedule, at: time.Date(2025, time.March, 3, 1, 0, 0, 0, time.UTC), wantOpen: true, wantNext: ptr(at(0, 17, 0))},
+		{
+			name:     "public holiday ignored when the business stays open",
+			schedule: withHolidaysOpen(schedule),
+			at:       at(7, 9, 0),
+			wantOpen: true,
+			wantNext: ptr(at(7, 17, 0)),
+		},
+		{
+			name:     "no change within the horizon",
+			schedule: Schedule{Location: jakarta, Exceptions: map[string][]Interval{"2025-04-01": {interval("08:00", "17:00")}}},
+			at:       at(0, 12, 0),
+		},
+		{
+			name:     "always open has no change",
+			schedule: Schedule{Location: jakarta, Weekly: everyDayInterval(interval("00:00", "24:00"))},
+			at:       at(0, 12, 0),
+			wantOpen: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, next := tt.schedule.Status(tt.at)
+			if open != tt.wantOpen {
+				t.Errorf("open = %v, want %v", open, tt.wantOpen)
+			}
+			switch {
+			case next == nil && tt.wantNext == nil:
+			case next == nil || tt.wantNext == nil || !next.Equal(*tt.wantNext):
+				t.Errorf("next change = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func withHolidaysOpen(schedule Schedule) Schedule {
+	schedule.ClosedOnHolidays = false
+	return schedule
+}
+
+func everyDayInterval(interval Interval) []WeeklyInterval {
+	weekly := make([]WeeklyInterval, 0, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		weekly = append(weekly, WeeklyInterval{Weekday: day, Interval: interval})
+	}
+	return weekly
+}
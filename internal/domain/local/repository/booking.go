@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// GetTourGuideBookingByID retrieves a tour guide booking by its ID
+func (r *localRepository) GetTourGuideBookingByID(ctx context.Context, booking *local.TourGuideBookings) error {
+	query := `
+		SELECT 
+			id, payment_url, booked_at, expires_at, status, amount, user_id, tourist_attraction_id,
+			created_at, updated_at
+		FROM tourguide_bookings
+		WHERE id = $1`
+
+	row := r.queryExecutor.QueryRowxContext(ctx, query, booking.ID)
+	if err := row.StructScan(booking); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return local.ErrBookingNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CreateBookingStatusHistory records a booking status transition
+func (r *localRepository) CreateBookingStatusHistory(ctx context.Context, history *local.BookingStatusHistory) error {
+	query := `
+		INSERT INTO booking_status_history (
+			id, booking_id, from_status, to_status, actor, reason, created_at
+		) VALUES (
+			:id, :booking_id, NULLIF(:from_status, ''), :to_status, :actor, :reason, NOW()
+		)`
+
+	_, err := r.queryExecutor.NamedExecContext(ctx, query, history)
+	return err
+}
+
+// GetBookingStatusHistory retrieves the status transitions of a booking in chronological order
+func (r *localRepository) GetBookingStatusHistory(ctx context.Context, bookingID string, out *[]local.BookingStatusHistory) error {
+	query := `
+		SELECT 
+			id, booking_id, COALESCE(from_status, '') AS from_status, to_status,
+			actor, reason, created_at
+		FROM booking_status_history
+		WHERE booking_id = $1
+		ORDER BY created_at ASC`
+
+	rows, err := r.queryExecutor.QueryxContext(ctx, query, bookingID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var result []local.BookingStatusHistory
+	for rows.Next() {
+		var history local.BookingStatusHistory
+		if err := rows.StructScan(&history); err != nil {
+			return err
+		}
+		result = append(result, history)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
+
+// GetExpiredPendingBookingIDs retrieves up to limit unpaid bookings whose hold has expired, oldest first
+func (r *localRepository) GetExpiredPendingBookingIDs(ctx context.Context, limit int, out *[]uuid.UUID) error {
+	query := `
+		SELECT id
+		FROM tourguide_bookings
+		WHERE status = 'pending_payment'
+			AND expires_at <= NOW()
+		ORDER BY expires_at ASC
+		LIMIT $1`
+
+	rows, err := r.queryExecutor.QueryxContext(ctx, query, limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var result []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		result = append(result, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
@@ -9,77 +9,62 @@ import (
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
 )
 
-// GetAllLocalBusinesses retrieves all local businesses with optional city and type filtering
+// localBusinessListingColumns selects a local business aliased as l along with its average review rating, kept up to date by a trigger
+const localBusinessListingColumns = `
+			l.id, l.name, l.description, l.address, l.city, l.province, l.longitude, l.latitude, 
+			l.label, l.opened_time, l.photo_url, l.is_business, l.timezone, l.closed_on_public_holidays,
+			l.owner_id, l.created_at, l.updated_at,
+			l.rating`
+
+// GetAllLocalBusinesses retrieves one page of local businesses matching the filters, params must be normalized by the service
 func (r *localRepository) GetAllLocalBusinesses(ctx context.Context, params local.QueryParamRequestGetLocals, out *[]local.Locals) error {
-	query := `
-		SELECT 
-			id, name, description, address, city, province, longitude, latitude, 
-			label, opened_time, photo_url, is_business, created_at, updated_at
-		FROM locals
+	listing := `SELECT ` + localBusinessListingColumns + `
+		FROM locals l
 		WHERE 1=1`
 
 	queryParams := make(map[string]interface{})
 
 	if params.City != "" {
-		query += " AND LOWER(city) LIKE :city"
+		listing += " AND LOWER(l.city) LIKE :city"
 		queryParams["city"] = "%" + strings.ToLower(params.City) + "%"
 	}
 
-	if params.Type == "business" {
-		query += " AND is_business = true"
-	} else if params.Type == "individual" {
-		query += " AND is_business = false"
+	if params.Province != "" {
+		listing += " AND LOWER(l.province) = :province"
+		queryParams["province"] = strings.ToLower(params.Province)
 	}
 
-	query += " ORDER BY created_at DESC"
-
-	// If no city filter, use regular query
-	if params.City == "" {
-		rows, err := r.queryExecutor.QueryxContext(ctx, query)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		var result []local.Locals
-		for rows.Next() {
-			var business local.Locals
-			if err := rows.StructScan(&business); err != nil {
-				return err
-			}
-			result = append(result, business)
-		}
-
-		if err := rows.Err(); err != nil {
-			return err
-		}
-
-		*out = result
-		return nil
+	if params.Label != "" {
+		listing += " AND LOWER(l.label) = :label"
+		queryParams["label"] = strings.ToLower(params.Label)
 	}
 
-	// Use named query for city filtering
-	rows, err := r.queryExecutor.NamedQueryContext(ctx, query, queryParams)
-	if err != nil {
-		return err
+	if params.Category != "" {
+		listing += categoryFilter("locals", "l", params.Category, queryParams)
 	}
-	defer rows.Close()
 
-	var result []local.Locals
-	for rows.Next() {
-		var business local.Locals
-		if err := rows.StructScan(&business); err != nil {
-			return err
-		}
-		result = append(result, business)
+	if params.OpenAtTime != nil {
+		listing += " AND local_is_open(l.id, l.timezone, :open_at)"
+		queryParams["open_at"] = *params.OpenAtTime
 	}
 
-	if err := rows.Err(); err != nil {
-		return err
+	if params.Type == "business" {
+		listing += " AND l.is_business = true"
+	} else if params.Type == "individual" {
+		listing += " AND l.is_business = false"
 	}
 
-	*out = result
-	return nil
+	query := pageCatalogue(listing, local.CatalogueSort(params.Sort), params.Order, params.After, params.Limit, queryParams)
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
+}
+
+// GetNearbyLocalBusinesses retrieves one page of local businesses within the radius, nearest first
+func (r *localRepository) GetNearbyLocalBusinesses(ctx context.Context, params local.QueryParamRequestNearby, out *[]local.Locals) error {
+	queryParams := make(map[string]interface{})
+	listing := nearbyListing(localBusinessListingColumns, "locals", "l", params, queryParams)
+
+	query := pageCatalogue(listing, local.SortDistance, local.SortDistance.DefaultOrder(), params.After, params.Limit, queryParams)
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
 }
 
 // GetLocalBusinessByID retrieves a local business by its ID
@@ -87,7 +72,8 @@ func (r *localRepository) GetLocalBusinessByID(ctx context.Context, business *lo
 	query := `
 		SELECT 
 			id, name, description, address, city, province, longitude, latitude, 
-			label, opened_time, photo_url, is_business, created_at, updated_at
+			label, opened_time, photo_url, is_business, timezone, closed_on_public_holidays,
+			owner_id, created_at, updated_at
 		FROM locals
 		WHERE id = $1`
 
@@ -102,15 +88,40 @@ func (r *localRepository) GetLocalBusinessByID(ctx context.Context, business *lo
 	return nil
 }
 
+// GetLocalBusinessByIDForUpdate retrieves a local business by its ID and locks the row until the
+// surrounding transaction ends, so concurrent edits of the listing and its hours apply one after another
+func (r *localRepository) GetLocalBusinessByIDForUpdate(ctx context.Context, business *local.Locals) error {
+	query := `
+		SELECT 
+			id, name, description, address, city, province, longitude, latitude, 
+			label, opened_time, photo_url, is_business, timezone, closed_on_public_holidays,
+			owner_id, created_at, updated_at
+		FROM locals
+		WHERE id = $1
+		FOR UPDATE`
+
+	row := r.queryExecutor.QueryRowxContext(ctx, query, business.ID)
+	if err := row.StructScan(business); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return local.ErrLBNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
 // CreateLocalBusiness creates a new local business in the database
 func (r *localRepository) CreateLocalBusiness(ctx context.Context, business *local.Locals) error {
 	query := `
 		INSERT INTO locals (
 			id, name, description, address, city, province, longitude, latitude,
-			label, opened_time, photo_url, is_business, created_at, updated_at
+			label, opened_time, photo_url, is_business, timezone, closed_on_public_holidays,
+			owner_id, created_at, updated_at
 		) VALUES (
 			:id, :name, :description, :address, :city, :province, :longitude, :latitude,
-			:label, :opened_time, :photo_url, :is_business, :created_at, :updated_at
+			:label, :opened_time, :photo_url, :is_business, :timezone, :closed_on_public_holidays,
+			:owner_id, :created_at, :updated_at
 		)`
 
 	_, err := r.queryExecutor.NamedExecContext(ctx, query, business)
@@ -136,6 +147,8 @@ func (r *localRepository) UpdateLocalBusiness(ctx context.Context, business *loc
 			opened_time = :opened_time,
 			photo_url = :photo_url,
 			is_business = :is_business,
+			timezone = :timezone,
+			closed_on_public_holidays = :closed_on_public_holidays,
 			updated_at = :updated_at
 		WHERE id = :id`
 
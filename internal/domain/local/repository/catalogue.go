@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/geo"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
+)
+
+// sortColumns maps each catalogue sort to its column on the listing subquery and the type its cursor value is cast to
+var sortColumns = map[local.CatalogueSort]struct {
+	column string
+	cast   string
+}{
+	local.SortName:      {"t.name", "VARCHAR"},
+	local.SortCreatedAt: {"t.created_at", "TIMESTAMP"},
+	local.SortPrice:     {"t.price", "BIGINT"},
+	local.SortRating:    {"t.rating", "DOUBLE PRECISION"},
+	local.SortDistance:  {"t.distance", "DOUBLE PRECISION"},
+	local.SortRelevance: {"t.rank", "DOUBLE PRECISION"},
+}
+
+// pageCatalogue wraps a listing query, aliased as t, with keyset pagination on (sort column, id).
+// sort and order must already be validated and limit already bounded, one extra row is fetched to detect the next page.
+func pageCatalogue(listing string, sort local.CatalogueSort, order string, after *pagination.Cursor, limit int, params map[string]interface{}) string {
+	column := sortColumns[sort]
+
+	direction, comparison := "ASC", ">"
+	if order == pagination.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	query := fmt.Sprintf("SELECT * FROM (%s) t", listing)
+	if after != nil {
+		query += fmt.Sprintf(" WHERE (%s, t.id) %s (CAST(:cursor_value AS %s), CAST(:cursor_id AS UUID))", column.column, comparison, column.cast)
+		params["cursor_value"] = after.Value
+		params["cursor_id"] = after.ID
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, t.id %s LIMIT :limit", column.column, direction, direction)
+	params["limit"] = limit + 1
+
+	return query
+}
+
+// nearbyListing selects the listing columns of table, aliased as alias, with the haversine distance in whole metres
+// from the searched point and keeps the rows within the radius, and in the category when one is given.
+// The indexed bounding box narrows the rows first.
+// params must be normalized by the service.
+func nearbyListing(columns, table, alias string, params local.QueryParamRequestNearby, queryParams map[string]interface{}) string {
+	box := geo.BoundingBox(*params.Latitude, *params.Longitude, params.Radius)
+
+	listing := fmt.Sprintf(`SELECT * FROM (
+		SELECT %[1]s,
+			CAST(ROUND(2 * %[4]f * ASIN(LEAST(1, SQRT(
+				POWER(SIN(RADIANS(%[3]s.latitude - CAST(:latitude AS DOUBLE PRECISION)) / 2), 2) +
+				COS(RADIANS(CAST(:latitude AS DOUBLE PRECISION))) * COS(RADIANS(%[3]s.latitude)) *
+				POWER(SIN(RADIANS(%[3]s.longitude - CAST(:longitude AS DOUBLE PRECISION)) / 2), 2)
+			)))) AS DOUBLE PRECISION) AS distance
+		FROM %[2]s %[3]s
+		WHERE %[3]s.latitude BETWEEN :min_latitude AND :max_latitude`, columns, table, alias, geo.EarthRadiusMetres)
+
+	queryParams["latitude"] = *params.Latitude
+	queryParams["longitude"] = *params.Longitude
+	queryParams["min_latitude"] = box.MinLatitude
+	queryParams["max_latitude"] = box.MaxLatitude
+
+	if !box.WrapsLongitude {
+		listing += fmt.Sprintf(" AND %[1]s.longitude BETWEEN :min_longitude AND :max_longitude", alias)
+		queryParams["min_longitude"] = box.MinLongitude
+		queryParams["max_longitude"] = box.MaxLongitude
+	}
+
+	if params.Category != "" {
+		listing += categoryFilter(table, alias, params.Category, queryParams)
+	}
+
+	listing += `
+	) n WHERE n.distance <= :radius`
+	queryParams["radius"] = params.Radius
+
+	return listing
+}
+
+// queryCatalogue runs a named catalogue query and scans every row into out
+func queryCatalogue[T any](ctx context.Context, q namedExtension, query string, params map[string]interface{}, out *[]T) error {
+	rows, err := q.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		var item T
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
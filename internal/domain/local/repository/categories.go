@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// categoryLinks names the table linking each catalogue table to categories and its listing column
+var categoryLinks = map[string]struct {
+	table  string
+	column string
+}{
+	"locals":              {"local_categories", "local_id"},
+	"tourist_attractions": {"tourist_attraction_categories", "tourist_attraction_id"},
+}
+
+// categoryFilter keeps the rows of table, aliased as alias, filed under the category :category or any of its subcategories
+func categoryFilter(table, alias, slug string, queryParams map[string]interface{}) string {
+	link := categoryLinks[table]
+	queryParams["category"] = slug
+
+	return fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM %[1]s lc
+			WHERE lc.%[2]s = %[3]s.id AND lc.category_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT id FROM categories WHERE slug = :category
+					UNION
+					SELECT c.id FROM categories c INNER JOIN tree ON c.parent_id = tree.id
+				)
+				SELECT id FROM tree
+			)
+		)`, link.table, link.column, alias)
+}
+
+// getListingCategories retrieves the categories linked to a listing of table
+func (r *localRepository) getListingCategories(ctx context.Context, table string, listingID uuid.UUID, out *[]local.ListingCategory) error {
+	link := categoryLinks[table]
+	query := fmt.Sprintf(`
+		SELECT lc.%[2]s AS listing_id, c.id, c.parent_id, c.slug, c.names, c.icon
+		FROM %[1]s lc
+		INNER JOIN categories c ON c.id = lc.category_id
+		WHERE lc.%[2]s = :listing_id
+		ORDER BY c.position, c.slug`, link.table, link.column)
+
+	return queryCatalogue(ctx, r.queryExecutor, query, map[string]interface{}{"listing_id": listingID}, out)
+}
+
+// replaceListingCategories swaps the categories linked to a listing of table for categoryIDs
+func (r *localRepository) replaceListingCategories(ctx context.Context, table string, listingID uuid.UUID, categoryIDs []uuid.UUID) error {
+	link := categoryLinks[table]
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, link.table, link.column)
+	if _, err := r.queryExecutor.ExecContext(ctx, query, listingID); err != nil {
+		return err
+	}
+
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	insert := fmt.Sprintf(`
+		INSERT INTO %s (%s, category_id)
+		SELECT $1, UNNEST(CAST($2 AS UUID[]))
+		ON CONFLICT DO NOTHING`, link.table, link.column)
+
+	if _, err := r.queryExecutor.ExecContext(ctx, insert, listingID, pq.Array(uuidStrings(categoryIDs))); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+			return local.ErrUnknownCategory
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetLocalBusinessCategories retrieves the categories a local business is filed under
+func (r *localRepository) GetLocalBusinessCategories(ctx context.Context, businessID uuid.UUID, out *[]local.ListingCategory) error {
+	return r.getListingCategories(ctx, "locals", businessID, out)
+}
+
+// ReplaceLocalBusinessCategories files a local business under exactly the given categories
+func (r *localRepository) ReplaceLocalBusinessCategories(ctx context.Context, businessID uuid.UUID, categoryIDs []uuid.UUID) error {
+	return r.replaceListingCategories(ctx, "locals", businessID, categoryIDs)
+}
+
+// GetTouristAttractionCategories retrieves the categories a tourist attraction is filed under
+func (r *localRepository) GetTouristAttractionCategories(ctx context.Context, attractionID uuid.UUID, out *[]local.ListingCategory) error {
+	return r.getListingCategories(ctx, "tourist_attractions", attractionID, out)
+}
+
+// ReplaceTouristAttractionCategories files a tourist attraction under exactly the given categories
+func (r *localRepository) ReplaceTouristAttractionCategories(ctx context.Context, attractionID uuid.UUID, categoryIDs []uuid.UUID) error {
+	return r.replaceListingCategories(ctx, "tourist_attractions", attractionID, categoryIDs)
+}
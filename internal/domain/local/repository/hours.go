@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// GetOpeningHours retrieves the weekly intervals of the given local businesses
+func (r *localRepository) GetOpeningHours(ctx context.Context, localIDs []uuid.UUID, out *[]local.OpeningHours) error {
+	query := `
+		SELECT local_id, weekday, opens_at, closes_at
+		FROM local_opening_hours
+		WHERE local_id = ANY(CAST(:local_ids AS UUID[]))
+		ORDER BY local_id, weekday, opens_at`
+
+	return queryCatalogue(ctx, r.queryExecutor, query, map[string]interface{}{
+		"local_ids": pq.Array(uuidStrings(localIDs)),
+	}, out)
+}
+
+// ReplaceOpeningHours swaps the weekly intervals of a local business for the given ones
+func (r *localRepository) ReplaceOpeningHours(ctx context.Context, localID uuid.UUID, hours []local.OpeningHours) error {
+	if _, err := r.queryExecutor.ExecContext(ctx, `DELETE FROM local_opening_hours WHERE local_id = $1`, localID); err != nil {
+		return err
+	}
+
+	if len(hours) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO local_opening_hours (local_id, weekday, opens_at, closes_at)
+		VALUES (:local_id, :weekday, :opens_at, :closes_at)`
+
+	_, err := r.queryExecutor.NamedExecContext(ctx, query, hours)
+	return err
+}
+
+// GetOpeningExceptions retrieves the exceptions of the given local businesses dated from from to to, both included
+func (r *localRepository) GetOpeningExceptions(ctx context.Context, localIDs []uuid.UUID, from, to time.Time, out *[]local.OpeningException) error {
+	query := `
+		SELECT local_id, date, opens_at, closes_at, note
+		FROM local_opening_exceptions
+		WHERE local_id = ANY(CAST(:local_ids AS UUID[])) AND date BETWEEN :from AND :to
+		ORDER BY local_id, date, opens_at NULLS FIRST`
+
+	return queryCatalogue(ctx, r.queryExecutor, query, map[string]interface{}{
+		"local_ids": pq.Array(uuidStrings(localIDs)),
+		"from":      from.Format(local.DateLayout),
+		"to":        to.Format(local.DateLayout),
+	}, out)
+}
+
+// ReplaceOpeningException swaps the exception rows of a local business on a date for the given ones
+func (r *localRepository) ReplaceOpeningException(ctx context.Context, localID uuid.UUID, date time.Time, exceptions []local.OpeningException) error {
+	query := `DELETE FROM local_opening_exceptions WHERE local_id = $1 AND date = $2`
+	if _, err := r.queryExecutor.ExecContext(ctx, query, localID, date.Format(local.DateLayout)); err != nil {
+		return err
+	}
+
+	insert := `
+		INSERT INTO local_opening_exceptions (local_id, date, opens_at, closes_at, note)
+		VALUES (:local_id, :date, :opens_at, :closes_at, :note)`
+
+	_, err := r.queryExecutor.NamedExecContext(ctx, insert, exceptions)
+	return err
+}
+
+// DeleteOpeningException removes every exception row of a local business on a date
+func (r *localRepository) DeleteOpeningException(ctx context.Context, localID uuid.UUID, date time.Time) error {
+	query := `DELETE FROM local_opening_exceptions WHERE local_id = $1 AND date = $2`
+
+	result, err := r.queryExecutor.ExecContext(ctx, query, localID, date.Format(local.DateLayout))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return local.ErrExceptionNotFound
+	}
+
+	return nil
+}
+
+// GetPublicHolidays retrieves the public holidays from from to to, both included
+func (r *localRepository) GetPublicHolidays(ctx context.Context, from, to time.Time, out *[]local.PublicHoliday) error {
+	query := `
+		SELECT date, name
+		FROM public_holidays
+		WHERE date BETWEEN :from AND :to
+		ORDER BY date`
+
+	return queryCatalogue(ctx, r.queryExecutor, query, map[string]interface{}{
+		"from": from.Format(local.DateLayout),
+		"to":   to.Format(local.DateLayout),
+	}, out)
+}
+
+// UpsertPublicHoliday creates a public holiday or renames the one on its date
+func (r *localRepository) UpsertPublicHoliday(ctx context.Context, holiday *local.PublicHoliday) error {
+	query := `
+		INSERT INTO public_holidays (date, name)
+		VALUES ($1, $2)
+		ON CONFLICT (date) DO UPDATE SET name = EXCLUDED.name`
+
+	_, err := r.queryExecutor.ExecContext(ctx, query, holiday.Date.Format(local.DateLayout), holiday.Name)
+	return err
+}
+
+// DeletePublicHoliday removes the public holiday on a date
+func (r *localRepository) DeletePublicHoliday(ctx context.Context, date time.Time) error {
+	result, err := r.queryExecutor.ExecContext(ctx, `DELETE FROM public_holidays WHERE date = $1`, date.Format(local.DateLayout))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return local.ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+// GetLocalBusinessesWithLegacyHours retrieves the local businesses with a free-text opened_time but no structured hours
+func (r *localRepository) GetLocalBusinessesWithLegacyHours(ctx context.Context, out *[]local.Locals) error {
+	query := `
+		SELECT id, name, province, opened_time, timezone
+		FROM locals l
+		WHERE TRIM(l.opened_time) <> ''
+			AND NOT EXISTS (SELECT 1 FROM local_opening_hours h WHERE h.local_id = l.id)
+		ORDER BY l.created_at`
+
+	return queryCatalogue(ctx, r.queryExecutor, query, map[string]interface{}{}, out)
+}
+
+// uuidStrings formats IDs for a Postgres UUID array parameter
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
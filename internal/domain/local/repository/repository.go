@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
 )
 
@@ -34,23 +37,55 @@ type LocalRepositoryInterface interface {
 	// Transaction management
 	Commit() error
 	Rollback() error
+	AuditTx() audit.Tx
 	
 	// Local business operations
 	GetAllLocalBusinesses(ctx context.Context, params local.QueryParamRequestGetLocals, out *[]local.Locals) error
+	GetNearbyLocalBusinesses(ctx context.Context, params local.QueryParamRequestNearby, out *[]local.Locals) error
 	GetLocalBusinessByID(ctx context.Context, data *local.Locals) error
+	GetLocalBusinessByIDForUpdate(ctx context.Context, data *local.Locals) error
 	CreateLocalBusiness(ctx context.Context, business *local.Locals) error
 	UpdateLocalBusiness(ctx context.Context, business *local.Locals) error
 	DeleteLocalBusiness(ctx context.Context, businessID string) error
 	GetReviewsByLocalBusinessID(ctx context.Context, localBusinessID string, out *[]local.Review) error
+	SearchCatalogue(ctx context.Context, params local.QueryParamRequestSearch, out *[]local.SearchResult) error
+	GetSearchFacets(ctx context.Context, params local.QueryParamRequestSearch, out *[]local.SearchFacetCount) error
+
+	// Opening hours operations
+	GetOpeningHours(ctx context.Context, localIDs []uuid.UUID, out *[]local.OpeningHours) error
+	ReplaceOpeningHours(ctx context.Context, localID uuid.UUID, hours []local.OpeningHours) error
+	GetOpeningExceptions(ctx context.Context, localIDs []uuid.UUID, from, to time.Time, out *[]local.OpeningException) error
+	ReplaceOpeningException(ctx context.Context, localID uuid.UUID, date time.Time, exceptions []local.OpeningException) error
+	DeleteOpeningException(ctx context.Context, localID uuid.UUID, date time.Time) error
+	GetPublicHolidays(ctx context.Context, from, to time.Time, out *[]local.PublicHoliday) error
+	UpsertPublicHoliday(ctx context.Context, holiday *local.PublicHoliday) error
+	DeletePublicHoliday(ctx context.Context, date time.Time) error
+	GetLocalBusinessesWithLegacyHours(ctx context.Context, out *[]local.Locals) error
+
+	// Category link operations
+	GetLocalBusinessCategories(ctx context.Context, businessID uuid.UUID, out *[]local.ListingCategory) error
+	ReplaceLocalBusinessCategories(ctx context.Context, businessID uuid.UUID, categoryIDs []uuid.UUID) error
+	GetTouristAttractionCategories(ctx context.Context, attractionID uuid.UUID, out *[]local.ListingCategory) error
+	ReplaceTouristAttractionCategories(ctx context.Context, attractionID uuid.UUID, categoryIDs []uuid.UUID) error
 	
 	// Tourist attraction operations
-	GetAllTouristAttractions(ctx context.Context, city string, out *[]local.TouristAttractions) error
+	GetAllTouristAttractions(ctx context.Context, params local.QueryParamRequestGetTouristAttractions, out *[]local.TouristAttractions) error
+	GetNearbyTouristAttractions(ctx context.Context, params local.QueryParamRequestNearby, out *[]local.TouristAttractions) error
 	GetTouristAttractionByID(ctx context.Context, data *local.TouristAttractions) error
+	GetTouristAttractionByIDForUpdate(ctx context.Context, data *local.TouristAttractions) error
 	CreateTouristAttraction(ctx context.Context, attraction *local.TouristAttractions) error
 	UpdateTouristAttraction(ctx context.Context, attraction *local.TouristAttractions) error
 	DeleteTouristAttraction(ctx context.Context, attractionID string) error
 	GetBookingsByTouristAttractionID(ctx context.Context, attractionID string, out *[]local.TourGuideBookings) error
+	CountActiveBookingsOnDate(ctx context.Context, attractionID string, bookedAt time.Time, count *int) error
 	CreateTourGuideBooking(ctx context.Context, booking *local.TourGuideBookings) error
+	UpdateTourGuideBookingPaymentURL(ctx context.Context, booking *local.TourGuideBookings) error
+	GetTourGuideBookingByIDForUpdate(ctx context.Context, booking *local.TourGuideBookings) error
+	UpdateTourGuideBookingStatus(ctx context.Context, booking *local.TourGuideBookings) error
+	GetTourGuideBookingByID(ctx context.Context, booking *local.TourGuideBookings) error
+	CreateBookingStatusHistory(ctx context.Context, history *local.BookingStatusHistory) error
+	GetBookingStatusHistory(ctx context.Context, bookingID string, out *[]local.BookingStatusHistory) error
+	GetExpiredPendingBookingIDs(ctx context.Context, limit int, out *[]uuid.UUID) error
 	GetFullyBookedDates(ctx context.Context, attractionID string, year, month int, dates *[]string) error
 }
 
@@ -109,6 +144,15 @@ func (lr *localRepository) Commit() error {
 	}
 }
 
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (lr *localRepository) AuditTx() audit.Tx {
+	if executor, ok := lr.queryExecutor.(*transactionWrapper); ok {
+		return executor.Tx
+	}
+
+	return lr.queryExecutor
+}
+
 // Rollback rolls back the transaction if one exists
 func (lr *localRepository) Rollback() error {
 	switch executor := lr.queryExecutor.(type) {
@@ -11,9 +11,9 @@ func (r *localRepository) GetReviewsByLocalBusinessID(ctx context.Context, local
 	query := `
 		SELECT 
 			r.id, r.star, r.content, r.created_at, r.updated_at, 
-			u.photo_url, u.full_name as user_name
+			COALESCE(u.photo_url, '') AS photo_url, COALESCE(u.full_name, 'Deleted user') as user_name
 		FROM reviews r 
-		INNER JOIN users u ON u.id = r.user_id
+		LEFT JOIN users u ON u.id = r.user_id
 		WHERE r.local_id = $1
 		ORDER BY r.created_at DESC`
 
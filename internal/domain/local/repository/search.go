@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// searchMatches selects the local businesses and tourist attractions matching :query, either through the
+// full-text index or a trigram word similarity on the name for misspellings. rank adds both scores.
+func searchMatches(params local.QueryParamRequestSearch, queryParams map[string]interface{}) string {
+	queryParams["query"] = params.Query
+
+	localFilters, attractionFilters := "", ""
+	if params.Province != "" {
+		localFilters += " AND LOWER(l.province) = :province"
+		attractionFilters += " AND LOWER(a.province) = :province"
+		queryParams["province"] = strings.ToLower(params.Province)
+	}
+	if params.Label != "" {
+		localFilters += " AND LOWER(l.label) = :label"
+		queryParams["label"] = strings.ToLower(params.Label)
+	}
+
+	branches := []string{}
+	if params.Type != string(local.ListingTouristAttraction) {
+		branches = append(branches, `
+		SELECT 'local_business' AS type, l.id, l.name, l.description, l.city, l.province, l.label, l.photo_url,
+			CAST(ts_rank_cd(l.search_vector, q.tsquery, 32) + word_similarity(q.normalized, vistara_unaccent(LOWER(l.name))) AS DOUBLE PRECISION) AS rank
+		FROM locals l, q
+		WHERE (l.search_vector @@ q.tsquery OR q.normalized <% vistara_unaccent(LOWER(l.name)))`+localFilters)
+	}
+	// Tourist attractions have no label, filtering by one leaves only local businesses
+	if params.Type != string(local.ListingLocalBusiness) && params.Label == "" {
+		branches = append(branches, `
+		SELECT 'tourist_attraction' AS type, a.id, a.name, a.description, a.city, a.province, '' AS label, a.photo_url,
+			CAST(ts_rank_cd(a.search_vector, q.tsquery, 32) + word_similarity(q.normalized, vistara_unaccent(LOWER(a.name))) AS DOUBLE PRECISION) AS rank
+		FROM tourist_attractions a, q
+		WHERE (a.search_vector @@ q.tsquery OR q.normalized <% vistara_unaccent(LOWER(a.name)))`+attractionFilters)
+	}
+
+	return `
+		WITH q AS (
+			SELECT websearch_to_tsquery('vistara_search', :query) AS tsquery, vistara_unaccent(LOWER(:query)) AS normalized
+		)` + strings.Join(branches, `
+		UNION ALL`)
+}
+
+// SearchCatalogue retrieves one page of search matches, best first, with highlighted names and description snippets.
+// params must be normalized by the service.
+func (r *localRepository) SearchCatalogue(ctx context.Context, params local.QueryParamRequestSearch, out *[]local.SearchResult) error {
+	queryParams := make(map[string]interface{})
+	paged := pageCatalogue(searchMatches(params, queryParams), local.SortRelevance, local.SortRelevance.DefaultOrder(), params.After, params.Limit, queryParams)
+
+	// Highlighting is the expensive part, so it only runs on the rows of the page
+	query := `
+		SELECT p.type, p.id, p.name, p.city, p.province, p.label, p.photo_url, p.rank,
+			ts_headline('vistara_search', p.name, websearch_to_tsquery('vistara_search', :headline_query), :name_options) AS name_highlight,
+			ts_headline('vistara_search', p.description, websearch_to_tsquery('vistara_search', :headline_query), :snippet_options) AS snippet
+		FROM (` + paged + `) p
+		ORDER BY p.rank DESC, p.id DESC`
+
+	delimiters := "StartSel=" + local.SearchHighlightStart + ", StopSel=" + local.SearchHighlightStop
+	queryParams["headline_query"] = params.Query
+	queryParams["name_options"] = delimiters + ", HighlightAll=true"
+	queryParams["snippet_options"] = delimiters + ", MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=\" … \""
+
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
+}
+
+// GetSearchFacets counts every search match by listing type, province and label
+func (r *localRepository) GetSearchFacets(ctx context.Context, params local.QueryParamRequestSearch, out *[]local.SearchFacetCount) error {
+	queryParams := make(map[string]interface{})
+	query := `
+		WITH m AS (` + searchMatches(params, queryParams) + `
+		)
+		SELECT 'type' AS facet, m.type AS value, COUNT(*) AS count FROM m GROUP BY m.type
+		UNION ALL
+		SELECT 'province', m.province, COUNT(*) FROM m GROUP BY m.province
+		UNION ALL
+		SELECT 'label', m.label, COUNT(*) FROM m WHERE m.label <> '' GROUP BY m.label
+		ORDER BY facet, count DESC, value`
+
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
+}
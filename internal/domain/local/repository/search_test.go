@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+func TestSearchMatches(t *testing.T) {
+	tests := []struct {
+		name            string
+		params          local.QueryParamRequestSearch
+		wantBusinesses  bool
+		wantAttractions bool
+		wantParams      map[string]interface{}
+		wantFilters     []string
+	}{
+		{
+			name:            "every listing",
+			params:          local.QueryParamRequestSearch{Query: "pantai kuta"},
+			wantBusinesses:  true,
+			wantAttractions: true,
+			wantParams:      map[string]interface{}{"query": "pantai kuta"},
+		},
+		{
+			name:           "only local businesses",
+			params:         local.QueryParamRequestSearch{Query: "kopi", Type: string(local.ListingLocalBusiness)},
+			wantBusinesses: true,
+			wantParams:     map[string]interface{}{"query": "kopi"},
+		},
+		{
+			name:            "only tourist attractions",
+			params:          local.QueryParamRequestSearch{Query: "pura", Type: string(local.ListingTouristAttraction)},
+			wantAttractions: true,
+			wantParams:      map[string]interface{}{"query": "pura"},
+		},
+		{
+			name:            "province filters both listings case-insensitively",
+			params:          local.QueryParamRequestSearch{Query: "pantai", Province: "Bali"},
+			wantBusinesses:  true,
+			wantAttractions: true,
+			wantParams:      map[string]interface{}{"query": "pantai", "province": "bali"},
+			wantFilters:     []string{"LOWER(l.province) = :province", "LOWER(a.province) = :province"},
+		},
+		{
+			name:           "label leaves only local businesses",
+			params:         local.QueryParamRequestSearch{Query: "kopi", Label: "Kuliner"},
+			wantBusinesses: true,
+			wantParams:     map[string]interface{}{"query": "kopi", "label": "kuliner"},
+			wantFilters:    []string{"LOWER(l.label) = :label"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryParams := map[string]interface{}{}
+			query := searchMatches(tt.params, queryParams)
+
+			if got := strings.Contains(query, "FROM locals l, q"); got != tt.wantBusinesses {
+				t.Errorf("searches local businesses = %v, want %v", got, tt.wantBusinesses)
+			}
+			if got := strings.Contains(query, "FROM tourist_attractions a, q"); got != tt.wantAttractions {
+				t.Errorf("searches tourist attractions = %v, want %v", got, tt.wantAttractions)
+			}
+			if got := strings.Contains(query, "UNION ALL"); got != (tt.wantBusinesses && tt.wantAttractions) {
+				t.Errorf("unions both listings = %v, want %v", got, tt.wantBusinesses && tt.wantAttractions)
+			}
+			for _, filter := range tt.wantFilters {
+				if !strings.Contains(query, filter) {
+					t.Errorf("query doesn't filter on %q", filter)
+				}
+			}
+
+			if len(queryParams) != len(tt.wantParams) {
+				t.Fatalf("params = %v, want %v", queryParams, tt.wantParams)
+			}
+			for name, want := range tt.wantParams {
+				if queryParams[name] != want {
+					t.Fatalf("params = %v, want %v", queryParams, tt.wantParams)
+				}
+			}
+
+			// The user's text only ever reaches the database as a bind parameter
+			if strings.Contains(query, tt.params.Query) {
+				t.Fatalf("query holds the search text: %s", query)
+			}
+			if _, _, err := sqlx.Named(query, queryParams); err != nil {
+				t.Fatalf("query doesn't bind its params: %v", err)
+			}
+		})
+	}
+}
@@ -5,52 +5,70 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
 )
 
-// GetAllTouristAttractions retrieves all tourist attractions with optional city filtering
-func (r *localRepository) GetAllTouristAttractions(ctx context.Context, city string, out *[]local.TouristAttractions) error {
-	query := `
-		SELECT 
-			id, name, description, address, city, province, longitude, latitude, 
-			photo_url, tour_guide_price, tour_guide_count, tour_guide_discount_percentage, 
-			price, discount_percentage, created_at, updated_at
-		FROM tourist_attractions 
+// touristAttractionListingColumns selects a tourist attraction aliased as a along with its average booking rating, kept up to date by a trigger
+const touristAttractionListingColumns = `
+			a.id, a.name, a.description, a.address, a.city, a.province, a.longitude, a.latitude, 
+			a.photo_url, a.tour_guide_price, a.tour_guide_count, a.tour_guide_discount_percentage, 
+			a.price, a.discount_percentage, a.owner_id, a.created_at, a.updated_at,
+			a.rating`
+
+// GetAllTouristAttractions retrieves one page of tourist attractions matching the filters, params must be normalized by the service
+func (r *localRepository) GetAllTouristAttractions(ctx context.Context, params local.QueryParamRequestGetTouristAttractions, out *[]local.TouristAttractions) error {
+	listing := `SELECT ` + touristAttractionListingColumns + `
+		FROM tourist_attractions a
 		WHERE 1=1`
 
-	var rows *sqlx.Rows
-	var err error
+	queryParams := make(map[string]interface{})
 
-	if city != "" {
-		query += " AND LOWER(city) LIKE $1"
-		cityFilter := "%" + strings.ToLower(city) + "%"
-		rows, err = r.queryExecutor.QueryxContext(ctx, query, cityFilter)
-	} else {
-		rows, err = r.queryExecutor.QueryxContext(ctx, query)
+	if params.City != "" {
+		listing += " AND LOWER(a.city) LIKE :city"
+		queryParams["city"] = "%" + strings.ToLower(params.City) + "%"
 	}
 
-	if err != nil {
-		return err
+	if params.Province != "" {
+		listing += " AND LOWER(a.province) = :province"
+		queryParams["province"] = strings.ToLower(params.Province)
 	}
-	defer rows.Close()
 
-	var result []local.TouristAttractions
-	for rows.Next() {
-		var attraction local.TouristAttractions
-		if err := rows.StructScan(&attraction); err != nil {
-			return err
+	if params.MinPrice != nil {
+		listing += " AND a.price >= :min_price"
+		queryParams["min_price"] = *params.MinPrice
+	}
+
+	if params.MaxPrice != nil {
+		listing += " AND a.price <= :max_price"
+		queryParams["max_price"] = *params.MaxPrice
+	}
+
+	if params.HasDiscount != nil {
+		discounted := "(a.discount_percentage > 0 OR a.tour_guide_discount_percentage > 0)"
+		if *params.HasDiscount {
+			listing += " AND " + discounted
+		} else {
+			listing += " AND NOT " + discounted
 		}
-		result = append(result, attraction)
 	}
 
-	if err := rows.Err(); err != nil {
-		return err
+	if params.Category != "" {
+		listing += categoryFilter("tourist_attractions", "a", params.Category, queryParams)
 	}
 
-	*out = result
-	return nil
+	query := pageCatalogue(listing, local.CatalogueSort(params.Sort), params.Order, params.After, params.Limit, queryParams)
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
+}
+
+// GetNearbyTouristAttractions retrieves one page of tourist attractions within the radius, nearest first
+func (r *localRepository) GetNearbyTouristAttractions(ctx context.Context, params local.QueryParamRequestNearby, out *[]local.TouristAttractions) error {
+	queryParams := make(map[string]interface{})
+	listing := nearbyListing(touristAttractionListingColumns, "tourist_attractions", "a", params, queryParams)
+
+	query := pageCatalogue(listing, local.SortDistance, local.SortDistance.DefaultOrder(), params.After, params.Limit, queryParams)
+	return queryCatalogue(ctx, r.queryExecutor, query, queryParams, out)
 }
 
 // GetTouristAttractionByID retrieves a tourist attraction by its ID
@@ -59,7 +77,7 @@ func (r *localRepository) GetTouristAttractionByID(ctx context.Context, data *lo
 		SELECT 
 			id, name, description, address, city, province, longitude, latitude, 
 			photo_url, tour_guide_price, tour_guide_count, tour_guide_discount_percentage, 
-			price, discount_percentage, created_at, updated_at
+			price, discount_percentage, owner_id, created_at, updated_at
 		FROM tourist_attractions
 		WHERE id = $1`
 
@@ -74,6 +92,45 @@ func (r *localRepository) GetTouristAttractionByID(ctx context.Context, data *lo
 	return nil
 }
 
+// GetTouristAttractionByIDForUpdate retrieves a tourist attraction by its ID and locks the row
+// until the surrounding transaction ends, serializing bookings for the attraction
+func (r *localRepository) GetTouristAttractionByIDForUpdate(ctx context.Context, data *local.TouristAttractions) error {
+	query := `
+		SELECT 
+			id, name, description, address, city, province, longitude, latitude, 
+			photo_url, tour_guide_price, tour_guide_count, tour_guide_discount_percentage, 
+			price, discount_percentage, owner_id, created_at, updated_at
+		FROM tourist_attractions
+		WHERE id = $1
+		FOR UPDATE`
+
+	row := r.queryExecutor.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return local.ErrLBNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CountActiveBookingsOnDate counts the bookings holding a tour guide slot on the given date.
+// Paid bookings, flagged ones included, always hold a slot, unpaid ones only until their hold expires.
+func (r *localRepository) CountActiveBookingsOnDate(ctx context.Context, attractionID string, bookedAt time.Time, count *int) error {
+	query := `
+		SELECT COUNT(*)
+		FROM tourguide_bookings
+		WHERE tourist_attraction_id = $1
+			AND DATE(booked_at) = DATE($2::timestamp)
+			AND (
+				status IN ('paid', 'flagged', 'confirmed', 'completed')
+				OR (status = 'pending_payment' AND expires_at > NOW())
+			)`
+
+	return r.queryExecutor.QueryRowxContext(ctx, query, attractionID, bookedAt).Scan(count)
+}
+
 // GetBookingsByTouristAttractionID retrieves all bookings for a specific tourist attraction
 func (r *localRepository) GetBookingsByTouristAttractionID(ctx context.Context, attractionID string, out *[]local.TourGuideBookings) error {
 	query := `
@@ -81,7 +138,7 @@ func (r *localRepository) GetBookingsByTouristAttractionID(ctx context.Context,
 			tb.id, tb.payment_url, tb.star, tb.content, tb.created_at, tb.updated_at, 
 			tb.status, tb.user_id, tb.tourist_attraction_id
 		FROM tourguide_bookings tb 
-		INNER JOIN users u ON u.id = tb.user_id
+		LEFT JOIN users u ON u.id = tb.user_id
 		WHERE tb.tourist_attraction_id = $1
 		ORDER BY tb.created_at DESC`
 
@@ -118,7 +175,10 @@ func (r *localRepository) GetFullyBookedDates(ctx context.Context, attractionID
 		WHERE EXTRACT(MONTH FROM tb.booked_at) = $2
 			AND EXTRACT(YEAR FROM tb.booked_at) = $3
 			AND tb.tourist_attraction_id = $1
-			AND tb.status = 'confirmed'
+			AND (
+				tb.status IN ('paid', 'flagged', 'confirmed', 'completed')
+				OR (tb.status = 'pending_payment' AND tb.expires_at > NOW())
+			)
 		GROUP BY DATE(tb.booked_at), ta.tour_guide_count
 		HAVING COUNT(*) >= ta.tour_guide_count
 		ORDER BY DATE(tb.booked_at)`
@@ -150,10 +210,10 @@ func (r *localRepository) GetFullyBookedDates(ctx context.Context, attractionID
 func (r *localRepository) CreateTourGuideBooking(ctx context.Context, booking *local.TourGuideBookings) error {
 	query := `
 		INSERT INTO tourguide_bookings (
-			id, payment_url, star, content, booked_at, status, user_id, tourist_attraction_id,
+			id, payment_url, booked_at, expires_at, status, amount, user_id, tourist_attraction_id,
 			created_at, updated_at
 		) VALUES (
-			:id, :payment_url, :star, :content, :booked_at, :status, :user_id, :tourist_attraction_id,
+			:id, :payment_url, :booked_at, :expires_at, :status, :amount, :user_id, :tourist_attraction_id,
 			NOW(), NOW()
 		)`
 
@@ -171,11 +231,11 @@ func (r *localRepository) CreateTouristAttraction(ctx context.Context, attractio
 		INSERT INTO tourist_attractions (
 			id, name, description, address, city, province, longitude, latitude,
 			photo_url, tour_guide_price, tour_guide_count, tour_guide_discount_percentage,
-			price, discount_percentage, created_at, updated_at
+			price, discount_percentage, owner_id, created_at, updated_at
 		) VALUES (
 			:id, :name, :description, :address, :city, :province, :longitude, :latitude,
 			:photo_url, :tour_guide_price, :tour_guide_count, :tour_guide_discount_percentage,
-			:price, :discount_percentage, :created_at, :updated_at
+			:price, :discount_percentage, :owner_id, :created_at, :updated_at
 		)`
 
 	_, err := r.queryExecutor.NamedExecContext(ctx, query, attraction)
@@ -239,3 +299,75 @@ func (r *localRepository) DeleteTouristAttraction(ctx context.Context, attractio
 
 	return nil
 }
+
+// GetTourGuideBookingByIDForUpdate retrieves a tour guide booking by its ID and locks the row
+// until the surrounding transaction ends
+func (r *localRepository) GetTourGuideBookingByIDForUpdate(ctx context.Context, booking *local.TourGuideBookings) error {
+	query := `
+		SELECT 
+			id, payment_url, booked_at, expires_at, status, amount, user_id, tourist_attraction_id,
+			created_at, updated_at
+		FROM tourguide_bookings
+		WHERE id = $1
+		FOR UPDATE`
+
+	row := r.queryExecutor.QueryRowxContext(ctx, query, booking.ID)
+	if err := row.StructScan(booking); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return local.ErrBookingNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateTourGuideBookingPaymentURL sets the payment URL of an existing tour guide booking
+func (r *localRepository) UpdateTourGuideBookingPaymentURL(ctx context.Context, booking *local.TourGuideBookings) error {
+	query := `
+		UPDATE tourguide_bookings SET
+			payment_url = :payment_url,
+			updated_at = NOW()
+		WHERE id = :id`
+
+	result, err := r.queryExecutor.NamedExecContext(ctx, query, booking)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return local.ErrBookingNotFound
+	}
+
+	return nil
+}
+
+// UpdateTourGuideBookingStatus updates the status of an existing tour guide booking
+func (r *localRepository) UpdateTourGuideBookingStatus(ctx context.Context, booking *local.TourGuideBookings) error {
+	query := `
+		UPDATE tourguide_bookings SET
+			status = :status,
+			updated_at = NOW()
+		WHERE id = :id`
+
+	result, err := r.queryExecutor.NamedExecContext(ctx, query, booking)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return local.ErrBookingNotFound
+	}
+
+	return nil
+}
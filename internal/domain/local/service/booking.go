@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/local/repository"
+)
+
+// bookingHoldDuration is how long an unpaid booking holds its tour guide slot, matching the Snap expiry
+const bookingHoldDuration = 24 * time.Hour
+
+// userActor formats a user ID as a booking status history actor
+func userActor(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// recordBookingStatus writes a booking status history entry for a transition from one status to another
+func recordBookingStatus(ctx context.Context, repository repository.LocalRepositoryInterface, bookingID uuid.UUID, from, to local.BookingStatus, actor, reason string) error {
+	historyID, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate history ID: %w", err)
+	}
+
+	return repository.CreateBookingStatusHistory(ctx, &local.BookingStatusHistory{
+		ID:         historyID,
+		BookingID:  bookingID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Reason:     reason,
+	})
+}
+
+// transitionBooking moves a booking to the next status if the transition graph allows it and
+// records the transition. The booking should be locked by the caller's transaction.
+func transitionBooking(ctx context.Context, repository repository.LocalRepositoryInterface, booking *local.TourGuideBookings, next local.BookingStatus, actor, reason string) error {
+	if !booking.Status.CanTransitionTo(next) {
+		return local.ErrInvalidBookingTransition
+	}
+
+	previous := booking.Status
+	booking.Status = next
+	if err := repository.UpdateTourGuideBookingStatus(ctx, booking); err != nil {
+		return fmt.Errorf("failed to update booking status: %w", err)
+	}
+
+	if err := recordBookingStatus(ctx, repository, booking.ID, previous, next, actor, reason); err != nil {
+		return fmt.Errorf("failed to record booking status history: %w", err)
+	}
+
+	return nil
+}
+
+// recordBookingStatusChange audits a status change in the transaction that made it, a request can move a booking through several statuses
+func (s *localService) recordBookingStatusChange(ctx context.Context, repository repository.LocalRepositoryInterface, actor audit.Actor, bookingID uuid.UUID, from, to local.BookingStatus, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	return s.audit.Record(ctx, repository.AuditTx(), audit.Entry{
+		Actor:      actor,
+		Action:     audit.ActionBookingStatusChanged,
+		TargetType: audit.TargetBooking,
+		TargetID:   bookingID.String(),
+		Changes: audit.Changes{
+			"status": {From: from, To: to},
+			"reason": {To: reason},
+		},
+	})
+}
+
+// GetBookingStatusHistory retrieves the status history of a booking owned by the given user
+func (s *localService) GetBookingStatusHistory(ctx context.Context, bookingID, userID uuid.UUID) ([]local.ResponseBookingStatusHistory, error) {
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return []local.ResponseBookingStatusHistory{}, err
+	}
+
+	booking := &local.TourGuideBookings{ID: bookingID}
+	if err := repository.GetTourGuideBookingByID(ctx, booking); err != nil {
+		return []local.ResponseBookingStatusHistory{}, err
+	}
+
+	// Do not reveal other users' bookings
+	if booking.UserID != userID {
+		return []local.ResponseBookingStatusHistory{}, local.ErrBookingNotFound
+	}
+
+	var history []local.BookingStatusHistory
+	if err := repository.GetBookingStatusHistory(ctx, bookingID.String(), &history); err != nil {
+		return []local.ResponseBookingStatusHistory{}, err
+	}
+
+	response := make([]local.ResponseBookingStatusHistory, len(history))
+	for i, entry := range history {
+		response[i] = local.ResponseBookingStatusHistory{
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			Actor:      entry.Actor,
+			Reason:     entry.Reason,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	return response, nil
+}
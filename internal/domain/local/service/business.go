@@ -5,43 +5,110 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
 )
 
-// GetAllLocalBusinessesWithFilters retrieves all local businesses with optional filtering
-func (s *localService) GetAllLocalBusinessesWithFilters(ctx context.Context, request local.QueryParamRequestGetLocals) ([]local.ResponseGetLocalBusinesses, error) {
+// GetAllLocalBusinessesWithFilters retrieves one page of local businesses matching the filters
+func (s *localService) GetAllLocalBusinessesWithFilters(ctx context.Context, request local.QueryParamRequestGetLocals) (pagination.Page[local.ResponseGetLocalBusinesses], error) {
+	page, err := resolveCataloguePage(request.Sort, request.Order, request.Cursor, request.Limit, local.LocalBusinessSorts)
+	if err != nil {
+		return pagination.Page[local.ResponseGetLocalBusinesses]{}, err
+	}
+	request.Sort, request.Order, request.After, request.Limit = string(page.sort), page.order, page.after, page.limit
+
+	if request.OpenAt != "" {
+		openAt, err := time.Parse(time.RFC3339, request.OpenAt)
+		if err != nil {
+			return pagination.Page[local.ResponseGetLocalBusinesses]{}, local.ErrInvalidOpenAt
+		}
+		request.OpenAtTime = &openAt
+	}
+
 	localRepository, err := s.repository.NewClient(false)
 	if err != nil {
-		return []local.ResponseGetLocalBusinesses{}, err
+		return pagination.Page[local.ResponseGetLocalBusinesses]{}, err
 	}
 
 	var localBusinesses []local.Locals
 	err = localRepository.GetAllLocalBusinesses(ctx, request, &localBusinesses)
 	if err != nil {
-		return []local.ResponseGetLocalBusinesses{}, err
+		return pagination.Page[local.ResponseGetLocalBusinesses]{}, err
 	}
 
 	// Transform domain entities to response DTOs
 	response := make([]local.ResponseGetLocalBusinesses, len(localBusinesses))
+	statuses := make([]*local.ResponseGetLocalBusinesses, len(localBusinesses))
 	for i, business := range localBusinesses {
-		response[i] = local.ResponseGetLocalBusinesses{
-			ID:          business.ID,
-			Name:        business.Name,
-			Description: business.Description,
-			Address:     business.Address,
-			City:        business.City,
-			Province:    business.Province,
-			Longitude:   business.Longitude,
-			Latitude:    business.Latitude,
-			Label:       business.Label,
-			OpenedTime:  business.OpenedTime,
-			PhotoUrl:    business.PhotoUrl,
-			IsBusiness:  business.IsBusiness,
-			CreatedAt:   business.CreatedAt,
+		response[i] = newLocalBusinessListResponse(business)
+		statuses[i] = &response[i]
+	}
+
+	if err := applyOpeningStatus(ctx, localRepository, localBusinesses, statuses); err != nil {
+		return pagination.Page[local.ResponseGetLocalBusinesses]{}, err
+	}
+
+	return pagination.NewPage(response, page.limit, func(business local.ResponseGetLocalBusinesses) pagination.Cursor {
+		return page.cursor(sortableListing{id: business.ID, name: business.Name, createdAt: business.CreatedAt, rating: business.Rating})
+	}), nil
+}
+
+// GetNearbyLocalBusinesses retrieves one page of local businesses within the radius of a point, nearest first
+func (s *localService) GetNearbyLocalBusinesses(ctx context.Context, request local.QueryParamRequestNearby) (pagination.Page[local.ResponseNearbyLocalBusiness], error) {
+	page, err := resolveNearbyPage(&request)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyLocalBusiness]{}, err
+	}
+
+	localRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyLocalBusiness]{}, err
+	}
+
+	var localBusinesses []local.Locals
+	err = localRepository.GetNearbyLocalBusinesses(ctx, request, &localBusinesses)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyLocalBusiness]{}, err
+	}
+
+	response := make([]local.ResponseNearbyLocalBusiness, len(localBusinesses))
+	statuses := make([]*local.ResponseGetLocalBusinesses, len(localBusinesses))
+	for i, business := range localBusinesses {
+		response[i] = local.ResponseNearbyLocalBusiness{
+			ResponseGetLocalBusinesses: newLocalBusinessListResponse(business),
+			Distance:                   business.Distance,
 		}
+		statuses[i] = &response[i].ResponseGetLocalBusinesses
 	}
 
-	return response, nil
+	if err := applyOpeningStatus(ctx, localRepository, localBusinesses, statuses); err != nil {
+		return pagination.Page[local.ResponseNearbyLocalBusiness]{}, err
+	}
+
+	return pagination.NewPage(response, page.limit, func(business local.ResponseNearbyLocalBusiness) pagination.Cursor {
+		return page.cursor(sortableListing{id: business.ID, distance: business.Distance})
+	}), nil
+}
+
+// newLocalBusinessListResponse maps a local business row of a list, lists carry the rating but no reviews
+func newLocalBusinessListResponse(business local.Locals) local.ResponseGetLocalBusinesses {
+	return local.ResponseGetLocalBusinesses{
+		ID:          business.ID,
+		Name:        business.Name,
+		Description: business.Description,
+		Address:     business.Address,
+		City:        business.City,
+		Province:    business.Province,
+		Longitude:   business.Longitude,
+		Latitude:    business.Latitude,
+		Label:       business.Label,
+		OpenedTime:  business.OpenedTime,
+		PhotoUrl:    business.PhotoUrl,
+		IsBusiness:  business.IsBusiness,
+		CreatedAt:   business.CreatedAt,
+		Rating:      business.Rating,
+	}
 }
 
 // GetLocalBusinessByID retrieves a specific local business by its ID with reviews
@@ -79,7 +146,7 @@ func (s *localService) GetLocalBusinessByID(ctx context.Context, businessID uuid
 		}
 	}
 
-	return local.ResponseGetLocalBusinesses{
+	response := local.ResponseGetLocalBusinesses{
 		ID:          business.ID,
 		Name:        business.Name,
 		Description: business.Description,
@@ -94,19 +161,51 @@ func (s *localService) GetLocalBusinessByID(ctx context.Context, businessID uuid
 		IsBusiness:  business.IsBusiness,
 		CreatedAt:   business.CreatedAt,
 		Reviews:     reviewResponses,
-	}, nil
-}
+	}
 
-// CreateLocalBusiness creates a new local business
-func (s *localService) CreateLocalBusiness(ctx context.Context, request local.RequestCreateLocalBusiness) (local.ResponseGetLocalBusinesses, error) {
-	client, err := s.repository.NewClient(false)
+	if err := applyOpeningStatus(ctx, localRepository, []local.Locals{*business}, []*local.ResponseGetLocalBusinesses{&response}); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	openingHours, err := openingHoursResponse(ctx, localRepository, *business)
 	if err != nil {
 		return local.ResponseGetLocalBusinesses{}, err
 	}
+	response.OpeningHours = &openingHours
 
+	var categories []local.ListingCategory
+	if err := localRepository.GetLocalBusinessCategories(ctx, businessID, &categories); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+	response.Categories = newListingCategoryResponses(categories)
+
+	return response, nil
+}
+
+// CreateLocalBusiness creates a new local business
+func (s *localService) CreateLocalBusiness(ctx context.Context, actor local.Actor, request local.RequestCreateLocalBusiness) (response local.ResponseGetLocalBusinesses, err error) {
 	businessID := uuid.New()
 	now := time.Now()
 
+	var openingHours []local.OpeningHours
+	if request.OpeningHours != nil {
+		openingHours, _, err = newOpeningHoursRows(businessID, *request.OpeningHours)
+		if err != nil {
+			return local.ResponseGetLocalBusinesses{}, err
+		}
+	}
+
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
 	business := &local.Locals{
 		ID:          businessID,
 		Name:        request.Name,
@@ -114,22 +213,31 @@ func (s *localService) CreateLocalBusiness(ctx context.Context, request local.Re
 		Address:     request.Address,
 		City:        request.City,
 		Province:    request.Province,
-		Longitude:   request.Longitude,
-		Latitude:    request.Latitude,
+		Longitude:   request.Longitude.Degrees(),
+		Latitude:    request.Latitude.Degrees(),
 		Label:       request.Label,
 		OpenedTime:  request.OpenedTime,
 		PhotoUrl:    request.PhotoUrl,
 		IsBusiness:  request.IsBusiness,
+		Timezone:    local.TimezoneForProvince(request.Province),
+		OwnerID:     &actor.UserID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
+	if request.OpeningHours != nil {
+		business.ClosedOnPublicHolidays = request.OpeningHours.ClosedOnPublicHolidays
+	}
 
 	err = client.CreateLocalBusiness(ctx, business)
 	if err != nil {
 		return local.ResponseGetLocalBusinesses{}, err
 	}
 
-	return local.ResponseGetLocalBusinesses{
+	if err = client.ReplaceOpeningHours(ctx, business.ID, openingHours); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	response = local.ResponseGetLocalBusinesses{
 		ID:          business.ID,
 		Name:        business.Name,
 		Description: business.Description,
@@ -144,23 +252,56 @@ func (s *localService) CreateLocalBusiness(ctx context.Context, request local.Re
 		IsBusiness:  business.IsBusiness,
 		CreatedAt:   business.CreatedAt,
 		Reviews:     []local.ResponseReviews{},
-	}, nil
+	}
+
+	if err = applyOpeningStatus(ctx, client, []local.Locals{*business}, []*local.ResponseGetLocalBusinesses{&response}); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessCreated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    audit.Diff(nil, business),
+	})
+	if err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	return response, nil
 }
 
 // UpdateLocalBusiness updates an existing local business
-func (s *localService) UpdateLocalBusiness(ctx context.Context, businessID uuid.UUID, request local.RequestUpdateLocalBusiness) (local.ResponseGetLocalBusinesses, error) {
-	client, err := s.repository.NewClient(false)
+func (s *localService) UpdateLocalBusiness(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestUpdateLocalBusiness) (response local.ResponseGetLocalBusinesses, err error) {
+	client, err := s.repository.NewClient(true)
 	if err != nil {
 		return local.ResponseGetLocalBusinesses{}, err
 	}
 
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
 	// First, get the existing business
 	business := &local.Locals{ID: businessID}
-	err = client.GetLocalBusinessByID(ctx, business)
+	err = client.GetLocalBusinessByIDForUpdate(ctx, business)
 	if err != nil {
 		return local.ResponseGetLocalBusinesses{}, err
 	}
 
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	before := *business
+
 	// Update only provided fields
 	if request.Name != nil {
 		business.Name = *request.Name
@@ -176,12 +317,13 @@ func (s *localService) UpdateLocalBusiness(ctx context.Context, businessID uuid.
 	}
 	if request.Province != nil {
 		business.Province = *request.Province
+		business.Timezone = local.TimezoneForProvince(business.Province)
 	}
 	if request.Longitude != nil {
-		business.Longitude = *request.Longitude
+		business.Longitude = request.Longitude.Degrees()
 	}
 	if request.Latitude != nil {
-		business.Latitude = *request.Latitude
+		business.Latitude = request.Latitude.Degrees()
 	}
 	if request.Label != nil {
 		business.Label = *request.Label
@@ -203,6 +345,17 @@ func (s *localService) UpdateLocalBusiness(ctx context.Context, businessID uuid.
 		return local.ResponseGetLocalBusinesses{}, err
 	}
 
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    audit.Diff(before, business),
+	})
+	if err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
 	// Get reviews for the response
 	var reviews []local.Review
 	err = client.GetReviewsByLocalBusinessID(ctx, businessID.String(), &reviews)
@@ -221,7 +374,7 @@ func (s *localService) UpdateLocalBusiness(ctx context.Context, businessID uuid.
 		}
 	}
 
-	return local.ResponseGetLocalBusinesses{
+	response = local.ResponseGetLocalBusinesses{
 		ID:          business.ID,
 		Name:        business.Name,
 		Description: business.Description,
@@ -236,15 +389,60 @@ func (s *localService) UpdateLocalBusiness(ctx context.Context, businessID uuid.
 		IsBusiness:  business.IsBusiness,
 		CreatedAt:   business.CreatedAt,
 		Reviews:     reviewResponses,
-	}, nil
+	}
+
+	if err = applyOpeningStatus(ctx, client, []local.Locals{*business}, []*local.ResponseGetLocalBusinesses{&response}); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return local.ResponseGetLocalBusinesses{}, err
+	}
+
+	return response, nil
 }
 
 // DeleteLocalBusiness deletes a local business
-func (s *localService) DeleteLocalBusiness(ctx context.Context, businessID uuid.UUID) error {
-	client, err := s.repository.NewClient(false)
+func (s *localService) DeleteLocalBusiness(ctx context.Context, actor local.Actor, businessID uuid.UUID) (err error) {
+	client, err := s.repository.NewClient(true)
 	if err != nil {
 		return err
 	}
 
-	return client.DeleteLocalBusiness(ctx, businessID.String())
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByID(ctx, business); err != nil {
+		return err
+	}
+
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return err
+	}
+
+	if err = client.DeleteLocalBusiness(ctx, businessID.String()); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessDeleted,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    audit.Diff(business, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = client.Commit(); err != nil {
+		return err
+	}
+
+	s.removeListingPhotos(ctx, local.ListingLocalBusiness, business.ID)
+	return nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
+)
+
+// cataloguePage is a validated sort, order, cursor and page size for a catalogue list
+type cataloguePage struct {
+	sort  local.CatalogueSort
+	order string
+	after *pagination.Cursor
+	limit int
+}
+
+// resolveCataloguePage applies the defaults, created_at newest first, and rejects sorts the list doesn't support
+// as well as cursors issued for a different ordering
+func resolveCataloguePage(sort, order, cursor string, limit int, allowed []local.CatalogueSort) (cataloguePage, error) {
+	page := cataloguePage{
+		sort:  local.SortCreatedAt,
+		limit: pagination.Limit(limit, local.DefaultCatalogueLimit, local.MaxCatalogueLimit),
+	}
+
+	if sort != "" {
+		page.sort = ""
+		for _, candidate := range allowed {
+			if string(candidate) == strings.ToLower(sort) {
+				page.sort = candidate
+			}
+		}
+		if page.sort == "" {
+			return cataloguePage{}, local.ErrInvalidSort
+		}
+	}
+
+	page.order = strings.ToLower(order)
+	switch page.order {
+	case "":
+		page.order = page.sort.DefaultOrder()
+	case pagination.OrderAsc, pagination.OrderDesc:
+	default:
+		return cataloguePage{}, local.ErrInvalidSort
+	}
+
+	if cursor != "" {
+		after, err := pagination.Decode(cursor)
+		if err != nil || after.Sort != string(page.sort) || after.Order != page.order {
+			return cataloguePage{}, local.ErrInvalidCursor
+		}
+		if _, err := uuid.Parse(after.ID); err != nil || !validCursorValue(page.sort, after.Value) {
+			return cataloguePage{}, local.ErrInvalidCursor
+		}
+		page.after = &after
+	}
+
+	return page, nil
+}
+
+// resolveNearbyPage applies the default radius and pages nearby results nearest first
+func resolveNearbyPage(request *local.QueryParamRequestNearby) (cataloguePage, error) {
+	page, err := resolveCataloguePage(string(local.SortDistance), "", request.Cursor, request.Limit, []local.CatalogueSort{local.SortDistance})
+	if err != nil {
+		return cataloguePage{}, err
+	}
+
+	if request.Radius == 0 {
+		request.Radius = local.DefaultNearbyRadius
+	}
+	request.After, request.Limit = page.after, page.limit
+
+	return page, nil
+}
+
+// validCursorValue checks a cursor value parses as the type of its sort column, so a tampered cursor
+// is rejected here rather than failing the query
+func validCursorValue(sort local.CatalogueSort, value string) bool {
+	var err error
+	switch sort {
+	case local.SortName:
+	case local.SortPrice:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case local.SortRating, local.SortDistance, local.SortRelevance:
+		_, err = strconv.ParseFloat(value, 64)
+	default:
+		_, err = time.Parse(time.RFC3339Nano, value)
+	}
+	return err == nil
+}
+
+// sortableListing carries the values a listing can be sorted by
+type sortableListing struct {
+	id        uuid.UUID
+	name      string
+	createdAt time.Time
+	price     int64
+	rating    float64
+	distance  float64
+	rank      float64
+}
+
+// cursor builds the cursor pointing after the listing
+func (p cataloguePage) cursor(listing sortableListing) pagination.Cursor {
+	cursor := pagination.Cursor{Sort: string(p.sort), Order: p.order, ID: listing.id.String()}
+
+	switch p.sort {
+	case local.SortName:
+		cursor.Value = listing.name
+	case local.SortPrice:
+		cursor.Value = strconv.FormatInt(listing.price, 10)
+	case local.SortRating:
+		cursor.Value = strconv.FormatFloat(listing.rating, 'g', -1, 64)
+	case local.SortDistance:
+		cursor.Value = strconv.FormatFloat(listing.distance, 'g', -1, 64)
+	case local.SortRelevance:
+		cursor.Value = strconv.FormatFloat(listing.rank, 'g', -1, 64)
+	default:
+		cursor.Value = listing.createdAt.Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
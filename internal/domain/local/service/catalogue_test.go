@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
+)
+
+func TestResolveCataloguePage(t *testing.T) {
+	id := uuid.MustParse("0195a1b2-0000-7000-8000-000000000001").String()
+	cursor := func(sort local.CatalogueSort, order, value, id string) string {
+		return pagination.Cursor{Sort: string(sort), Order: order, Value: value, ID: id}.Encode()
+	}
+
+	tests := []struct {
+		name      string
+		sort      string
+		order     string
+		cursor    string
+		limit     int
+		wantSort  local.CatalogueSort
+		wantOrder string
+		wantLimit int
+		wantErr   error
+	}{
+		{name: "defaults", wantSort: local.SortCreatedAt, wantOrder: pagination.OrderDesc, wantLimit: local.DefaultCatalogueLimit},
+		{name: "name sorts ascending", sort: "name", wantSort: local.SortName, wantOrder: pagination.OrderAsc, wantLimit: local.DefaultCatalogueLimit},
+		{name: "sort and order are case-insensitive", sort: "RATING", order: "ASC", limit: 500, wantSort: local.SortRating, wantOrder: pagination.OrderAsc, wantLimit: local.MaxCatalogueLimit},
+		{name: "sort the list doesn't support", sort: "price", wantErr: local.ErrInvalidSort},
+		{name: "unknown order", order: "up", wantErr: local.ErrInvalidSort},
+		{
+			name:      "cursor of the same ordering",
+			sort:      "rating",
+			cursor:    cursor(local.SortRating, pagination.OrderDesc, "4.5", id),
+			wantSort:  local.SortRating,
+			wantOrder: pagination.OrderDesc,
+			wantLimit: local.DefaultCatalogueLimit,
+		},
+		{name: "cursor of another sort", sort: "name", cursor: cursor(local.SortRating, pagination.OrderAsc, "4.5", id), wantErr: local.ErrInvalidCursor},
+		{name: "cursor of another order", sort: "name", order: "desc", cursor: cursor(local.SortName, pagination.OrderAsc, "a", id), wantErr: local.ErrInvalidCursor},
+		{name: "cursor with a bad ID", sort: "name", cursor: cursor(local.SortName, pagination.OrderAsc, "a", "42"), wantErr: local.ErrInvalidCursor},
+		{name: "cursor value of the wrong type", sort: "rating", cursor: cursor(local.SortRating, pagination.OrderDesc, "'; DROP TABLE locals", id), wantErr: local.ErrInvalidCursor},
+		{name: "cursor timestamp of the wrong format", cursor: cursor(local.SortCreatedAt, pagination.OrderDesc, "2025-03-01", id), wantErr: local.ErrInvalidCursor},
+		{name: "malformed cursor", cursor: "garbage", wantErr: local.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := resolveCataloguePage(tt.sort, tt.order, tt.cursor, tt.limit, local.LocalBusinessSorts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if page.sort != tt.wantSort || page.order != tt.wantOrder || page.limit != tt.wantLimit {
+				t.Errorf("page = %s %s limit %d, want %s %s limit %d", page.sort, page.order, page.limit, tt.wantSort, tt.wantOrder, tt.wantLimit)
+			}
+			if (tt.cursor != "") != (page.after != nil) {
+				t.Errorf("after = %+v, want a cursor only when one was sent", page.after)
+			}
+		})
+	}
+}
+
+func TestCataloguePageCursorRoundTrip(t *testing.T) {
+	listing := sortableListing{
+		id:        uuid.MustParse("0195a1b2-0000-7000-8000-000000000001"),
+		name:      "Warung Bu Sri",
+		createdAt: time.Date(2025, time.March, 1, 8, 30, 0, 123456000, time.UTC),
+		price:     25000,
+		rating:    4.333333333333333,
+		distance:  1234,
+		rank:      0.0759909,
+	}
+
+	tests := []struct {
+		sort      local.CatalogueSort
+		wantValue string
+	}{
+		{sort: local.SortName, wantValue: "Warung Bu Sri"},
+		{sort: local.SortCreatedAt, wantValue: "2025-03-01T08:30:00.123456Z"},
+		{sort: local.SortPrice, wantValue: "25000"},
+		{sort: local.SortRating, wantValue: "4.333333333333333"},
+		{sort: local.SortDistance, wantValue: "1234"},
+		{sort: local.SortRelevance, wantValue: "0.0759909"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			issued := cataloguePage{sort: tt.sort, order: tt.sort.DefaultOrder()}.cursor(listing)
+			if issued.Value != tt.wantValue {
+				t.Errorf("cursor value = %q, want %q", issued.Value, tt.wantValue)
+			}
+
+			// A cursor the service issued must be accepted back for the same ordering
+			allowed := []local.CatalogueSort{tt.sort}
+			page, err := resolveCataloguePage(string(tt.sort), "", issued.Encode(), 0, allowed)
+			if err != nil {
+				t.Fatalf("issued cursor rejected: %v", err)
+			}
+			if *page.after != issued {
+				t.Errorf("after = %+v, want %+v", *page.after, issued)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+func newListingCategoryResponses(categories []local.ListingCategory) []local.ResponseListingCategory {
+	response := make([]local.ResponseListingCategory, len(categories))
+	for i, category := range categories {
+		response[i] = local.ResponseListingCategory{
+			ID:       category.ID,
+			ParentID: category.ParentID,
+			Slug:     category.Slug,
+			Names:    category.Names,
+			Icon:     category.Icon,
+		}
+	}
+	return response
+}
+
+// categorySlugs lists the slugs of categories for the audit log
+func categorySlugs(categories []local.ListingCategory) []string {
+	slugs := make([]string, len(categories))
+	for i, category := range categories {
+		slugs[i] = category.Slug
+	}
+	return slugs
+}
+
+// SetLocalBusinessCategories files a local business under exactly the given categories
+func (s *localService) SetLocalBusinessCategories(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestSetCategories) (response []local.ResponseListingCategory, err error) {
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByID(ctx, business); err != nil {
+		return nil, err
+	}
+
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return nil, err
+	}
+
+	var before, after []local.ListingCategory
+	if err = client.GetLocalBusinessCategories(ctx, businessID, &before); err != nil {
+		return nil, err
+	}
+	if err = client.ReplaceLocalBusinessCategories(ctx, businessID, request.CategoryIDs); err != nil {
+		return nil, err
+	}
+	if err = client.GetLocalBusinessCategories(ctx, businessID, &after); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   businessID.String(),
+		Changes:    audit.Changes{"categories": audit.Change{From: categorySlugs(before), To: categorySlugs(after)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newListingCategoryResponses(after), nil
+}
+
+// SetTouristAttractionCategories files a tourist attraction under exactly the given categories
+func (s *localService) SetTouristAttractionCategories(ctx context.Context, actor local.Actor, attractionID uuid.UUID, request local.RequestSetCategories) (response []local.ResponseListingCategory, err error) {
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	attraction := &local.TouristAttractions{ID: attractionID}
+	if err = client.GetTouristAttractionByID(ctx, attraction); err != nil {
+		return nil, err
+	}
+
+	if err = authorizeListingWrite(actor, attraction.OwnerID); err != nil {
+		return nil, err
+	}
+
+	var before, after []local.ListingCategory
+	if err = client.GetTouristAttractionCategories(ctx, attractionID, &before); err != nil {
+		return nil, err
+	}
+	if err = client.ReplaceTouristAttractionCategories(ctx, attractionID, request.CategoryIDs); err != nil {
+		return nil, err
+	}
+	if err = client.GetTouristAttractionCategories(ctx, attractionID, &after); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionTouristAttractionUpdated,
+		TargetType: audit.TargetTouristAttraction,
+		TargetID:   attractionID.String(),
+		Changes:    audit.Changes{"categories": audit.Change{From: categorySlugs(before), To: categorySlugs(after)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newListingCategoryResponses(after), nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// expiryBatchSize limits how many bookings a single expiry run processes
+const expiryBatchSize = 100
+
+// ExpireStaleBookings expires unpaid bookings whose hold has passed, cancelling their Midtrans
+// transaction so a late payment cannot be made for a slot that was already released
+func (s *localService) ExpireStaleBookings(ctx context.Context) error {
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	var bookingIDs []uuid.UUID
+	if err := repository.GetExpiredPendingBookingIDs(ctx, expiryBatchSize, &bookingIDs); err != nil {
+		return fmt.Errorf("failed to get expired bookings: %w", err)
+	}
+
+	for _, bookingID := range bookingIDs {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// A failed booking stays pending and is retried on the next run
+		if err := s.expireBooking(ctx, bookingID); err != nil {
+			log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("failed to expire booking")
+		}
+	}
+
+	return nil
+}
+
+// bookingIsStale reports whether a booking is still waiting for a payment whose window has passed
+func bookingIsStale(booking *local.TourGuideBookings) bool {
+	return booking.Status == local.BookingStatusPendingPayment && booking.ExpiresAt != nil && !booking.ExpiresAt.After(time.Now())
+}
+
+// expireBooking cancels the Midtrans transaction of a single stale booking and marks it expired. The Midtrans
+// calls happen before the booking row is locked so a slow gateway never holds the lock.
+func (s *localService) expireBooking(ctx context.Context, bookingID uuid.UUID) (err error) {
+	reader, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	booking := &local.TourGuideBookings{ID: bookingID}
+	if err = reader.GetTourGuideBookingByID(ctx, booking); err != nil {
+		return err
+	}
+
+	// A payment notification may have settled the booking since it was selected
+	if !bookingIsStale(booking) {
+		return nil
+	}
+
+	if _, midtransErr := s.coreAPI.CancelTransaction(bookingID.String()); midtransErr != nil {
+		switch midtransErr.GetStatusCode() {
+		case http.StatusNotFound:
+			// The customer never opened the Snap page, so there is no transaction to cancel
+		case http.StatusPreconditionFailed:
+			// The transaction can no longer be cancelled, only expire it if it was not paid
+			transaction, checkErr := s.coreAPI.CheckTransaction(bookingID.String())
+			if checkErr != nil {
+				return fmt.Errorf("failed to check transaction: %w", checkErr)
+			}
+			if status := bookingStatusFromMidtrans(transaction.TransactionStatus, transaction.FraudStatus); status != local.BookingStatusExpired && status != local.BookingStatusCancelled {
+				// Leave it to the payment notification
+				return nil
+			}
+		default:
+			return fmt.Errorf("failed to cancel transaction: %w", midtransErr)
+		}
+	}
+
+	repository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	if err = repository.GetTourGuideBookingByIDForUpdate(ctx, booking); err != nil {
+		return err
+	}
+
+	// Once the transaction is cancelled it can't be paid any more, a notification that settled the
+	// booking in between the check and the lock has already moved it on and wins
+	if !bookingIsStale(booking) {
+		return repository.Commit()
+	}
+
+	previous := booking.Status
+	err = transitionBooking(ctx, repository, booking, local.BookingStatusExpired, local.BookingActorSystem, "payment window expired")
+	if err != nil {
+		return err
+	}
+
+	err = s.recordBookingStatusChange(ctx, repository, audit.SystemActor(local.BookingActorSystem), booking.ID, previous, booking.Status, "payment window expired")
+	if err != nil {
+		return err
+	}
+
+	return repository.Commit()
+}
@@ -0,0 +1,628 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/local/repository"
+)
+
+// upcomingExceptionDays is how far ahead the exceptions and public holidays of a schedule are listed
+const upcomingExceptionDays = 90
+
+// statusWindow covers the local dates Schedule.Status looks at in every Indonesian time zone
+func statusWindow(now time.Time) (from, to time.Time) {
+	today := dateOnly(now.UTC())
+	return today.AddDate(0, 0, -2), today.AddDate(0, 0, local.StatusHorizonDays+2)
+}
+
+// dateOnly drops the time of day, dates are compared as UTC midnights
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// parseDate reads a YYYY-MM-DD path parameter
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(local.DateLayout, value)
+	if err != nil {
+		return time.Time{}, local.ErrInvalidDate
+	}
+	return date, nil
+}
+
+// parseWeekday reads a lower case English day name
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// loadSchedules builds the schedule of every business from its weekly hours and the exceptions
+// and public holidays dated from from to to
+func loadSchedules(ctx context.Context, client repository.LocalRepositoryInterface, businesses []local.Locals, from, to time.Time) (map[uuid.UUID]local.Schedule, error) {
+	schedules := make(map[uuid.UUID]local.Schedule, len(businesses))
+	if len(businesses) == 0 {
+		return schedules, nil
+	}
+
+	ids := make([]uuid.UUID, len(businesses))
+	for i, business := range businesses {
+		ids[i] = business.ID
+	}
+
+	var hours []local.OpeningHours
+	if err := client.GetOpeningHours(ctx, ids, &hours); err != nil {
+		return nil, err
+	}
+
+	var exceptions []local.OpeningException
+	if err := client.GetOpeningExceptions(ctx, ids, from, to, &exceptions); err != nil {
+		return nil, err
+	}
+
+	var holidays []local.PublicHoliday
+	if err := client.GetPublicHolidays(ctx, from, to, &holidays); err != nil {
+		return nil, err
+	}
+
+	holidayDates := make(map[string]bool, len(holidays))
+	for _, holiday := range holidays {
+		holidayDates[holiday.Date.Format(local.DateLayout)] = true
+	}
+
+	for _, business := range businesses {
+		location, err := time.LoadLocation(business.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		schedules[business.ID] = local.Schedule{
+			Location:         location,
+			Exceptions:       map[string][]local.Interval{},
+			Holidays:         holidayDates,
+			ClosedOnHolidays: business.ClosedOnPublicHolidays,
+		}
+	}
+
+	for _, row := range hours {
+		interval, err := local.NewInterval(row.OpensAt, row.ClosesAt)
+		if err != nil {
+			return nil, err
+		}
+		schedule := schedules[row.LocalID]
+		schedule.Weekly = append(schedule.Weekly, local.WeeklyInterval{Weekday: time.Weekday(row.Weekday), Interval: interval})
+		schedules[row.LocalID] = schedule
+	}
+
+	for _, row := range exceptions {
+		date := row.Date.Format(local.DateLayout)
+		schedule := schedules[row.LocalID]
+		intervals := schedule.Exceptions[date]
+		if row.OpensAt != nil && row.ClosesAt != nil {
+			interval, err := local.NewInterval(*row.OpensAt, *row.ClosesAt)
+			if err != nil {
+				return nil, err
+			}
+			intervals = append(intervals, interval)
+		}
+		schedule.Exceptions[date] = intervals
+	}
+
+	return schedules, nil
+}
+
+// withOpeningStatus sets the time zone and, when the business has hours, whether it is open now and until when
+func withOpeningStatus(response *local.ResponseGetLocalBusinesses, timezone string, schedule local.Schedule, now time.Time) {
+	response.Timezone = timezone
+	if !schedule.Known() {
+		return
+	}
+
+	open, nextChange := schedule.Status(now)
+	response.OpenNow = &open
+	response.NextChangeAt = nextChange
+}
+
+// applyOpeningStatus loads the schedules of a page of businesses and fills in their opening status
+func applyOpeningStatus(ctx context.Context, client repository.LocalRepositoryInterface, businesses []local.Locals, responses []*local.ResponseGetLocalBusinesses) error {
+	now := time.Now()
+	from, to := statusWindow(now)
+
+	schedules, err := loadSchedules(ctx, client, businesses, from, to)
+	if err != nil {
+		return err
+	}
+
+	for i, business := range businesses {
+		withOpeningStatus(responses[i], business.Timezone, schedules[business.ID], now)
+	}
+	return nil
+}
+
+// newOpeningHoursRows validates a weekly schedule request and turns it into rows of the business
+func newOpeningHoursRows(businessID uuid.UUID, request local.RequestOpeningHours) ([]local.OpeningHours, []local.WeeklyInterval, error) {
+	rows := make([]local.OpeningHours, 0, len(request.Weekly))
+	weekly := make([]local.WeeklyInterval, 0, len(request.Weekly))
+	for _, entry := range request.Weekly {
+		day, ok := parseWeekday(entry.Day)
+		if !ok {
+			return nil, nil, local.ErrInvalidOpeningHours
+		}
+		interval, err := local.NewInterval(entry.Opens, entry.Closes)
+		if err != nil {
+			return nil, nil, local.ErrInvalidOpeningHours
+		}
+		weekly = append(weekly, local.WeeklyInterval{Weekday: day, Interval: interval})
+	}
+
+	local.SortWeekly(weekly)
+	for _, interval := range weekly {
+		rows = append(rows, openingHoursRow(businessID, interval))
+	}
+	return rows, weekly, nil
+}
+
+func openingHoursRow(businessID uuid.UUID, interval local.WeeklyInterval) local.OpeningHours {
+	return local.OpeningHours{
+		LocalID:  businessID,
+		Weekday:  int(interval.Weekday),
+		OpensAt:  interval.Opens.String(),
+		ClosesAt: interval.Closes.String(),
+	}
+}
+
+func newWeeklyResponse(weekly []local.WeeklyInterval) []local.ResponseWeeklyInterval {
+	response := make([]local.ResponseWeeklyInterval, len(weekly))
+	for i, interval := range weekly {
+		response[i] = local.ResponseWeeklyInterval{
+			Day:                     strings.ToLower(interval.Weekday.String()),
+			ResponseOpeningInterval: newIntervalResponse(interval.Interval),
+		}
+	}
+	return response
+}
+
+func newIntervalResponse(interval local.Interval) local.ResponseOpeningInterval {
+	return local.ResponseOpeningInterval{Opens: interval.Opens.String(), Closes: interval.Closes.String()}
+}
+
+// GetOpeningHours retrieves the weekly schedule of a local business with its upcoming exceptions and public holidays
+func (s *localService) GetOpeningHours(ctx context.Context, businessID uuid.UUID) (local.ResponseOpeningHours, error) {
+	client, err := s.repository.NewClient(false)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	business := &local.Locals{ID: businessID}
+	if err := client.GetLocalBusinessByID(ctx, business); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	return openingHoursResponse(ctx, client, *business)
+}
+
+// openingHoursResponse lists the schedule of a business from its local today onwards
+func openingHoursResponse(ctx context.Context, client repository.LocalRepositoryInterface, business local.Locals) (local.ResponseOpeningHours, error) {
+	location, err := time.LoadLocation(business.Timezone)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+	from := dateOnly(time.Now().In(location))
+	to := from.AddDate(0, 0, upcomingExceptionDays)
+
+	schedules, err := loadSchedules(ctx, client, []local.Locals{business}, from, to)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+	schedule := schedules[business.ID]
+
+	response := local.ResponseOpeningHours{
+		Timezone:               business.Timezone,
+		Weekly:                 newWeeklyResponse(schedule.Weekly),
+		ClosedOnPublicHolidays: business.ClosedOnPublicHolidays,
+		Exceptions:             []local.ResponseOpeningException{},
+	}
+
+	var exceptions []local.OpeningException
+	if err := client.GetOpeningExceptions(ctx, []uuid.UUID{business.ID}, from, to, &exceptions); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+	for _, row := range exceptions {
+		date := row.Date.Format(local.DateLayout)
+		last := len(response.Exceptions) - 1
+		if last < 0 || response.Exceptions[last].Date != date {
+			intervals := schedule.Exceptions[date]
+			exception := local.ResponseOpeningException{
+				Date:      date,
+				Closed:    len(intervals) == 0,
+				Intervals: make([]local.ResponseOpeningInterval, len(intervals)),
+				Note:      row.Note,
+			}
+			for i, interval := range intervals {
+				exception.Intervals[i] = newIntervalResponse(interval)
+			}
+			response.Exceptions = append(response.Exceptions, exception)
+		}
+	}
+
+	if business.ClosedOnPublicHolidays {
+		var holidays []local.PublicHoliday
+		if err := client.GetPublicHolidays(ctx, from, to, &holidays); err != nil {
+			return local.ResponseOpeningHours{}, err
+		}
+		response.PublicHolidays = newPublicHolidayResponses(holidays)
+	}
+
+	return response, nil
+}
+
+// UpdateOpeningHours replaces the weekly schedule of a local business
+func (s *localService) UpdateOpeningHours(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestOpeningHours) (response local.ResponseOpeningHours, err error) {
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByIDForUpdate(ctx, business); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	rows, _, err := newOpeningHoursRows(businessID, request)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	var previous []local.OpeningHours
+	if err = client.GetOpeningHours(ctx, []uuid.UUID{businessID}, &previous); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	before := *business
+	business.ClosedOnPublicHolidays = request.ClosedOnPublicHolidays
+	business.UpdatedAt = time.Now()
+
+	if err = client.UpdateLocalBusiness(ctx, business); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+	if err = client.ReplaceOpeningHours(ctx, businessID, rows); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	response, err = openingHoursResponse(ctx, client, *business)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	changes := audit.Diff(before, business)
+	changes["opening_hours"] = audit.Change{From: openingHoursSummary(previous), To: openingHoursSummary(rows)}
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    changes,
+	})
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	return response, nil
+}
+
+// openingHoursSummary writes weekly rows as "monday 08:00-17:00" for the audit log
+func openingHoursSummary(rows []local.OpeningHours) []string {
+	summary := make([]string, 0, len(rows))
+	for _, row := range rows {
+		interval, err := local.NewInterval(row.OpensAt, row.ClosesAt)
+		if err != nil {
+			continue
+		}
+		summary = append(summary, strings.ToLower(time.Weekday(row.Weekday).String())+" "+interval.Opens.String()+"-"+interval.Closes.String())
+	}
+	return summary
+}
+
+// SetOpeningException replaces the hours of a local business on one date, no intervals closes it for the day
+func (s *localService) SetOpeningException(ctx context.Context, actor local.Actor, businessID uuid.UUID, date string, request local.RequestOpeningException) (response local.ResponseOpeningHours, err error) {
+	day, err := parseDate(date)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	rows := []local.OpeningException{}
+	for _, entry := range request.Intervals {
+		interval, parseErr := local.NewInterval(entry.Opens, entry.Closes)
+		if parseErr != nil {
+			return local.ResponseOpeningHours{}, local.ErrInvalidOpeningHours
+		}
+		opens, closes := interval.Opens.String(), interval.Closes.String()
+		rows = append(rows, local.OpeningException{LocalID: businessID, Date: day, OpensAt: &opens, ClosesAt: &closes, Note: request.Note})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, local.OpeningException{LocalID: businessID, Date: day, Note: request.Note})
+	}
+
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByID(ctx, business); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	if err = client.ReplaceOpeningException(ctx, businessID, day, rows); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	response, err = openingHoursResponse(ctx, client, *business)
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    audit.Changes{"opening_exception": audit.Change{To: request}},
+	})
+	if err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return local.ResponseOpeningHours{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteOpeningException restores the weekly hours of a local business on a date
+func (s *localService) DeleteOpeningException(ctx context.Context, actor local.Actor, businessID uuid.UUID, date string) (err error) {
+	day, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByID(ctx, business); err != nil {
+		return err
+	}
+
+	if err = authorizeListingWrite(actor, business.OwnerID); err != nil {
+		return err
+	}
+
+	if err = client.DeleteOpeningException(ctx, businessID, day); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   business.ID.String(),
+		Changes:    audit.Changes{"opening_exception": audit.Change{From: date}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return client.Commit()
+}
+
+func newPublicHolidayResponses(holidays []local.PublicHoliday) []local.ResponsePublicHoliday {
+	response := make([]local.ResponsePublicHoliday, len(holidays))
+	for i, holiday := range holidays {
+		response[i] = local.ResponsePublicHoliday{Date: holiday.Date.Format(local.DateLayout), Name: holiday.Name}
+	}
+	return response
+}
+
+// GetPublicHolidays lists the public holidays of a year, the current one by default
+func (s *localService) GetPublicHolidays(ctx context.Context, request local.QueryParamRequestPublicHolidays) ([]local.ResponsePublicHoliday, error) {
+	year := request.Year
+	if year == 0 {
+		year = time.Now().Year()
+	}
+
+	client, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var holidays []local.PublicHoliday
+	if err := client.GetPublicHolidays(ctx, from, from.AddDate(1, 0, -1), &holidays); err != nil {
+		return nil, err
+	}
+
+	return newPublicHolidayResponses(holidays), nil
+}
+
+// SetPublicHoliday adds a public holiday or renames the one on its date
+func (s *localService) SetPublicHoliday(ctx context.Context, date string, request local.RequestPublicHoliday) (local.ResponsePublicHoliday, error) {
+	day, err := parseDate(date)
+	if err != nil {
+		return local.ResponsePublicHoliday{}, err
+	}
+
+	client, err := s.repository.NewClient(false)
+	if err != nil {
+		return local.ResponsePublicHoliday{}, err
+	}
+
+	holiday := &local.PublicHoliday{Date: day, Name: request.Name}
+	if err := client.UpsertPublicHoliday(ctx, holiday); err != nil {
+		return local.ResponsePublicHoliday{}, err
+	}
+
+	return local.ResponsePublicHoliday{Date: date, Name: holiday.Name}, nil
+}
+
+// DeletePublicHoliday removes the public holiday on a date
+func (s *localService) DeletePublicHoliday(ctx context.Context, date string) error {
+	day, err := parseDate(date)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return client.DeletePublicHoliday(ctx, day)
+}
+
+// ImportLegacyOpeningHours parses the free-text opened_time of every business without structured hours and stores
+// what it understands. Businesses it can't parse keep their text and are reported so their owners can enter the hours.
+// Each business is imported in its own transaction, a failure leaves it without hours rather than half a schedule.
+func (s *localService) ImportLegacyOpeningHours(ctx context.Context, actor local.Actor) (local.ResponseOpeningHoursImport, error) {
+	client, err := s.repository.NewClient(false)
+	if err != nil {
+		return local.ResponseOpeningHoursImport{}, err
+	}
+
+	var businesses []local.Locals
+	if err := client.GetLocalBusinessesWithLegacyHours(ctx, &businesses); err != nil {
+		return local.ResponseOpeningHoursImport{}, err
+	}
+
+	response := local.ResponseOpeningHoursImport{Failed: []local.ResponseOpeningHoursImportFailure{}}
+	for _, business := range businesses {
+		if ctx.Err() != nil {
+			return local.ResponseOpeningHoursImport{}, ctx.Err()
+		}
+
+		weekly, parseErr := local.ParseOpenedTime(business.OpenedTime)
+		if parseErr != nil {
+			response.Failed = append(response.Failed, local.ResponseOpeningHoursImportFailure{
+				ID:         business.ID,
+				Name:       business.Name,
+				OpenedTime: business.OpenedTime,
+				Error:      parseErr.Error(),
+			})
+			continue
+		}
+
+		rows := make([]local.OpeningHours, len(weekly))
+		for i, interval := range weekly {
+			rows[i] = openingHoursRow(business.ID, interval)
+		}
+
+		imported, err := s.importOpeningHours(ctx, actor, business.ID, rows)
+		if err != nil {
+			log.Error().Err(err).Str("local_id", business.ID.String()).Msg("failed to store imported opening hours")
+			response.Failed = append(response.Failed, local.ResponseOpeningHoursImportFailure{
+				ID:         business.ID,
+				Name:       business.Name,
+				OpenedTime: business.OpenedTime,
+				Error:      "failed to store opening hours",
+			})
+			continue
+		}
+
+		if imported {
+			response.Imported++
+		}
+	}
+
+	return response, nil
+}
+
+// importOpeningHours stores the parsed hours of one business together with their audit event. It skips the
+// business, reporting false, when the owner entered hours after the import listed it.
+func (s *localService) importOpeningHours(ctx context.Context, actor local.Actor, businessID uuid.UUID, rows []local.OpeningHours) (_ bool, err error) {
+	client, err := s.repository.NewClient(true)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = client.Rollback()
+		}
+	}()
+
+	// The lock is taken by UpdateOpeningHours too, so the check below can't race with the owner
+	business := &local.Locals{ID: businessID}
+	if err = client.GetLocalBusinessByIDForUpdate(ctx, business); err != nil {
+		return false, err
+	}
+
+	var existing []local.OpeningHours
+	if err = client.GetOpeningHours(ctx, []uuid.UUID{businessID}, &existing); err != nil {
+		return false, err
+	}
+	if len(existing) > 0 {
+		return false, client.Commit()
+	}
+
+	if err = client.ReplaceOpeningHours(ctx, businessID, rows); err != nil {
+		return false, err
+	}
+
+	err = s.audit.Record(ctx, client.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionLocalBusinessUpdated,
+		TargetType: audit.TargetLocalBusiness,
+		TargetID:   businessID.String(),
+		Changes:    audit.Changes{"opening_hours": audit.Change{To: openingHoursSummary(rows)}},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err = client.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
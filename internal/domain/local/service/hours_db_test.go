@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/local/repository"
+)
+
+// TestOpenStatusMatchesSQL pins the two implementations of the opening rules together: Schedule.Status, which
+// answers open_now, and the local_is_open SQL function behind the open_at filter. It needs a migrated database
+// given as a DSN in TEST_DATABASE_URL and writes only inside a transaction it rolls back.
+func TestOpenStatusMatchesSQL(t *testing.T) {
+	db := openTestDB(t)
+
+	ctx := context.Background()
+	client, err := repository.New(db).NewClient(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Rollback() }()
+
+	now := time.Now()
+	business := local.Locals{
+		ID:                     uuid.New(),
+		Name:                   "Warung Uji",
+		Description:            "Opening hours test fixture",
+		Address:                "Jalan Uji 1",
+		City:                   "Denpasar",
+		Province:               "Bali",
+		Timezone:               local.TimezoneWITA,
+		ClosedOnPublicHolidays: true,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+	if err := client.CreateLocalBusiness(ctx, &business); err != nil {
+		t.Fatal(err)
+	}
+
+	// A week starting Monday 3 March 2025 with daytime, midnight, overnight and touching intervals
+	hours := []local.OpeningHours{
+		{LocalID: business.ID, Weekday: int(time.Monday), OpensAt: "08:00", ClosesAt: "17:00"},
+		{LocalID: business.ID, Weekday: int(time.Tuesday), OpensAt: "08:00", ClosesAt: "24:00"},
+		{LocalID: business.ID, Weekday: int(time.Wednesday), OpensAt: "00:00", ClosesAt: "06:00"},
+		{LocalID: business.ID, Weekday: int(time.Thursday), OpensAt: "08:00", ClosesAt: "17:00"},
+		{LocalID: business.ID, Weekday: int(time.Friday), OpensAt: "08:00", ClosesAt: "17:00"},
+		{LocalID: business.ID, Weekday: int(time.Saturday), OpensAt: "20:00", ClosesAt: "02:00"},
+		{LocalID: business.ID, Weekday: int(time.Sunday), OpensAt: "10:00", ClosesAt: "14:00"},
+	}
+	if err := client.ReplaceOpeningHours(ctx, business.ID, hours); err != nil {
+		t.Fatal(err)
+	}
+
+	thursday := time.Date(2025, time.March, 6, 0, 0, 0, 0, time.UTC)
+	opens, closes := "10:00", "12:00"
+	err = client.ReplaceOpeningException(ctx, business.ID, thursday, []local.OpeningException{
+		{LocalID: business.ID, Date: thursday, OpensAt: &opens, ClosesAt: &closes, Note: "shorter hours"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	friday := thursday.AddDate(0, 0, 1)
+	err = client.ReplaceOpeningException(ctx, business.ID, friday, []local.OpeningException{
+		{LocalID: business.ID, Date: friday, Note: "closed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holiday := &local.PublicHoliday{Date: time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), Name: "Test holiday"}
+	if err := client.UpsertPublicHoliday(ctx, holiday); err != nil {
+		t.Fatal(err)
+	}
+
+	location, err := time.LoadLocation(business.Timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, time.March, 3, 0, 0, 0, 0, location)
+	end := start.AddDate(0, 0, 9)
+
+	from, _ := statusWindow(start)
+	_, to := statusWindow(end)
+	schedules, err := loadSchedules(ctx, client, []local.Locals{business}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := schedules[business.ID]
+
+	// Every quarter hour, which lands on every opening and closing above, plus the minute before each
+	for at := start; at.Before(end); at = at.Add(15 * time.Minute) {
+		for _, instant := range []time.Time{at.Add(-time.Minute), at} {
+			var sqlOpen bool
+			err := client.AuditTx().QueryRowxContext(ctx, `SELECT local_is_open($1, $2, $3)`, business.ID, business.Timezone, instant).Scan(&sqlOpen)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if goOpen, _ := schedule.Status(instant); goOpen != sqlOpen {
+				t.Errorf("at %s Schedule.Status open = %v, local_is_open = %v", instant.In(location).Format("Mon 2006-01-02 15:04"), goOpen, sqlOpen)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+	"github.com/rs/zerolog/log"
+)
+
+// bookingStatusFromMidtrans maps a Midtrans transaction and fraud status onto a booking status.
+// An empty result means the notification does not settle the booking yet (e.g. pending or challenged).
+func bookingStatusFromMidtrans(transactionStatus, fraudStatus string) local.BookingStatus {
+	switch transactionStatus {
+	case "capture":
+		if fraudStatus == "accept" {
+			return local.BookingStatusPaid
+		}
+		if fraudStatus == "deny" {
+			return local.BookingStatusCancelled
+		}
+		return ""
+	case "settlement":
+		return local.BookingStatusPaid
+	case "deny", "cancel":
+		return local.BookingStatusCancelled
+	case "expire":
+		return local.BookingStatusExpired
+	case "refund", "partial_refund":
+		return local.BookingStatusRefunded
+	default:
+		return ""
+	}
+}
+
+// grossAmountMatches reports whether a Midtrans gross_amount, sent as a decimal string such as "150000.00",
+// is the amount the booking was charged
+func grossAmountMatches(grossAmount string, amount int64) bool {
+	paid, err := strconv.ParseFloat(grossAmount, 64)
+	return err == nil && int64(paid) == amount
+}
+
+// HandlePaymentNotification processes a Midtrans HTTP notification for a tour guide booking.
+// The notification is only trusted after its signature is verified and its status is confirmed
+// with the Midtrans Core API; repeated or out-of-order notifications are no-ops.
+func (s *localService) HandlePaymentNotification(ctx context.Context, request payment.Notification) (err error) {
+	if !payment.VerifySignature(s.coreAPI.ServerKey, request.OrderID, request.StatusCode, request.GrossAmount, request.SignatureKey) {
+		return local.ErrInvalidPaymentSignature
+	}
+
+	bookingID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return local.ErrBookingNotFound
+	}
+
+	// Never trust the notification body alone, ask Midtrans for the actual status
+	transaction, midtransErr := s.coreAPI.CheckTransaction(request.OrderID)
+	if midtransErr != nil {
+		return local.ErrPaymentStatusUnavailable.WithErr(midtransErr)
+	}
+	if transaction.OrderID != request.OrderID {
+		return local.ErrPaymentStatusUnavailable
+	}
+
+	nextStatus := bookingStatusFromMidtrans(transaction.TransactionStatus, transaction.FraudStatus)
+	if nextStatus == "" {
+		return nil
+	}
+
+	repository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	booking := &local.TourGuideBookings{ID: bookingID}
+	if err = repository.GetTourGuideBookingByIDForUpdate(ctx, booking); err != nil {
+		return err
+	}
+
+	if nextStatus == local.BookingStatusPaid && !grossAmountMatches(transaction.GrossAmount, booking.Amount) {
+		// Rejecting the notification would only make Midtrans retry it, so park the booking for review
+		log.Error().
+			Str("booking_id", booking.ID.String()).
+			Str("gross_amount", transaction.GrossAmount).
+			Int64("amount", booking.Amount).
+			Msg("booking payment amount does not match the booking, booking flagged")
+		nextStatus = local.BookingStatusFlagged
+	}
+
+	previous := booking.Status
+	reason := fmt.Sprintf("midtrans %s (%s)", transaction.TransactionStatus, transaction.TransactionID)
+	err = transitionBooking(ctx, repository, booking, nextStatus, local.BookingActorMidtrans, reason)
+	if errors.Is(err, local.ErrInvalidBookingTransition) {
+		// Already processed or superseded by a later notification
+		err = nil
+		return repository.Commit()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Tour guides are assigned automatically, so a paid booking is confirmed right away
+	if booking.Status == local.BookingStatusPaid {
+		err = transitionBooking(ctx, repository, booking, local.BookingStatusConfirmed, local.BookingActorSystem, "tour guide slot allocated")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.recordBookingStatusChange(ctx, repository, audit.SystemActor(local.BookingActorMidtrans), booking.ID, previous, booking.Status, reason)
+	if err != nil {
+		return err
+	}
+
+	return repository.Commit()
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+func TestGrossAmountMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		grossAmount string
+		amount      int64
+		want        bool
+	}{
+		{name: "decimal string of the amount", grossAmount: "150000.00", amount: 150000, want: true},
+		{name: "integer string of the amount", grossAmount: "150000", amount: 150000, want: true},
+		{name: "less than the amount", grossAmount: "1000.00", amount: 150000},
+		{name: "more than the amount", grossAmount: "150001.00", amount: 150000},
+		{name: "not a number", grossAmount: "free", amount: 150000},
+		{name: "empty", grossAmount: "", amount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grossAmountMatches(tt.grossAmount, tt.amount); got != tt.want {
+				t.Errorf("grossAmountMatches(%q, %d) = %v, want %v", tt.grossAmount, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookingStatusFromMidtrans(t *testing.T) {
+	tests := []struct {
+		transactionStatus string
+		fraudStatus       string
+		want              local.BookingStatus
+	}{
+		{transactionStatus: "capture", fraudStatus: "accept", want: local.BookingStatusPaid},
+		{transactionStatus: "capture", fraudStatus: "challenge", want: ""},
+		{transactionStatus: "capture", fraudStatus: "deny", want: local.BookingStatusCancelled},
+		{transactionStatus: "settlement", want: local.BookingStatusPaid},
+		{transactionStatus: "pending", want: ""},
+		{transactionStatus: "deny", want: local.BookingStatusCancelled},
+		{transactionStatus: "cancel", want: local.BookingStatusCancelled},
+		{transactionStatus: "expire", want: local.BookingStatusExpired},
+		{transactionStatus: "refund", want: local.BookingStatusRefunded},
+		{transactionStatus: "partial_refund", want: local.BookingStatusRefunded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transactionStatus+"/"+tt.fraudStatus, func(t *testing.T) {
+			if got := bookingStatusFromMidtrans(tt.transactionStatus, tt.fraudStatus); got != tt.want {
+				t.Errorf("bookingStatusFromMidtrans(%q, %q) = %q, want %q", tt.transactionStatus, tt.fraudStatus, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
+)
+
+// SearchCatalogue runs a full-text search over local businesses and tourist attractions, best matches first.
+// Facets are counted on the first page only, later pages share the same matches.
+func (s *localService) SearchCatalogue(ctx context.Context, request local.QueryParamRequestSearch) (local.ResponseSearch, error) {
+	request.Query = strings.TrimSpace(request.Query)
+
+	page, err := resolveCataloguePage(string(local.SortRelevance), "", request.Cursor, request.Limit, []local.CatalogueSort{local.SortRelevance})
+	if err != nil {
+		return local.ResponseSearch{}, err
+	}
+	request.After, request.Limit = page.after, page.limit
+
+	var facets *local.ResponseSearchFacets
+	if request.After == nil {
+		facets = &local.ResponseSearchFacets{
+			Types:     []local.ResponseFacetCount{},
+			Provinces: []local.ResponseFacetCount{},
+			Labels:    []local.ResponseFacetCount{},
+		}
+	}
+
+	// Tourist attractions have no label, so nothing can match
+	if request.Type == string(local.ListingTouristAttraction) && request.Label != "" {
+		return local.ResponseSearch{Page: pagination.NewPage([]local.ResponseSearchResult{}, page.limit, nil), Facets: facets}, nil
+	}
+
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return local.ResponseSearch{}, err
+	}
+
+	var results []local.SearchResult
+	if err := repository.SearchCatalogue(ctx, request, &results); err != nil {
+		return local.ResponseSearch{}, err
+	}
+
+	if facets != nil {
+		var counts []local.SearchFacetCount
+		if err := repository.GetSearchFacets(ctx, request, &counts); err != nil {
+			return local.ResponseSearch{}, err
+		}
+
+		for _, count := range counts {
+			facet := local.ResponseFacetCount{Value: count.Value, Count: count.Count}
+			switch count.Facet {
+			case local.FacetType:
+				facets.Types = append(facets.Types, facet)
+			case local.FacetProvince:
+				facets.Provinces = append(facets.Provinces, facet)
+			case local.FacetLabel:
+				facets.Labels = append(facets.Labels, facet)
+			}
+		}
+	}
+
+	response := make([]local.ResponseSearchResult, len(results))
+	for i, result := range results {
+		response[i] = local.ResponseSearchResult{
+			Type:          result.Type,
+			ID:            result.ID,
+			Name:          result.Name,
+			NameHighlight: highlight(result.NameHighlight),
+			Snippet:       highlight(result.Snippet),
+			City:          result.City,
+			Province:      result.Province,
+			Label:         result.Label,
+			PhotoUrl:      result.PhotoURL,
+			Score:         result.Rank,
+		}
+	}
+
+	return local.ResponseSearch{
+		Page: pagination.NewPage(response, page.limit, func(result local.ResponseSearchResult) pagination.Cursor {
+			return page.cursor(sortableListing{id: result.ID, rank: result.Score})
+		}),
+		Facets: facets,
+	}, nil
+}
+
+// highlight HTML escapes a headline and turns the highlight delimiters into <mark> tags
+func highlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, local.SearchHighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, local.SearchHighlightStop, "</mark>")
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
+	"github.com/rs/zerolog/log"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
 	"github.com/vistara-studio/vistara-be/internal/domain/local/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
 )
 
 // localService implements the local business service
@@ -15,34 +20,79 @@ type localService struct {
 	repository repository.RepositoryInterface
 	snapClient snap.Client
 	coreAPI    coreapi.Client
+	audit      audit.Recorder
+	remover    storage.Remover
 }
 
 // LocalServiceInterface defines the contract for local business operations
 type LocalServiceInterface interface {
 	// Local business operations
-	GetAllLocalBusinessesWithFilters(ctx context.Context, request local.QueryParamRequestGetLocals) ([]local.ResponseGetLocalBusinesses, error)
+	GetAllLocalBusinessesWithFilters(ctx context.Context, request local.QueryParamRequestGetLocals) (pagination.Page[local.ResponseGetLocalBusinesses], error)
+	GetNearbyLocalBusinesses(ctx context.Context, request local.QueryParamRequestNearby) (pagination.Page[local.ResponseNearbyLocalBusiness], error)
 	GetLocalBusinessByID(ctx context.Context, businessID uuid.UUID) (local.ResponseGetLocalBusinesses, error)
-	CreateLocalBusiness(ctx context.Context, request local.RequestCreateLocalBusiness) (local.ResponseGetLocalBusinesses, error)
-	UpdateLocalBusiness(ctx context.Context, businessID uuid.UUID, request local.RequestUpdateLocalBusiness) (local.ResponseGetLocalBusinesses, error)
-	DeleteLocalBusiness(ctx context.Context, businessID uuid.UUID) error
+	CreateLocalBusiness(ctx context.Context, actor local.Actor, request local.RequestCreateLocalBusiness) (local.ResponseGetLocalBusinesses, error)
+	UpdateLocalBusiness(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestUpdateLocalBusiness) (local.ResponseGetLocalBusinesses, error)
+	DeleteLocalBusiness(ctx context.Context, actor local.Actor, businessID uuid.UUID) error
+	SearchCatalogue(ctx context.Context, request local.QueryParamRequestSearch) (local.ResponseSearch, error)
+
+	// Opening hours operations
+	GetOpeningHours(ctx context.Context, businessID uuid.UUID) (local.ResponseOpeningHours, error)
+	UpdateOpeningHours(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestOpeningHours) (local.ResponseOpeningHours, error)
+	SetOpeningException(ctx context.Context, actor local.Actor, businessID uuid.UUID, date string, request local.RequestOpeningException) (local.ResponseOpeningHours, error)
+	DeleteOpeningException(ctx context.Context, actor local.Actor, businessID uuid.UUID, date string) error
+	GetPublicHolidays(ctx context.Context, request local.QueryParamRequestPublicHolidays) ([]local.ResponsePublicHoliday, error)
+	SetPublicHoliday(ctx context.Context, date string, request local.RequestPublicHoliday) (local.ResponsePublicHoliday, error)
+	DeletePublicHoliday(ctx context.Context, date string) error
+	ImportLegacyOpeningHours(ctx context.Context, actor local.Actor) (local.ResponseOpeningHoursImport, error)
+
+	// Category link operations
+	SetLocalBusinessCategories(ctx context.Context, actor local.Actor, businessID uuid.UUID, request local.RequestSetCategories) ([]local.ResponseListingCategory, error)
+	SetTouristAttractionCategories(ctx context.Context, actor local.Actor, attractionID uuid.UUID, request local.RequestSetCategories) ([]local.ResponseListingCategory, error)
 	
 	// Tourist attraction operations
-	GetAllTouristAttractions(ctx context.Context, city string) ([]local.ResponseGetTourGuide, error)
+	GetAllTouristAttractions(ctx context.Context, request local.QueryParamRequestGetTouristAttractions) (pagination.Page[local.ResponseGetTourGuide], error)
+	GetNearbyTouristAttractions(ctx context.Context, request local.QueryParamRequestNearby) (pagination.Page[local.ResponseNearbyTouristAttraction], error)
 	GetTouristAttractionByID(ctx context.Context, attractionID uuid.UUID) (local.ResponseGetTourGuide, error)
-	CreateTouristAttraction(ctx context.Context, request local.RequestCreateTouristAttraction) (local.ResponseGetTourGuide, error)
-	UpdateTouristAttraction(ctx context.Context, attractionID uuid.UUID, request local.RequestUpdateTouristAttraction) (local.ResponseGetTourGuide, error)
-	DeleteTouristAttraction(ctx context.Context, attractionID uuid.UUID) error
+	CreateTouristAttraction(ctx context.Context, actor local.Actor, request local.RequestCreateTouristAttraction) (local.ResponseGetTourGuide, error)
+	UpdateTouristAttraction(ctx context.Context, actor local.Actor, attractionID uuid.UUID, request local.RequestUpdateTouristAttraction) (local.ResponseGetTourGuide, error)
+	DeleteTouristAttraction(ctx context.Context, actor local.Actor, attractionID uuid.UUID) error
 	
 	// Booking operations
 	GeneratePaymentSnapLink(ctx context.Context, request local.RequestGenerateSnapLink) (local.ResponseGenerateSnapLink, error)
 	GetFullyBookedDates(ctx context.Context, attractionID string, year, month int) ([]string, error)
+	GetBookingStatusHistory(ctx context.Context, bookingID, userID uuid.UUID) ([]local.ResponseBookingStatusHistory, error)
+	HandlePaymentNotification(ctx context.Context, request payment.Notification) error
+	ExpireStaleBookings(ctx context.Context) error
+}
+
+// authorizeListingWrite lets admins change any listing and everyone else only the listings they own
+func authorizeListingWrite(actor local.Actor, ownerID *uuid.UUID) error {
+	if actor.IsAdmin {
+		return nil
+	}
+
+	if ownerID == nil || *ownerID != actor.UserID {
+		return local.ErrNotListingOwner
+	}
+
+	return nil
 }
 
 // New creates a new local service instance
-func New(repo repository.RepositoryInterface, snapClient snap.Client, coreAPI coreapi.Client) LocalServiceInterface {
+func New(repo repository.RepositoryInterface, snapClient snap.Client, coreAPI coreapi.Client, recorder audit.Recorder, remover storage.Remover) LocalServiceInterface {
 	return &localService{
 		repository: repo,
 		snapClient: snapClient,
 		coreAPI:    coreAPI,
+		audit:      recorder,
+		remover:    remover,
+	}
+}
+
+// removeListingPhotos deletes the stored gallery of a deleted listing, the media rows are gone with the listing
+// so a failure only leaves unreferenced objects behind
+func (s *localService) removeListingPhotos(ctx context.Context, listingType local.ListingType, listingID uuid.UUID) {
+	if err := storage.RemoveListingMedia(ctx, s.remover, string(listingType), listingID); err != nil {
+		log.Warn().Err(err).Str("listing_id", listingID.String()).Msg("failed to remove listing photos")
 	}
 }
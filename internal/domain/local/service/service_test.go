@@ -0,0 +1,34 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+func TestAuthorizeListingWrite(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		actor   local.Actor
+		ownerID *uuid.UUID
+		wantErr error
+	}{
+		{name: "owner", actor: local.Actor{UserID: owner}, ownerID: &owner},
+		{name: "someone else", actor: local.Actor{UserID: other}, ownerID: &owner, wantErr: local.ErrNotListingOwner},
+		{name: "listing without an owner", actor: local.Actor{UserID: other}, wantErr: local.ErrNotListingOwner},
+		{name: "admin on someone else's listing", actor: local.Actor{UserID: other, IsAdmin: true}, ownerID: &owner},
+		{name: "admin on a listing without an owner", actor: local.Actor{UserID: other, IsAdmin: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := authorizeListingWrite(tt.actor, tt.ownerID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("authorizeListingWrite() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
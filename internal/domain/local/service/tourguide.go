@@ -8,45 +8,102 @@ import (
 	"github.com/google/uuid"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/snap"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/pkg/pagination"
 )
 
-// GetAllTouristAttractions retrieves all tourist attractions with optional city filtering
-func (s *localService) GetAllTouristAttractions(ctx context.Context, city string) ([]local.ResponseGetTourGuide, error) {
+// GetAllTouristAttractions retrieves one page of tourist attractions matching the filters
+func (s *localService) GetAllTouristAttractions(ctx context.Context, request local.QueryParamRequestGetTouristAttractions) (pagination.Page[local.ResponseGetTourGuide], error) {
+	if request.MinPrice != nil && request.MaxPrice != nil && *request.MinPrice > *request.MaxPrice {
+		return pagination.Page[local.ResponseGetTourGuide]{}, local.ErrInvalidPriceRange
+	}
+
+	page, err := resolveCataloguePage(request.Sort, request.Order, request.Cursor, request.Limit, local.TouristAttractionSorts)
+	if err != nil {
+		return pagination.Page[local.ResponseGetTourGuide]{}, err
+	}
+	request.Sort, request.Order, request.After, request.Limit = string(page.sort), page.order, page.after, page.limit
+
 	repository, err := s.repository.NewClient(false)
 	if err != nil {
-		return []local.ResponseGetTourGuide{}, err
+		return pagination.Page[local.ResponseGetTourGuide]{}, err
 	}
 
 	var touristAttractions []local.TouristAttractions
-	err = repository.GetAllTouristAttractions(ctx, city, &touristAttractions)
+	err = repository.GetAllTouristAttractions(ctx, request, &touristAttractions)
 	if err != nil {
-		return []local.ResponseGetTourGuide{}, err
+		return pagination.Page[local.ResponseGetTourGuide]{}, err
 	}
 
 	// Transform domain entities to response DTOs
 	response := make([]local.ResponseGetTourGuide, len(touristAttractions))
 	for i, attraction := range touristAttractions {
-		response[i] = local.ResponseGetTourGuide{
-			ID:                          attraction.ID,
-			Name:                        attraction.Name,
-			Description:                 attraction.Description,
-			Address:                     attraction.Address,
-			City:                        attraction.City,
-			Province:                    attraction.Province,
-			Longitude:                   attraction.Longitude,
-			Latitude:                    attraction.Latitude,
-			PhotoUrl:                    attraction.PhotoURL,
-			TourGuidePrice:              attraction.TourGuidePrice,
-			TourGuideCount:              attraction.TourGuideCount,
-			TourGuideDiscountPercentage: attraction.TourGuideDiscountPercentage,
-			Price:                       attraction.Price,
-			DiscountPercentage:          attraction.DiscountPercentage,
-			CreatedAt:                   attraction.CreatedAt,
+		response[i] = newTouristAttractionListResponse(attraction)
+	}
+
+	return pagination.NewPage(response, page.limit, func(attraction local.ResponseGetTourGuide) pagination.Cursor {
+		return page.cursor(sortableListing{
+			id:        attraction.ID,
+			name:      attraction.Name,
+			createdAt: attraction.CreatedAt,
+			price:     attraction.Price,
+			rating:    attraction.Rating,
+		})
+	}), nil
+}
+
+// GetNearbyTouristAttractions retrieves one page of tourist attractions within the radius of a point, nearest first
+func (s *localService) GetNearbyTouristAttractions(ctx context.Context, request local.QueryParamRequestNearby) (pagination.Page[local.ResponseNearbyTouristAttraction], error) {
+	page, err := resolveNearbyPage(&request)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyTouristAttraction]{}, err
+	}
+
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyTouristAttraction]{}, err
+	}
+
+	var touristAttractions []local.TouristAttractions
+	err = repository.GetNearbyTouristAttractions(ctx, request, &touristAttractions)
+	if err != nil {
+		return pagination.Page[local.ResponseNearbyTouristAttraction]{}, err
+	}
+
+	response := make([]local.ResponseNearbyTouristAttraction, len(touristAttractions))
+	for i, attraction := range touristAttractions {
+		response[i] = local.ResponseNearbyTouristAttraction{
+			ResponseGetTourGuide: newTouristAttractionListResponse(attraction),
+			Distance:             attraction.Distance,
 		}
 	}
 
-	return response, nil
+	return pagination.NewPage(response, page.limit, func(attraction local.ResponseNearbyTouristAttraction) pagination.Cursor {
+		return page.cursor(sortableListing{id: attraction.ID, distance: attraction.Distance})
+	}), nil
+}
+
+// newTouristAttractionListResponse maps a tourist attraction row of a list, lists carry the rating but no reviews
+func newTouristAttractionListResponse(attraction local.TouristAttractions) local.ResponseGetTourGuide {
+	return local.ResponseGetTourGuide{
+		ID:                          attraction.ID,
+		Name:                        attraction.Name,
+		Description:                 attraction.Description,
+		Address:                     attraction.Address,
+		City:                        attraction.City,
+		Province:                    attraction.Province,
+		Longitude:                   attraction.Longitude,
+		Latitude:                    attraction.Latitude,
+		PhotoUrl:                    attraction.PhotoURL,
+		TourGuidePrice:              attraction.TourGuidePrice,
+		TourGuideCount:              attraction.TourGuideCount,
+		TourGuideDiscountPercentage: attraction.TourGuideDiscountPercentage,
+		Price:                       attraction.Price,
+		DiscountPercentage:          attraction.DiscountPercentage,
+		CreatedAt:                   attraction.CreatedAt,
+		Rating:                      attraction.Rating,
+	}
 }
 
 // GetTouristAttractionByID retrieves a specific tourist attraction by its ID with bookings/reviews
@@ -84,6 +141,12 @@ func (s *localService) GetTouristAttractionByID(ctx context.Context, attractionI
 		}
 	}
 
+	var categories []local.ListingCategory
+	err = repository.GetTouristAttractionCategories(ctx, attractionID, &categories)
+	if err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
 	return local.ResponseGetTourGuide{
 		ID:                          attraction.ID,
 		Name:                        attraction.Name,
@@ -101,6 +164,7 @@ func (s *localService) GetTouristAttractionByID(ctx context.Context, attractionI
 		DiscountPercentage:          attraction.DiscountPercentage,
 		CreatedAt:                   attraction.CreatedAt,
 		Reviews:                     reviews,
+		Categories:                  newListingCategoryResponses(categories),
 	}, nil
 }
 
@@ -120,95 +184,196 @@ func (s *localService) GetFullyBookedDates(ctx context.Context, attractionID str
 	return dates, nil
 }
 
-// GeneratePaymentSnapLink generates a Midtrans Snap payment link for tour guide booking
+// GeneratePaymentSnapLink reserves a tour guide slot for the requested date and generates a
+// Midtrans Snap payment link for it. The reservation is made under a lock on the attraction row
+// so concurrent requests cannot take more slots than TourGuideCount; unpaid reservations hold
+// their slot until the payment expires.
 func (s *localService) GeneratePaymentSnapLink(ctx context.Context, request local.RequestGenerateSnapLink) (local.ResponseGenerateSnapLink, error) {
-	repository, err := s.repository.NewClient(false)
-	if err != nil {
-		return local.ResponseGenerateSnapLink{}, err
-	}
-
 	// Parse and validate tourist attraction ID
 	attractionID, err := uuid.Parse(request.TAID)
 	if err != nil {
 		return local.ResponseGenerateSnapLink{}, fmt.Errorf("invalid tourist attraction ID: %w", err)
 	}
 
-	// Get tourist attraction details
-	attraction := &local.TouristAttractions{
-		ID: attractionID,
+	// Parse user ID
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return local.ResponseGenerateSnapLink{}, fmt.Errorf("invalid user ID: %w", err)
 	}
-	err = repository.GetTouristAttractionByID(ctx, attraction)
+
+	// Parse booking date
+	bookedAt, err := time.Parse("2006-01-02", request.BookedAt)
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, err
+		return local.ResponseGenerateSnapLink{}, local.ErrInvalidBookingDate
+	}
+	bookedAt = time.Date(bookedAt.Year(), bookedAt.Month(), bookedAt.Day(), 0, 0, 0, 0, time.UTC)
+
+	now := time.Now().UTC()
+	if bookedAt.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+		return local.ResponseGenerateSnapLink{}, local.ErrInvalidBookingDate
 	}
 
-	// Generate unique transaction ID
-	transactionID, err := uuid.NewV7()
+	attraction := &local.TouristAttractions{ID: attractionID}
+	booking, err := s.reserveTourGuideSlot(ctx, attraction, userID, bookedAt)
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to generate transaction ID: %w", err)
+		return local.ResponseGenerateSnapLink{}, err
 	}
 
-	// Create Midtrans Snap request
+	// Create Midtrans Snap request, the booking ID doubles as the order ID
 	snapRequest := &snap.Request{
 		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  transactionID.String(),
-			GrossAmt: attraction.TourGuidePrice,
+			OrderID:  booking.ID.String(),
+			GrossAmt: booking.Amount,
 		},
 		EnabledPayments: snap.AllSnapPaymentType,
 		Expiry: &snap.ExpiryDetails{
-			Duration: 24, // 24 hours expiry
+			Duration: int64(bookingHoldDuration / time.Hour),
 			Unit:     "hours",
 		},
 	}
 
-	// Create payment token
-	snapToken, err := s.snapClient.CreateTransactionToken(snapRequest)
+	// Create payment token outside the reservation transaction to keep the lock short
+	snapToken, snapErr := s.snapClient.CreateTransactionToken(snapRequest)
+	if snapErr != nil {
+		if err := s.releaseTourGuideSlot(ctx, booking.ID, "payment token creation failed"); err != nil {
+			return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to release booking %s: %w", booking.ID, err)
+		}
+		return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to create payment token: %w", snapErr)
+	}
+
+	repository, err := s.repository.NewClient(false)
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to create payment token: %w", err)
+		return local.ResponseGenerateSnapLink{}, err
 	}
 
-	// Parse booking date
-	layout := "2006-01-02"
-	bookedAt, err := time.Parse(layout, request.BookedAt)
+	booking.PaymentURL = fmt.Sprintf("https://app.sandbox.midtrans.com/snap/v4/redirection/%s", snapToken)
+	if err := repository.UpdateTourGuideBookingPaymentURL(ctx, booking); err != nil {
+		return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to save payment URL: %w", err)
+	}
+
+	return local.ResponseGenerateSnapLink{
+		TAID:       attractionID.String(),
+		PaymentUrl: snapToken,
+	}, nil
+}
+
+// reserveTourGuideSlot creates a pending booking if the attraction still has a free tour guide on the date
+func (s *localService) reserveTourGuideSlot(ctx context.Context, attraction *local.TouristAttractions, userID uuid.UUID, bookedAt time.Time) (_ *local.TourGuideBookings, err error) {
+	repository, err := s.repository.NewClient(true)
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, fmt.Errorf("invalid booking date format: %w", err)
+		return nil, err
 	}
-	bookedAt = time.Date(bookedAt.Year(), bookedAt.Month(), bookedAt.Day(), 0, 0, 0, 0, time.UTC)
 
-	// Parse user ID
-	userID, err := uuid.Parse(request.UserID)
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	// Lock the attraction so concurrent reservations are counted one after another
+	if err = repository.GetTouristAttractionByIDForUpdate(ctx, attraction); err != nil {
+		return nil, err
+	}
+
+	var activeBookings int
+	err = repository.CountActiveBookingsOnDate(ctx, attraction.ID.String(), bookedAt, &activeBookings)
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("failed to count bookings: %w", err)
+	}
+
+	if activeBookings >= attraction.TourGuideCount {
+		err = local.ErrFullyBooked
+		return nil, err
 	}
 
-	// Create booking record
+	bookingID, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate transaction ID: %w", err)
+	}
+
+	expiresAt := time.Now().Add(bookingHoldDuration)
 	booking := &local.TourGuideBookings{
-		ID:                   transactionID,
-		PaymentURL:           fmt.Sprintf("https://app.sandbox.midtrans.com/snap/v4/redirection/%s", snapToken),
+		ID:                   bookingID,
 		BookedAt:             bookedAt,
-		Status:               "pending_payment",
+		ExpiresAt:            &expiresAt,
+		Status:               local.BookingStatusPendingPayment,
+		Amount:               attraction.TourGuidePrice,
 		UserID:               userID,
-		TouristAttractionsID: attractionID,
+		TouristAttractionsID: attraction.ID,
+	}
+
+	if err = repository.CreateTourGuideBooking(ctx, booking); err != nil {
+		return nil, fmt.Errorf("failed to create booking: %w", err)
 	}
 
-	err = repository.CreateTourGuideBooking(ctx, booking)
+	err = recordBookingStatus(ctx, repository, booking.ID, "", booking.Status, userActor(userID), "tour guide slot reserved")
 	if err != nil {
-		return local.ResponseGenerateSnapLink{}, fmt.Errorf("failed to create booking: %w", err)
+		return nil, fmt.Errorf("failed to record booking status history: %w", err)
 	}
 
-	return local.ResponseGenerateSnapLink{
-		TAID:       attractionID.String(),
-		PaymentUrl: snapToken,
-	}, nil
+	err = s.audit.Record(ctx, repository.AuditTx(), audit.Entry{
+		Actor:      audit.ActorFromContext(ctx),
+		Action:     audit.ActionBookingCreated,
+		TargetType: audit.TargetBooking,
+		TargetID:   booking.ID.String(),
+		Changes:    audit.Diff(nil, booking),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = repository.Commit(); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// releaseTourGuideSlot cancels a pending booking so its slot becomes available again
+func (s *localService) releaseTourGuideSlot(ctx context.Context, bookingID uuid.UUID, reason string) (err error) {
+	repository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	booking := &local.TourGuideBookings{ID: bookingID}
+	if err = repository.GetTourGuideBookingByIDForUpdate(ctx, booking); err != nil {
+		return err
+	}
+
+	previous := booking.Status
+	err = transitionBooking(ctx, repository, booking, local.BookingStatusCancelled, local.BookingActorSystem, reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.recordBookingStatusChange(ctx, repository, audit.SystemActor(local.BookingActorSystem), booking.ID, previous, booking.Status, reason)
+	if err != nil {
+		return err
+	}
+
+	return repository.Commit()
 }
 
 // CreateTouristAttraction creates a new tourist attraction
-func (s *localService) CreateTouristAttraction(ctx context.Context, request local.RequestCreateTouristAttraction) (local.ResponseGetTourGuide, error) {
-	repository, err := s.repository.NewClient(false)
+func (s *localService) CreateTouristAttraction(ctx context.Context, actor local.Actor, request local.RequestCreateTouristAttraction) (_ local.ResponseGetTourGuide, err error) {
+	repository, err := s.repository.NewClient(true)
 	if err != nil {
 		return local.ResponseGetTourGuide{}, err
 	}
 
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
 	// Generate new UUID for the attraction
 	attractionID := uuid.New()
 	now := time.Now()
@@ -220,14 +385,15 @@ func (s *localService) CreateTouristAttraction(ctx context.Context, request loca
 		Address:                     request.Address,
 		City:                        request.City,
 		Province:                    request.Province,
-		Longitude:                   request.Longitude,
-		Latitude:                    request.Latitude,
+		Longitude:                   request.Longitude.Degrees(),
+		Latitude:                    request.Latitude.Degrees(),
 		PhotoURL:                    request.PhotoUrl,
 		TourGuidePrice:              request.TourGuidePrice,
 		TourGuideCount:              request.TourGuideCount,
 		TourGuideDiscountPercentage: request.TourGuideDiscountPercentage,
 		Price:                       request.Price,
 		DiscountPercentage:          request.DiscountPercentage,
+		OwnerID:                     &actor.UserID,
 		CreatedAt:                   now,
 		UpdatedAt:                   now,
 	}
@@ -237,6 +403,21 @@ func (s *localService) CreateTouristAttraction(ctx context.Context, request loca
 		return local.ResponseGetTourGuide{}, fmt.Errorf("failed to create tourist attraction: %w", err)
 	}
 
+	err = s.audit.Record(ctx, repository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionTouristAttractionCreated,
+		TargetType: audit.TargetTouristAttraction,
+		TargetID:   attraction.ID.String(),
+		Changes:    audit.Diff(nil, attraction),
+	})
+	if err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
+	if err = repository.Commit(); err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
 	// Return the created attraction
 	return local.ResponseGetTourGuide{
 		ID:                          attraction.ID,
@@ -259,19 +440,31 @@ func (s *localService) CreateTouristAttraction(ctx context.Context, request loca
 }
 
 // UpdateTouristAttraction updates an existing tourist attraction
-func (s *localService) UpdateTouristAttraction(ctx context.Context, attractionID uuid.UUID, request local.RequestUpdateTouristAttraction) (local.ResponseGetTourGuide, error) {
-	repository, err := s.repository.NewClient(false)
+func (s *localService) UpdateTouristAttraction(ctx context.Context, actor local.Actor, attractionID uuid.UUID, request local.RequestUpdateTouristAttraction) (_ local.ResponseGetTourGuide, err error) {
+	repository, err := s.repository.NewClient(true)
 	if err != nil {
 		return local.ResponseGetTourGuide{}, err
 	}
 
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
 	// First, get the existing attraction
 	attraction := &local.TouristAttractions{ID: attractionID}
-	err = repository.GetTouristAttractionByID(ctx, attraction)
+	err = repository.GetTouristAttractionByIDForUpdate(ctx, attraction)
 	if err != nil {
 		return local.ResponseGetTourGuide{}, fmt.Errorf("failed to get tourist attraction: %w", err)
 	}
 
+	if err = authorizeListingWrite(actor, attraction.OwnerID); err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
+	before := *attraction
+
 	// Update only provided fields
 	if request.Name != nil {
 		attraction.Name = *request.Name
@@ -289,10 +482,10 @@ func (s *localService) UpdateTouristAttraction(ctx context.Context, attractionID
 		attraction.Province = *request.Province
 	}
 	if request.Longitude != nil {
-		attraction.Longitude = *request.Longitude
+		attraction.Longitude = request.Longitude.Degrees()
 	}
 	if request.Latitude != nil {
-		attraction.Latitude = *request.Latitude
+		attraction.Latitude = request.Latitude.Degrees()
 	}
 	if request.PhotoUrl != nil {
 		attraction.PhotoURL = *request.PhotoUrl
@@ -320,6 +513,21 @@ func (s *localService) UpdateTouristAttraction(ctx context.Context, attractionID
 		return local.ResponseGetTourGuide{}, fmt.Errorf("failed to update tourist attraction: %w", err)
 	}
 
+	err = s.audit.Record(ctx, repository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionTouristAttractionUpdated,
+		TargetType: audit.TargetTouristAttraction,
+		TargetID:   attraction.ID.String(),
+		Changes:    audit.Diff(before, attraction),
+	})
+	if err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
+	if err = repository.Commit(); err != nil {
+		return local.ResponseGetTourGuide{}, err
+	}
+
 	// Return the updated attraction
 	return local.ResponseGetTourGuide{
 		ID:                          attraction.ID,
@@ -342,16 +550,47 @@ func (s *localService) UpdateTouristAttraction(ctx context.Context, attractionID
 }
 
 // DeleteTouristAttraction deletes a tourist attraction
-func (s *localService) DeleteTouristAttraction(ctx context.Context, attractionID uuid.UUID) error {
-	repository, err := s.repository.NewClient(false)
+func (s *localService) DeleteTouristAttraction(ctx context.Context, actor local.Actor, attractionID uuid.UUID) (err error) {
+	repository, err := s.repository.NewClient(true)
 	if err != nil {
 		return err
 	}
 
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	attraction := &local.TouristAttractions{ID: attractionID}
+	if err = repository.GetTouristAttractionByIDForUpdate(ctx, attraction); err != nil {
+		return fmt.Errorf("failed to get tourist attraction: %w", err)
+	}
+
+	if err = authorizeListingWrite(actor, attraction.OwnerID); err != nil {
+		return err
+	}
+
 	err = repository.DeleteTouristAttraction(ctx, attractionID.String())
 	if err != nil {
 		return fmt.Errorf("failed to delete tourist attraction: %w", err)
 	}
 
+	err = s.audit.Record(ctx, repository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionTouristAttractionDeleted,
+		TargetType: audit.TargetTouristAttraction,
+		TargetID:   attraction.ID.String(),
+		Changes:    audit.Diff(attraction, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = repository.Commit(); err != nil {
+		return err
+	}
+
+	s.removeListingPhotos(ctx, local.ListingTouristAttraction, attraction.ID)
 	return nil
 }
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/local/repository"
+)
+
+// discardRecorder drops audit entries, the append-only audit table can't be cleaned up after a test
+type discardRecorder struct{}
+
+func (discardRecorder) Record(context.Context, audit.Tx, audit.Entry) error {
+	return nil
+}
+
+// TestReserveTourGuideSlotConcurrently books more slots at once than an attraction has tour guides and
+// expects exactly as many bookings as guides. It needs a migrated database given in TEST_DATABASE_URL
+// and deletes the user and attraction it creates.
+func TestReserveTourGuideSlotConcurrently(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	userID, attractionID := uuid.New(), uuid.New()
+	_, err := db.ExecContext(ctx, `INSERT INTO users (id, full_name, email, auth_provider, photo_url) VALUES ($1, 'Slot Test', $2, 'email', '')`,
+		userID, userID.String()+"@example.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID) })
+
+	const guides, price = 2, 150000
+	_, err = db.ExecContext(ctx, `INSERT INTO tourist_attractions (
+			id, name, description, address, city, province, longitude, latitude, photo_url,
+			tour_guide_price, tour_guide_count, tour_guide_discount_percentage, price, discount_percentage
+		) VALUES ($1, 'Slot Test', '', '', 'Denpasar', 'Bali', 115.2, -8.65, '', $2, $3, 0, 0, 0)`,
+		attractionID, price, guides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, `DELETE FROM tourist_attractions WHERE id = $1`, attractionID) })
+
+	s := &localService{repository: repository.New(db), audit: discardRecorder{}}
+	bookedAt := time.Now().UTC().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+
+	const attempts = 8
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	amounts := make(chan int64, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			booking, err := s.reserveTourGuideSlot(ctx, &local.TouristAttractions{ID: attractionID}, userID, bookedAt)
+			if err == nil {
+				amounts <- booking.Amount
+			}
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(amounts)
+
+	booked := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, local.ErrFullyBooked):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if booked != guides {
+		t.Errorf("%d bookings made for %d tour guides", booked, guides)
+	}
+
+	for amount := range amounts {
+		if amount != price {
+			t.Errorf("booking amount = %d, want the tour guide price %d", amount, price)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package media
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadMediaRequest is a multipart/form-data upload, the file goes in the photo field
+type UploadMediaRequest struct {
+	Photo       *multipart.FileHeader `json:"-" form:"-"`
+	Caption     string                `form:"caption" validate:"max=300"`
+	AltText     string                `form:"alt_text" validate:"max=300"`
+	Attribution string                `form:"attribution" validate:"max=200"`
+}
+
+type UpdateMediaRequest struct {
+	Caption     *string `json:"caption" validate:"omitempty,max=300"`
+	AltText     *string `json:"alt_text" validate:"omitempty,max=300"`
+	Attribution *string `json:"attribution" validate:"omitempty,max=200"`
+}
+
+// ReorderMediaRequest lists every photo of a listing in its new order
+type ReorderMediaRequest struct {
+	MediaIDs []uuid.UUID `json:"media_ids" validate:"required,min=1,max=20"`
+}
+
+// MediaResponse carries the renditions of a photo keyed by name, empty until status is ready
+type MediaResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	Status      Status             `json:"status"`
+	Variants    map[string]Variant `json:"variants"`
+	Caption     string             `json:"caption"`
+	AltText     string             `json:"alt_text"`
+	Attribution string             `json:"attribution"`
+	Position    int                `json:"position"`
+	IsCover     bool               `json:"is_cover"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func NewMediaResponse(data Media) MediaResponse {
+	variants := map[string]Variant{}
+	if data.Status == StatusReady {
+		variants = data.Variants
+	}
+
+	return MediaResponse{
+		ID:          data.ID,
+		Status:      data.Status,
+		Variants:    variants,
+		Caption:     data.Caption,
+		AltText:     data.AltText,
+		Attribution: data.Attribution,
+		Position:    data.Position,
+		IsCover:     data.IsCover,
+		CreatedAt:   data.CreatedAt,
+	}
+}
+
+// NewMediaResponses builds the gallery view, never nil so an empty gallery is an empty list
+func NewMediaResponses(data []Media) []MediaResponse {
+	response := make([]MediaResponse, 0, len(data))
+	for _, item := range data {
+		response = append(response, NewMediaResponse(item))
+	}
+	return response
+}
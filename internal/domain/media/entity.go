@@ -0,0 +1,103 @@
+package media
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+)
+
+// Variant is one processed rendition of a photo
+type Variant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Variants maps a rendition name to the rendition, stored as a JSONB object
+type Variants map[string]Variant
+
+// Value encodes the variants for a JSONB column
+func (v Variants) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]Variant(v))
+}
+
+// Scan decodes a JSONB column into the variants
+func (v *Variants) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	case nil:
+		*v = Variants{}
+		return nil
+	default:
+		return errors.New("media variants must be a JSON object")
+	}
+
+	variants := Variants{}
+	if err := json.Unmarshal(raw, &variants); err != nil {
+		return err
+	}
+	*v = variants
+	return nil
+}
+
+// Media is a photo in the gallery of exactly one local business or tourist attraction
+type Media struct {
+	ID                  uuid.UUID  `db:"id"`
+	LocalID             *uuid.UUID `db:"local_id"`
+	TouristAttractionID *uuid.UUID `db:"tourist_attraction_id"`
+	ObjectPath          string     `db:"object_path"`
+	URL                 string     `db:"url"`
+	Caption             string     `db:"caption"`
+	AltText             string     `db:"alt_text"`
+	Attribution         string     `db:"attribution"`
+	Position            int        `db:"position"`
+	IsCover             bool       `db:"is_cover"`
+	UploadedBy          *uuid.UUID `db:"uploaded_by"`
+	Status              Status     `db:"status"`
+	Variants            Variants   `db:"variants"`
+	Attempts            int        `db:"attempts"`
+	ProcessingStartedAt *time.Time `db:"processing_started_at"`
+	CreatedAt           time.Time  `db:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at"`
+}
+
+// CoverURL is the URL copied to the listing's photo_url while the photo is the cover,
+// empty until the large rendition exists
+func (m Media) CoverURL() string {
+	return m.Variants[VariantLarge].URL
+}
+
+// Listing identifies the local business or tourist attraction a gallery belongs to
+type Listing struct {
+	Type local.ListingType
+	ID   uuid.UUID
+}
+
+// Listing returns the listing the photo belongs to
+func (m Media) Listing() Listing {
+	if m.LocalID != nil {
+		return Listing{Type: local.ListingLocalBusiness, ID: *m.LocalID}
+	}
+	return Listing{Type: local.ListingTouristAttraction, ID: *m.TouristAttractionID}
+}
+
+// Attach points the photo at listing
+func (m *Media) Attach(listing Listing) {
+	m.LocalID, m.TouristAttractionID = nil, nil
+	if listing.Type == local.ListingLocalBusiness {
+		m.LocalID = &listing.ID
+		return
+	}
+	m.TouristAttractionID = &listing.ID
+}
@@ -0,0 +1,58 @@
+package media
+
+import "time"
+
+// MaxPhotosPerListing bounds the size of a gallery
+const MaxPhotosPerListing = 20
+
+// Status tells how far the background processing of a photo got
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusProcessing Status = "processing"
+	StatusReady      Status = "ready"
+	StatusFailed     Status = "failed"
+)
+
+// Rendition names, original keeps the full resolution
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+	VariantLarge     = "large"
+	VariantOriginal  = "original"
+)
+
+// Rendition is a variant name and the longest side it is scaled down to, 0 keeps the size
+type Rendition struct {
+	Name    string
+	MaxSide int
+}
+
+// Renditions are the jpeg variants generated for every photo
+var Renditions = []Rendition{
+	{Name: VariantThumbnail, MaxSide: 320},
+	{Name: VariantMedium, MaxSide: 800},
+	{Name: VariantLarge, MaxSide: 1600},
+	{Name: VariantOriginal},
+}
+
+const (
+	// RenditionQuality is the jpeg quality of the renditions
+	RenditionQuality = 80
+
+	// UploadQuality is the jpeg quality an upload is re-encoded at to strip its metadata, higher than
+	// RenditionQuality because the renditions are made from it
+	UploadQuality = 90
+)
+
+const (
+	// MaxProcessingAttempts is how often a photo is tried before it is marked failed
+	MaxProcessingAttempts = 3
+
+	// ProcessingTimeout is how long a claimed photo may stay processing before another run picks it up again
+	ProcessingTimeout = 10 * time.Minute
+
+	// ProcessingBatchSize is the most photos a single run of the processing job handles
+	ProcessingBatchSize = 5
+)
@@ -0,0 +1,19 @@
+package media
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+)
+
+var (
+	ErrListingNotFound   = cerr.New(fiber.ErrNotFound.Code, "listing not found", errors.New("listing not found"))
+	ErrMediaNotFound     = cerr.New(fiber.ErrNotFound.Code, "photo not found", errors.New("media not found"))
+	ErrPhotoRequired     = cerr.New(fiber.ErrBadRequest.Code, "a photo file is required", errors.New("photo missing"))
+	ErrInvalidPhoto      = cerr.New(fiber.ErrBadRequest.Code, "photo must be a jpeg, png or webp image of at most 2MB", errors.New("invalid listing photo"))
+	ErrPhotoTooLarge     = cerr.New(fiber.ErrBadRequest.Code, "photo can be at most 40 megapixels", errors.New("listing photo too large"))
+	ErrPhotoUploadFailed = cerr.New(fiber.ErrBadGateway.Code, "failed to upload photo", errors.New("listing photo upload failed"))
+	ErrGalleryFull       = cerr.New(fiber.ErrConflict.Code, "a listing can have at most 20 photos", errors.New("gallery full"))
+	ErrInvalidOrder      = cerr.New(fiber.ErrBadRequest.Code, "media_ids must list every photo of the listing exactly once", errors.New("invalid media order"))
+)
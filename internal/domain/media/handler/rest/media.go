@@ -0,0 +1,183 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// actorFromContext builds the gallery write actor from the authenticated user
+func actorFromContext(ctx *fiber.Ctx) (local.Actor, error) {
+	userIDRaw, _ := ctx.Locals("user_id").(string)
+	userID, err := uuid.Parse(userIDRaw)
+	if err != nil {
+		return local.Actor{}, fiber.ErrUnauthorized
+	}
+
+	role, _ := ctx.Locals("role").(string)
+
+	return local.Actor{
+		UserID:  userID,
+		IsAdmin: user.Role(role) == user.RoleAdmin,
+	}, nil
+}
+
+// listingParam reads the listing of a gallery route, an unparseable ID can't match any listing
+func listingParam(ctx *fiber.Ctx, listingType local.ListingType) (media.Listing, error) {
+	listingID, err := uuid.Parse(ctx.Params("listingID"))
+	if err != nil {
+		return media.Listing{}, media.ErrListingNotFound
+	}
+
+	return media.Listing{Type: listingType, ID: listingID}, nil
+}
+
+func (h *MediaHandler) listMedia(listingType local.ListingType) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		listing, err := listingParam(ctx, listingType)
+		if err != nil {
+			return err
+		}
+
+		response, err := h.service.ListMedia(ctx.Context(), listing)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "get photos successful",
+			"payload": response,
+		})
+	}
+}
+
+// uploadMedia accepts multipart/form-data with the file in photo and optional caption, alt_text and attribution
+func (h *MediaHandler) uploadMedia(listingType local.ListingType) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		listing, err := listingParam(ctx, listingType)
+		if err != nil {
+			return err
+		}
+
+		actor, err := actorFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		var request media.UploadMediaRequest
+		if err := ctx.BodyParser(&request); err != nil {
+			return err
+		}
+
+		if photo, err := ctx.FormFile("photo"); err == nil {
+			request.Photo = photo
+		}
+
+		if err := h.validator.Struct(request); err != nil {
+			return err
+		}
+
+		response, err := h.service.UploadMedia(ctx.Context(), actor, listing, request)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message": "upload photo successful",
+			"payload": response,
+		})
+	}
+}
+
+func (h *MediaHandler) reorderMedia(listingType local.ListingType) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		listing, err := listingParam(ctx, listingType)
+		if err != nil {
+			return err
+		}
+
+		actor, err := actorFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		var request media.ReorderMediaRequest
+		if err := ctx.BodyParser(&request); err != nil {
+			return err
+		}
+
+		if err := h.validator.Struct(request); err != nil {
+			return err
+		}
+
+		response, err := h.service.ReorderMedia(ctx.Context(), actor, listing, request)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message": "reorder photos successful",
+			"payload": response,
+		})
+	}
+}
+
+func (h *MediaHandler) updateMedia(ctx *fiber.Ctx) error {
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var request media.UpdateMediaRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateMedia(ctx.Context(), actor, ctx.Params("mediaID"), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "update photo successful",
+		"payload": response,
+	})
+}
+
+func (h *MediaHandler) setCover(ctx *fiber.Ctx) error {
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.service.SetCover(ctx.Context(), actor, ctx.Params("mediaID"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "set cover photo successful",
+		"payload": response,
+	})
+}
+
+func (h *MediaHandler) deleteMedia(ctx *fiber.Ctx) error {
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteMedia(ctx.Context(), actor, ctx.Params("mediaID")); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "delete photo successful",
+	})
+}
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/media/service"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MediaHandler struct {
+	service    service.MediaServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+func New(service service.MediaServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *MediaHandler {
+	return &MediaHandler{service: service, validator: validator, middleware: middleware}
+}
+
+func (h *MediaHandler) Mount(router fiber.Router) {
+	// Galleries are read with the catalogue and managed by the listing owner or an admin
+	readCatalogue := h.middleware.AuthenticationOrAPIKey(apikey.ScopeReadCatalogue)
+	authentication := h.middleware.Authentication()
+	canManageListings := h.middleware.RequireRole(user.RoleMerchant, user.RoleAdmin)
+
+	for prefix, listingType := range map[string]local.ListingType{
+		"/locals":              local.ListingLocalBusiness,
+		"/tourist-attractions": local.ListingTouristAttraction,
+	} {
+		galleryGroup := router.Group(prefix + "/:listingID/media")
+		galleryGroup.Get("/", readCatalogue, h.listMedia(listingType))
+		galleryGroup.Post("/", authentication, canManageListings, h.uploadMedia(listingType))
+		galleryGroup.Put("/order", authentication, canManageListings, h.reorderMedia(listingType))
+	}
+
+	mediaGroup := router.Group("/media")
+	mediaGroup.Patch("/:mediaID", authentication, canManageListings, h.updateMedia)
+	mediaGroup.Post("/:mediaID/cover", authentication, canManageListings, h.setCover)
+	mediaGroup.Delete("/:mediaID", authentication, canManageListings, h.deleteMedia)
+}
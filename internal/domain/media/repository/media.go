@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const mediaColumns = `id, local_id, tourist_attraction_id, object_path, url, caption, alt_text, attribution, position, is_cover, uploaded_by, status, variants, attempts, processing_started_at, created_at, updated_at`
+
+// listingTables maps a listing type to its table and the media column pointing at it
+var listingTables = map[local.ListingType]struct{ table, column string }{
+	local.ListingLocalBusiness:     {"locals", "local_id"},
+	local.ListingTouristAttraction: {"tourist_attractions", "tourist_attraction_id"},
+}
+
+func listingTable(listing media.Listing) (string, string, error) {
+	target, ok := listingTables[listing.Type]
+	if !ok {
+		return "", "", fmt.Errorf("unknown listing type %q", listing.Type)
+	}
+	return target.table, target.column, nil
+}
+
+// GetListingOwner reads who owns the listing, inside a transaction it also locks the listing row
+// so concurrent gallery changes of one listing run one after another
+func (r *mediaRepository) GetListingOwner(ctx context.Context, listing media.Listing, ownerID **uuid.UUID) error {
+	table, _, err := listingTable(listing)
+	if err != nil {
+		return err
+	}
+
+	query := `SELECT owner_id FROM ` + table + ` WHERE id = $1`
+	if _, ok := r.q.(*sqlx.Tx); ok {
+		query += ` FOR UPDATE`
+	}
+	if err := r.q.QueryRowxContext(ctx, query, listing.ID).Scan(ownerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return media.ErrListingNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetMediaByListing lists the gallery of a listing in display order
+func (r *mediaRepository) GetMediaByListing(ctx context.Context, listing media.Listing, out *[]media.Media) error {
+	_, column, err := listingTable(listing)
+	if err != nil {
+		return err
+	}
+
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE ` + column + ` = $1 ORDER BY position, created_at`
+
+	rows, err := r.q.QueryxContext(ctx, query, listing.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	result := []media.Media{}
+	for rows.Next() {
+		var item media.Media
+		if err := rows.StructScan(&item); err != nil {
+			return err
+		}
+
+		result = append(result, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	*out = result
+	return nil
+}
+
+func (r *mediaRepository) GetMediaByID(ctx context.Context, data *media.Media) error {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+
+	row := r.q.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return media.ErrMediaNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *mediaRepository) CreateMedia(ctx context.Context, data media.Media) error {
+	query := `INSERT INTO media (
+		id, local_id, tourist_attraction_id, object_path, url, caption, alt_text, attribution, position, is_cover, uploaded_by, status, variants, created_at, updated_at
+	) VALUES (
+		:id, :local_id, :tourist_attraction_id, :object_path, :url, :caption, :alt_text, :attribution, :position, :is_cover, :uploaded_by, :status, :variants, :created_at, :updated_at
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	return err
+}
+
+// UpdateMedia saves the caption, alt text and attribution of a photo
+func (r *mediaRepository) UpdateMedia(ctx context.Context, data *media.Media) error {
+	query := `UPDATE media SET
+		caption = :caption,
+		alt_text = :alt_text,
+		attribution = :attribution,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	result, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return media.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+func (r *mediaRepository) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	result, err := r.q.ExecContext(ctx, `DELETE FROM media WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return media.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// ReorderMedia numbers the listing's photos by their index in ids, starting from zero
+func (r *mediaRepository) ReorderMedia(ctx context.Context, listing media.Listing, ids []uuid.UUID) error {
+	_, column, err := listingTable(listing)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE media m SET position = o.ordinality - 1, updated_at = NOW()
+		FROM unnest(CAST($2 AS UUID[])) WITH ORDINALITY AS o(id, ordinality)
+		WHERE m.id = o.id AND m.` + column + ` = $1`
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	_, err = r.q.ExecContext(ctx, query, listing.ID, pq.Array(values))
+	return err
+}
+
+// SetCover makes data the only cover of the listing and copies its cover URL to the listing's photo_url,
+// photo_url is left alone while the photo has no cover URL yet. An empty data.ID clears the cover and
+// blanks photo_url if it still points at data.URL.
+func (r *mediaRepository) SetCover(ctx context.Context, listing media.Listing, data media.Media) error {
+	table, column, err := listingTable(listing)
+	if err != nil {
+		return err
+	}
+
+	// Clear the old cover first, the unique cover index is checked row by row
+	demote := `UPDATE media SET is_cover = FALSE, updated_at = NOW() WHERE ` + column + ` = $1 AND is_cover AND id <> $2`
+	if _, err := r.q.ExecContext(ctx, demote, listing.ID, data.ID); err != nil {
+		return err
+	}
+
+	if data.ID == uuid.Nil {
+		query := `UPDATE ` + table + ` SET photo_url = '', updated_at = NOW() WHERE id = $1 AND photo_url = $2`
+		_, err := r.q.ExecContext(ctx, query, listing.ID, data.URL)
+		return err
+	}
+
+	cover := `UPDATE media SET is_cover = TRUE, updated_at = NOW() WHERE id = $1 AND NOT is_cover`
+	if _, err := r.q.ExecContext(ctx, cover, data.ID); err != nil {
+		return err
+	}
+
+	if data.CoverURL() == "" {
+		return nil
+	}
+
+	query := `UPDATE ` + table + ` SET photo_url = $2, updated_at = NOW() WHERE id = $1`
+	_, err = r.q.ExecContext(ctx, query, listing.ID, data.CoverURL())
+	return err
+}
+
+// ClaimMediaForProcessing marks the oldest photo waiting for processing, or one whose processing stalled
+// since staleBefore, as processing and loads it into out. It reports false when there is nothing to do.
+func (r *mediaRepository) ClaimMediaForProcessing(ctx context.Context, staleBefore time.Time, out *media.Media) (bool, error) {
+	query := `UPDATE media SET
+		status = 'processing',
+		attempts = attempts + 1,
+		processing_started_at = NOW()
+	WHERE id = (
+		SELECT id FROM media
+		WHERE status = 'pending' OR (status = 'processing' AND processing_started_at < $1)
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + mediaColumns
+
+	if err := r.q.QueryRowxContext(ctx, query, staleBefore).StructScan(out); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CompleteProcessing points a processing photo at its renditions and marks it ready
+func (r *mediaRepository) CompleteProcessing(ctx context.Context, data *media.Media) error {
+	query := `UPDATE media SET
+		object_path = :object_path,
+		url = :url,
+		variants = :variants,
+		status = 'ready',
+		processing_started_at = NULL,
+		updated_at = :updated_at
+	WHERE id = :id AND status = 'processing'`
+
+	result, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return media.ErrMediaNotFound
+	}
+
+	return nil
+}
+
+// FailProcessing marks a processing photo failed and forgets its uploaded file, which the caller deletes,
+// photo_url is blanked if it still points at that file. It returns ErrMediaNotFound when the photo
+// isn't processing anymore.
+func (r *mediaRepository) FailProcessing(ctx context.Context, listing media.Listing, data media.Media) error {
+	table, _, err := listingTable(listing)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE media SET
+		status = 'failed',
+		object_path = '',
+		url = '',
+		processing_started_at = NULL,
+		updated_at = NOW()
+	WHERE id = $1 AND status = 'processing'`
+	result, err := r.q.ExecContext(ctx, query, data.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return media.ErrMediaNotFound
+	}
+
+	if data.URL == "" {
+		return nil
+	}
+
+	query = `UPDATE ` + table + ` SET photo_url = '', updated_at = NOW() WHERE id = $1 AND photo_url = $2`
+	_, err = r.q.ExecContext(ctx, query, listing.ID, data.URL)
+	return err
+}
+
+// ReleaseMedia hands a processing photo back as pending for a retry
+func (r *mediaRepository) ReleaseMedia(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE media SET status = 'pending', processing_started_at = NULL, updated_at = NOW() WHERE id = $1 AND status = 'processing'`
+
+	_, err := r.q.ExecContext(ctx, query, id)
+	return err
+}
+
+// UnclaimMedia hands a processing photo back as pending without counting the attempt, for processing
+// that was interrupted rather than failed
+func (r *mediaRepository) UnclaimMedia(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE media SET
+		status = 'pending',
+		attempts = GREATEST(attempts - 1, 0),
+		processing_started_at = NULL,
+		updated_at = NOW()
+	WHERE id = $1 AND status = 'processing'`
+
+	_, err := r.q.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	errFailedToCommit   = errors.New("FAILED_TO_COMMIT_TRANSACTION")
+	errFailedToRollback = errors.New("FAILED_TO_ROLLBACK_TRANSACTION")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+type RepositoryItf interface {
+	NewClient(tx bool) (mediaRepositoryItf, error)
+}
+
+type mediaRepository struct {
+	q namedExt
+}
+
+type mediaRepositoryItf interface {
+	Commit() error
+	Rollback() error
+	AuditTx() audit.Tx
+	GetListingOwner(ctx context.Context, listing media.Listing, ownerID **uuid.UUID) error
+	GetMediaByListing(ctx context.Context, listing media.Listing, out *[]media.Media) error
+	GetMediaByID(ctx context.Context, data *media.Media) error
+	CreateMedia(ctx context.Context, data media.Media) error
+	UpdateMedia(ctx context.Context, data *media.Media) error
+	DeleteMedia(ctx context.Context, id uuid.UUID) error
+	ReorderMedia(ctx context.Context, listing media.Listing, ids []uuid.UUID) error
+	SetCover(ctx context.Context, listing media.Listing, data media.Media) error
+	ClaimMediaForProcessing(ctx context.Context, staleBefore time.Time, out *media.Media) (bool, error)
+	CompleteProcessing(ctx context.Context, data *media.Media) error
+	FailProcessing(ctx context.Context, listing media.Listing, data media.Media) error
+	ReleaseMedia(ctx context.Context, id uuid.UUID) error
+	UnclaimMedia(ctx context.Context, id uuid.UUID) error
+}
+
+type namedExt interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+func New(db *sqlx.DB) RepositoryItf {
+	return &repository{db}
+}
+
+func (r *repository) NewClient(tx bool) (mediaRepositoryItf, error) {
+	var db namedExt
+
+	db = r.DB
+	if tx {
+		var err error
+		db, err = r.DB.Beginx()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &mediaRepository{db}, nil
+}
+
+func (r *mediaRepository) Commit() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Commit()
+	}
+
+	return errFailedToCommit
+}
+
+func (r *mediaRepository) Rollback() error {
+	if tx, ok := r.q.(*sqlx.Tx); ok {
+		return tx.Rollback()
+	}
+
+	return errFailedToRollback
+}
+
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (r *mediaRepository) AuditTx() audit.Tx {
+	return r.q
+}
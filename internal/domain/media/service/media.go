@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/vistara-studio/vistara-be/pkg/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// galleryWriter is the part of a repository client the write checks need
+type galleryWriter interface {
+	GetListingOwner(ctx context.Context, listing media.Listing, ownerID **uuid.UUID) error
+	GetMediaByID(ctx context.Context, data *media.Media) error
+}
+
+// lockGallery checks the actor may change the listing's gallery, on a transaction client it also locks
+// the listing row until the transaction ends
+func lockGallery(ctx context.Context, client galleryWriter, actor local.Actor, listing media.Listing) error {
+	var ownerID *uuid.UUID
+	if err := client.GetListingOwner(ctx, listing, &ownerID); err != nil {
+		return err
+	}
+
+	return authorizeGalleryWrite(actor, ownerID)
+}
+
+// loadForWrite loads a photo and locks the gallery it belongs to
+func loadForWrite(ctx context.Context, client galleryWriter, actor local.Actor, mediaID string) (*media.Media, error) {
+	id, err := uuid.Parse(mediaID)
+	if err != nil {
+		return nil, media.ErrMediaNotFound
+	}
+
+	data := &media.Media{ID: id}
+	if err := client.GetMediaByID(ctx, data); err != nil {
+		return nil, err
+	}
+
+	if err := lockGallery(ctx, client, actor, data.Listing()); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *mediaService) ListMedia(ctx context.Context, listing media.Listing) ([]media.MediaResponse, error) {
+	mediaRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner *uuid.UUID
+	if err := mediaRepository.GetListingOwner(ctx, listing, &owner); err != nil {
+		return nil, err
+	}
+
+	var gallery []media.Media
+	if err := mediaRepository.GetMediaByListing(ctx, listing, &gallery); err != nil {
+		return nil, err
+	}
+
+	return media.NewMediaResponses(gallery), nil
+}
+
+// UploadMedia stores a photo at the end of the listing's gallery, the first photo of a gallery becomes its cover.
+// The photo is re-encoded without its metadata before it is stored, the renditions are made later by
+// ProcessPendingMedia so the upload returns quickly.
+func (s *mediaService) UploadMedia(ctx context.Context, actor local.Actor, listing media.Listing, request media.UploadMediaRequest) (response media.MediaResponse, err error) {
+	if request.Photo == nil {
+		return media.MediaResponse{}, media.ErrPhotoRequired
+	}
+
+	if err := util.ValidateFile(request.Photo); err != nil {
+		return media.MediaResponse{}, media.ErrInvalidPhoto
+	}
+
+	// The bucket is public, the file must not carry the EXIF and GPS data of the camera for even a moment
+	photo, err := stripMetadata(request.Photo)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	// Check before uploading so a refused request doesn't leave an object behind. This client has no
+	// transaction so nothing is locked yet, the checks are repeated under the lock below.
+	precheck, err := s.repository.NewClient(false)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if err := lockGallery(ctx, precheck, actor, listing); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	var gallery []media.Media
+	if err := precheck.GetMediaByListing(ctx, listing, &gallery); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if len(gallery) >= media.MaxPhotosPerListing {
+		return media.MediaResponse{}, media.ErrGalleryFull
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	objectPath := storage.ListingPhotoUploadPath(string(listing.Type), listing.ID, id)
+	url, err := s.objects.Put(ctx, objectPath, "image/jpeg", photo)
+	if err != nil {
+		return media.MediaResponse{}, media.ErrPhotoUploadFailed
+	}
+
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		s.removeObject(ctx, objectPath)
+		return media.MediaResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+			s.removeObject(ctx, objectPath)
+		}
+	}()
+
+	if err = lockGallery(ctx, mediaRepository, actor, listing); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	// Read the gallery again under the lock, another upload may have landed in between
+	if err = mediaRepository.GetMediaByListing(ctx, listing, &gallery); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if len(gallery) >= media.MaxPhotosPerListing {
+		err = media.ErrGalleryFull
+		return media.MediaResponse{}, err
+	}
+
+	position := 0
+	if len(gallery) > 0 {
+		position = gallery[len(gallery)-1].Position + 1
+	}
+
+	now := time.Now()
+	data := media.Media{
+		ID:          id,
+		ObjectPath:  objectPath,
+		URL:         url,
+		Caption:     request.Caption,
+		AltText:     request.AltText,
+		Attribution: request.Attribution,
+		Position:    position,
+		UploadedBy:  &actor.UserID,
+		Status:      media.StatusPending,
+		Variants:    media.Variants{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	data.Attach(listing)
+
+	if err = mediaRepository.CreateMedia(ctx, data); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if len(gallery) == 0 {
+		if err = mediaRepository.SetCover(ctx, listing, data); err != nil {
+			return media.MediaResponse{}, err
+		}
+		data.IsCover = true
+	}
+
+	err = s.audit.Record(ctx, mediaRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionMediaUploaded,
+		TargetType: audit.TargetMedia,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(nil, data),
+	})
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	return media.NewMediaResponse(data), nil
+}
+
+// ReorderMedia renumbers a gallery in the order of request.MediaIDs, which must name every photo once
+func (s *mediaService) ReorderMedia(ctx context.Context, actor local.Actor, listing media.Listing, request media.ReorderMediaRequest) (response []media.MediaResponse, err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	if err = lockGallery(ctx, mediaRepository, actor, listing); err != nil {
+		return nil, err
+	}
+
+	var gallery []media.Media
+	if err = mediaRepository.GetMediaByListing(ctx, listing, &gallery); err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uuid.UUID]bool, len(gallery))
+	for _, item := range gallery {
+		remaining[item.ID] = true
+	}
+	for _, id := range request.MediaIDs {
+		if !remaining[id] {
+			err = media.ErrInvalidOrder
+			return nil, err
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		err = media.ErrInvalidOrder
+		return nil, err
+	}
+
+	if err = mediaRepository.ReorderMedia(ctx, listing, request.MediaIDs); err != nil {
+		return nil, err
+	}
+
+	if err = mediaRepository.GetMediaByListing(ctx, listing, &gallery); err != nil {
+		return nil, err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return nil, err
+	}
+
+	return media.NewMediaResponses(gallery), nil
+}
+
+// UpdateMedia changes the caption, alt text or attribution of a photo
+func (s *mediaService) UpdateMedia(ctx context.Context, actor local.Actor, mediaID string, request media.UpdateMediaRequest) (response media.MediaResponse, err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	data, err := loadForWrite(ctx, mediaRepository, actor, mediaID)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	before := *data
+
+	if request.Caption != nil {
+		data.Caption = *request.Caption
+	}
+	if request.AltText != nil {
+		data.AltText = *request.AltText
+	}
+	if request.Attribution != nil {
+		data.Attribution = *request.Attribution
+	}
+	data.UpdatedAt = time.Now()
+
+	if err = mediaRepository.UpdateMedia(ctx, data); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, mediaRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionMediaUpdated,
+		TargetType: audit.TargetMedia,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(before, data),
+	})
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	return media.NewMediaResponse(*data), nil
+}
+
+// SetCover makes a photo the cover of its gallery and the listing's photo_url
+func (s *mediaService) SetCover(ctx context.Context, actor local.Actor, mediaID string) (response media.MediaResponse, err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	data, err := loadForWrite(ctx, mediaRepository, actor, mediaID)
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	before := *data
+
+	if err = mediaRepository.SetCover(ctx, data.Listing(), *data); err != nil {
+		return media.MediaResponse{}, err
+	}
+	data.IsCover = true
+
+	err = s.audit.Record(ctx, mediaRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionMediaUpdated,
+		TargetType: audit.TargetMedia,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(before, data),
+	})
+	if err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return media.MediaResponse{}, err
+	}
+
+	return media.NewMediaResponse(*data), nil
+}
+
+// DeleteMedia removes a photo, its stored file and renditions, when it was the cover the next photo in the gallery takes over.
+// The files are removed after the commit, a failure is only logged.
+func (s *mediaService) DeleteMedia(ctx context.Context, actor local.Actor, mediaID string) (err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	data, err := loadForWrite(ctx, mediaRepository, actor, mediaID)
+	if err != nil {
+		return err
+	}
+
+	if err = mediaRepository.DeleteMedia(ctx, data.ID); err != nil {
+		return err
+	}
+
+	listing := data.Listing()
+	if data.IsCover {
+		// Blank photo_url while it still shows the deleted photo, the next photo only replaces it
+		// once that one has a large rendition
+		if err = mediaRepository.SetCover(ctx, listing, media.Media{URL: data.CoverURL()}); err != nil {
+			return err
+		}
+
+		var gallery []media.Media
+		if err = mediaRepository.GetMediaByListing(ctx, listing, &gallery); err != nil {
+			return err
+		}
+
+		if len(gallery) > 0 {
+			if err = mediaRepository.SetCover(ctx, listing, gallery[0]); err != nil {
+				return err
+			}
+		}
+	}
+
+	err = s.audit.Record(ctx, mediaRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(actor.UserID.String()),
+		Action:     audit.ActionMediaDeleted,
+		TargetType: audit.TargetMedia,
+		TargetID:   data.ID.String(),
+		Changes:    audit.Diff(*data, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return err
+	}
+
+	// Remove the files only once the row is gone, a failure then leaves unreferenced objects behind
+	// instead of a photo pointing at a deleted file. The renditions are found by URL, older ones were WebP.
+	if data.ObjectPath != "" {
+		s.removeObject(ctx, data.ObjectPath)
+	}
+	urls := make([]string, 0, len(data.Variants))
+	for _, variant := range data.Variants {
+		urls = append(urls, variant.URL)
+	}
+	if err := s.remover.RemoveURLs(ctx, urls...); err != nil {
+		log.Warn().Err(err).Strs("urls", urls).Msg("failed to remove orphaned listing photo")
+	}
+
+	return nil
+}
+
+// removeObject cleans up stored files no photo references anymore
+func (s *mediaService) removeObject(ctx context.Context, objectPaths ...string) {
+	if err := s.remover.Remove(ctx, objectPaths...); err != nil {
+		log.Warn().Err(err).Strs("object_paths", objectPaths).Msg("failed to remove orphaned listing photo")
+	}
+}
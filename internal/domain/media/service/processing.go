@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/vistara-studio/vistara-be/pkg/imaging"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// errUnreadableImage is a permanent failure, retrying the same file won't help
+var errUnreadableImage = errors.New("uploaded photo can't be decoded")
+
+// ProcessPendingMedia turns uploaded photos into upright jpeg renditions without metadata, it runs as a background job
+func (s *mediaService) ProcessPendingMedia(ctx context.Context) error {
+	for i := 0; i < media.ProcessingBatchSize; i++ {
+		// Stop claiming photos once the scheduler shuts down
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		mediaRepository, err := s.repository.NewClient(false)
+		if err != nil {
+			return err
+		}
+
+		var data media.Media
+		found, err := mediaRepository.ClaimMediaForProcessing(ctx, time.Now().Add(-media.ProcessingTimeout), &data)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return nil
+		}
+
+		// The claimed photo is handed back even when ctx is cancelled mid-way, or it would stay
+		// processing until ProcessingTimeout
+		release := context.WithoutCancel(ctx)
+
+		if data.Attempts > media.MaxProcessingAttempts {
+			if err := s.failProcessing(release, data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = s.processMedia(ctx, data)
+		if err == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			// Interrupted by the shutdown, that says nothing about the photo
+			return mediaRepository.UnclaimMedia(release, data.ID)
+		}
+
+		log.Error().Err(err).Str("media_id", data.ID.String()).Int("attempt", data.Attempts).Msg("failed to process listing photo")
+
+		if errors.Is(err, errUnreadableImage) || data.Attempts >= media.MaxProcessingAttempts {
+			err = s.failProcessing(release, data)
+		} else {
+			err = mediaRepository.ReleaseMedia(release, data.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processMedia stores the renditions of a claimed photo next to the uploaded file, then swaps the photo over to them
+// and removes the upload
+func (s *mediaService) processMedia(ctx context.Context, data media.Media) (err error) {
+	raw, err := s.objects.Download(ctx, data.ObjectPath)
+	if err != nil {
+		return err
+	}
+
+	// Decode checks the dimensions before decoding, an image above MaxPixels is failed right away
+	img, err := imaging.Decode(raw)
+	if err != nil {
+		return errors.Join(errUnreadableImage, err)
+	}
+
+	listing := data.Listing()
+	variants := media.Variants{}
+	var stored []string
+
+	// Files are cleaned up even when ctx is cancelled
+	cleanup := context.WithoutCancel(ctx)
+	defer func() {
+		if err != nil {
+			s.removeObject(cleanup, stored...)
+		}
+	}()
+
+	for _, rendition := range media.Renditions {
+		scaled := img
+		if rendition.MaxSide > 0 {
+			scaled = imaging.Fit(img, rendition.MaxSide)
+		}
+
+		encoded, encodeErr := imaging.EncodeJPEG(scaled, media.RenditionQuality)
+		if encodeErr != nil {
+			err = encodeErr
+			return err
+		}
+
+		path := storage.ListingPhotoVariantPath(string(listing.Type), listing.ID, data.ID, rendition.Name)
+		url, putErr := s.objects.Put(ctx, path, "image/jpeg", encoded)
+		if putErr != nil {
+			err = putErr
+			return err
+		}
+		stored = append(stored, path)
+
+		bounds := scaled.Bounds()
+		variants[rendition.Name] = media.Variant{URL: url, Width: bounds.Dx(), Height: bounds.Dy()}
+	}
+
+	uploadPath := data.ObjectPath
+	data.ObjectPath = storage.ListingPhotoVariantPath(string(listing.Type), listing.ID, data.ID, media.VariantOriginal)
+	data.URL = variants[media.VariantOriginal].URL
+	data.Variants = variants
+	data.UpdatedAt = time.Now()
+
+	deleted, err := s.completeProcessing(ctx, listing, &data)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		// The photo was deleted while it was processed, nothing references the renditions
+		s.removeObject(cleanup, stored...)
+		return nil
+	}
+
+	s.removeObject(cleanup, uploadPath)
+	return nil
+}
+
+// failProcessing gives up on a claimed photo. Its uploaded file is deleted so a photo without renditions
+// isn't served, and photo_url is blanked if it still points at that file.
+func (s *mediaService) failProcessing(ctx context.Context, data media.Media) (err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	// Lock the gallery first like every other gallery write
+	listing := data.Listing()
+	var ownerID *uuid.UUID
+	if err = mediaRepository.GetListingOwner(ctx, listing, &ownerID); err != nil {
+		if errors.Is(err, media.ErrListingNotFound) {
+			// Deleting the listing removed its files already
+			err = nil
+			_ = mediaRepository.Rollback()
+			return nil
+		}
+		return err
+	}
+
+	if err = mediaRepository.FailProcessing(ctx, listing, data); err != nil {
+		if errors.Is(err, media.ErrMediaNotFound) {
+			// Deleted, or claimed again by another run, the file isn't ours to remove
+			err = nil
+			_ = mediaRepository.Rollback()
+			return nil
+		}
+		return err
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return err
+	}
+
+	if data.ObjectPath != "" {
+		s.removeObject(ctx, data.ObjectPath)
+	}
+	return nil
+}
+
+// stripMetadata re-encodes an uploaded photo upright and without metadata, refusing images above imaging.MaxPixels
+func stripMetadata(photo *multipart.FileHeader) ([]byte, error) {
+	file, err := photo.Open()
+	if err != nil {
+		return nil, media.ErrInvalidPhoto
+	}
+	defer file.Close()
+
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		return nil, media.ErrInvalidPhoto
+	}
+
+	img, err := imaging.Decode(raw)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, media.ErrPhotoTooLarge
+		}
+		return nil, media.ErrInvalidPhoto
+	}
+
+	return imaging.EncodeJPEG(img, media.UploadQuality)
+}
+
+// completeProcessing saves the renditions and refreshes the listing's photo_url when the photo is the cover,
+// it reports true when the photo no longer exists
+func (s *mediaService) completeProcessing(ctx context.Context, listing media.Listing, data *media.Media) (deleted bool, err error) {
+	mediaRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = mediaRepository.Rollback()
+		}
+	}()
+
+	// Lock the gallery first like every other gallery write
+	var ownerID *uuid.UUID
+	if err = mediaRepository.GetListingOwner(ctx, listing, &ownerID); err != nil {
+		if errors.Is(err, media.ErrListingNotFound) {
+			err = nil
+			_ = mediaRepository.Rollback()
+			return true, nil
+		}
+		return false, err
+	}
+
+	current := media.Media{ID: data.ID}
+	if err = mediaRepository.GetMediaByID(ctx, &current); err != nil {
+		if errors.Is(err, media.ErrMediaNotFound) {
+			err = nil
+			_ = mediaRepository.Rollback()
+			return true, nil
+		}
+		return false, err
+	}
+
+	if err = mediaRepository.CompleteProcessing(ctx, data); err != nil {
+		return false, err
+	}
+
+	if current.IsCover {
+		data.IsCover = true
+		if err = mediaRepository.SetCover(ctx, listing, *data); err != nil {
+			return false, err
+		}
+	}
+
+	if err = mediaRepository.Commit(); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
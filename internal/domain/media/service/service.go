@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/local"
+	"github.com/vistara-studio/vistara-be/internal/domain/media"
+	mediaRepository "github.com/vistara-studio/vistara-be/internal/domain/media/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/google/uuid"
+)
+
+type mediaService struct {
+	repository mediaRepository.RepositoryItf
+	objects    storage.Objects
+	remover    storage.Remover
+	audit      audit.Recorder
+}
+
+type MediaServiceItf interface {
+	ListMedia(ctx context.Context, listing media.Listing) ([]media.MediaResponse, error)
+	UploadMedia(ctx context.Context, actor local.Actor, listing media.Listing, request media.UploadMediaRequest) (media.MediaResponse, error)
+	ReorderMedia(ctx context.Context, actor local.Actor, listing media.Listing, request media.ReorderMediaRequest) ([]media.MediaResponse, error)
+	UpdateMedia(ctx context.Context, actor local.Actor, mediaID string, request media.UpdateMediaRequest) (media.MediaResponse, error)
+	SetCover(ctx context.Context, actor local.Actor, mediaID string) (media.MediaResponse, error)
+	DeleteMedia(ctx context.Context, actor local.Actor, mediaID string) error
+	ProcessPendingMedia(ctx context.Context) error
+}
+
+func New(repository mediaRepository.RepositoryItf, objects storage.Objects, remover storage.Remover, recorder audit.Recorder) MediaServiceItf {
+	return &mediaService{repository: repository, objects: objects, remover: remover, audit: recorder}
+}
+
+// authorizeGalleryWrite lets admins change any gallery and everyone else only the galleries of listings they own
+func authorizeGalleryWrite(actor local.Actor, ownerID *uuid.UUID) error {
+	if actor.IsAdmin {
+		return nil
+	}
+
+	if ownerID == nil || *ownerID != actor.UserID {
+		return local.ErrNotListingOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+)
+
+// HandleMidtransNotification handles the HTTP notification Midtrans sends when a payment changes state
+func (h *PaymentHandler) HandleMidtransNotification(ctx *fiber.Ctx) error {
+	var request payment.Notification
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": "Failed to parse notification body",
+		})
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"message": err.Error(),
+		})
+	}
+
+	if err := h.dispatcher.Dispatch(ctx.Context(), request); err != nil {
+		var customErr *cerr.CustomError
+		if errors.As(err, &customErr) {
+			return ctx.Status(customErr.Code).JSON(fiber.Map{
+				"error":   customErr.Message,
+				"message": "Failed to process payment notification",
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to process payment notification",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "payment notification processed",
+	})
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+)
+
+// PaymentHandler receives payment gateway callbacks and forwards them to the domain that owns the order
+type PaymentHandler struct {
+	dispatcher *payment.Dispatcher
+	validator  *validator.Validate
+}
+
+// New creates a new PaymentHandler instance
+func New(dispatcher *payment.Dispatcher, validator *validator.Validate) *PaymentHandler {
+	return &PaymentHandler{
+		dispatcher: dispatcher,
+		validator:  validator,
+	}
+}
+
+// Mount registers all payment routes
+func (h *PaymentHandler) Mount(router fiber.Router) {
+	// Payment gateway callbacks - public, authenticity is checked through the notification signature
+	paymentGroup := router.Group("/payments")
+	paymentGroup.Post("/midtrans/notification", h.HandleMidtransNotification)
+}
@@ -1,18 +1,107 @@
 package session
 
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type RegisterRequest struct {
-	FullName        string `json:"full_name" validate:"required"`
-	Email           string `json:"email" validate:"required,email"`
-	Password        string `json:"password" validate:"required"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
+	FullName        string                `json:"full_name" form:"full_name" validate:"required"`
+	Email           string                `json:"email" form:"email" validate:"required,email"`
+	Password        string                `json:"password" form:"password" validate:"required"`
+	ConfirmPassword string                `json:"confirm_password" form:"confirm_password" validate:"required,eqfield=Password"`
+	Photo           *multipart.FileHeader `json:"-" form:"-"`
+	UserAgent       string                `json:"-" form:"-"`
+	IPAddress       string                `json:"-" form:"-"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type GoogleLoginRequest struct {
+	IDToken   string `json:"id_token" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type GoogleLinkRequest struct {
+	IDToken   string `json:"id_token" validate:"required"`
+	Password  string `json:"password" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=Password"`
 }
 
+// LoginResponse carries either the tokens or, for accounts with two-factor authentication, the challenge to complete
 type LoginResponse struct {
-	AccessToken  string `json:"token"`
+	AccessToken  string `json:"token,omitempty"`
 	RefreshToken string `json:"-"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// MFAVerifyRequest completes a login with either a TOTP code or a recovery code
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
+}
+
+// MFACodeRequest confirms an MFA change with a TOTP code, or a recovery code where allowed
+type MFACodeRequest struct {
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
+}
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFAEnableResponse struct {
+	AccessToken   string   `json:"token"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string
+	UserAgent    string
+	IPAddress    string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	SignedInAt time.Time `json:"signed_in_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
 }
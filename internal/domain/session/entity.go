@@ -3,11 +3,108 @@ package session
 import (
 	"time"
 
+	"github.com/vistara-studio/vistara-be/pkg/secretbox"
 	"github.com/google/uuid"
 )
 
+// RefreshTokenTTL is how long a refresh token stays valid without being used
+const RefreshTokenTTL = 7 * 24 * time.Hour
+
+const (
+	// MFAChallengeTTL is how long the second login step can be completed after the password step
+	MFAChallengeTTL = 5 * time.Minute
+
+	// MaxMFAChallengeAttempts is how many wrong codes a challenge accepts before it is burned
+	MaxMFAChallengeAttempts = 5
+
+	// RecoveryCodeCount is how many one-time recovery codes are handed out at once
+	RecoveryCodeCount = 10
+)
+
 type Table struct {
-	ID        uuid.UUID `db:"id"`
-	UserID    uuid.UUID `db:"user_id"`
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	FamilyID   uuid.UUID  `db:"family_id"`
+	TokenHash  string     `db:"token_hash"`
+	ReplacedBy *uuid.UUID `db:"replaced_by"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	UserAgent  string     `db:"user_agent"`
+	IPAddress  string     `db:"ip_address"`
+	MFA        bool       `db:"mfa_authenticated"`
+	LastUsedAt time.Time  `db:"last_used_at"`
+	SignedInAt time.Time  `db:"signed_in_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// LockoutScope is what a login lockout applies to
+type LockoutScope string
+
+const (
+	LockoutScopeAccount LockoutScope = "account"
+	LockoutScopeIP      LockoutScope = "ip"
+)
+
+// LoginThrottle limits failed password logins per account and per IP address
+type LoginThrottle struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	Window             time.Duration
+	LockoutDuration    time.Duration
+}
+
+// LoginAttempt is one password login attempt, the email is stored lowercased
+type LoginAttempt struct {
+	ID        int64     `db:"id"`
+	Email     string    `db:"email"`
+	IPAddress string    `db:"ip_address"`
+	Succeeded bool      `db:"succeeded"`
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// LoginFailures summarises recent failed attempts for one account or IP address
+type LoginFailures struct {
+	Count        int        `db:"failures"`
+	LastFailedAt *time.Time `db:"last_failed_at"`
+}
+
+// Lockout records that an account or IP address was temporarily blocked from logging in
+type Lockout struct {
+	ID             uuid.UUID    `db:"id"`
+	Scope          LockoutScope `db:"scope"`
+	Subject        string       `db:"subject"`
+	UserID         *uuid.UUID   `db:"user_id"`
+	FailedAttempts int          `db:"failed_attempts"`
+	LockedUntil    time.Time    `db:"locked_until"`
+	IPAddress      string       `db:"ip_address"`
+	UserAgent      string       `db:"user_agent"`
+	CreatedAt      time.Time    `db:"created_at"`
+}
+
+// MFAConfig holds the box TOTP secrets are encrypted with and the issuer shown in authenticator apps
+type MFAConfig struct {
+	Secrets *secretbox.Box
+	Issuer  string
+}
+
+// MFA is a user's TOTP enrolment, pending until EnabledAt is set
+type MFA struct {
+	UserID      uuid.UUID  `db:"user_id"`
+	Secret      string     `db:"secret"`
+	EnabledAt   *time.Time `db:"enabled_at"`
+	LastCounter int64      `db:"last_counter"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// MFAChallenge links the password step of a login to the code step
+type MFAChallenge struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	Attempts  int        `db:"attempts"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	UserAgent string     `db:"user_agent"`
+	IPAddress string     `db:"ip_address"`
+	CreatedAt time.Time  `db:"created_at"`
+}
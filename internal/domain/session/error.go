@@ -8,5 +8,23 @@ import (
 )
 
 var (
-	ErrSessionNotFound = cerr.New(fiber.ErrNotFound.Code, "session not found", errors.New("session not found"))
+	ErrSessionNotFound       = cerr.New(fiber.ErrNotFound.Code, "session not found", errors.New("session not found"))
+	ErrInvalidRefreshToken   = cerr.New(fiber.ErrUnauthorized.Code, "invalid refresh token", errors.New("refresh token is missing, expired or revoked"))
+	ErrRefreshTokenReused    = cerr.New(fiber.ErrUnauthorized.Code, "refresh token has already been used", errors.New("refresh token reuse detected, session revoked"))
+	ErrSessionRevoked        = cerr.New(fiber.ErrUnauthorized.Code, "session has been revoked", errors.New("session is revoked or expired"))
+	ErrInvalidPassword       = cerr.New(fiber.ErrUnauthorized.Code, "invalid password", errors.New("password confirmation failed"))
+	ErrGoogleSignInDisabled  = cerr.New(fiber.ErrServiceUnavailable.Code, "google sign-in is not configured", errors.New("google client id is not set"))
+	ErrInvalidGoogleToken    = cerr.New(fiber.ErrUnauthorized.Code, "invalid google id token", errors.New("google id token verification failed"))
+	ErrGoogleLinkRequired    = cerr.New(fiber.ErrConflict.Code, "an account with this email already exists, confirm your password to link google sign-in", errors.New("google account link requires password confirmation"))
+	ErrGoogleAccountMismatch = cerr.New(fiber.ErrConflict.Code, "this email is linked to a different google account", errors.New("google subject does not match linked account"))
+	ErrInvalidCredentials    = cerr.New(fiber.ErrUnauthorized.Code, "invalid email or password", errors.New("invalid credentials"))
+	ErrLoginLocked           = cerr.New(fiber.ErrTooManyRequests.Code, "too many failed login attempts, try again later", errors.New("login temporarily locked"))
+	ErrLoginThrottled        = cerr.New(fiber.ErrTooManyRequests.Code, "please wait a moment before trying again", errors.New("login attempted before the retry delay passed"))
+	ErrLockoutNotFound       = cerr.New(fiber.ErrNotFound.Code, "lockout not found", errors.New("no active lockout"))
+	ErrMFADisabled           = cerr.New(fiber.ErrServiceUnavailable.Code, "two-factor authentication is not configured", errors.New("mfa encryption key is not set"))
+	ErrMFANotFound           = cerr.New(fiber.ErrNotFound.Code, "two-factor authentication is not set up", errors.New("mfa enrolment not found"))
+	ErrMFAAlreadyEnabled     = cerr.New(fiber.ErrConflict.Code, "two-factor authentication is already enabled", errors.New("mfa already enabled"))
+	ErrMFANotEnabled         = cerr.New(fiber.ErrBadRequest.Code, "two-factor authentication is not enabled", errors.New("mfa not enabled"))
+	ErrInvalidMFACode        = cerr.New(fiber.ErrUnauthorized.Code, "invalid two-factor code", errors.New("totp or recovery code mismatch"))
+	ErrInvalidMFAChallenge   = cerr.New(fiber.ErrUnauthorized.Code, "login challenge is invalid or has expired, sign in again", errors.New("mfa challenge not found, used, expired or exhausted"))
 )
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) logout(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+	sessionID, _ := ctx.Locals("session_id").(string)
+
+	if err := h.service.Logout(ctx.Context(), userID, sessionID); err != nil {
+		return err
+	}
+
+	ctx.ClearCookie("refresh_token")
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "logout successful",
+	})
+}
+
+func (h *AuthHandler) logoutAll(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	if err := h.service.LogoutAll(ctx.Context(), userID); err != nil {
+		return err
+	}
+
+	ctx.ClearCookie("refresh_token")
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "logged out from all sessions",
+	})
+}
+
+func (h *AuthHandler) listSessions(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+	sessionID, _ := ctx.Locals("session_id").(string)
+
+	response, err := h.service.ListSessions(ctx.Context(), userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "get sessions successful",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) revokeSession(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	if err := h.service.Logout(ctx.Context(), userID, ctx.Params("sessionID")); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "session revoked",
+	})
+}
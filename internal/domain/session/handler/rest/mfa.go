@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) verifyMFA(ctx *fiber.Ctx) error {
+	var request session.MFAVerifyRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
+	response, err := h.service.VerifyMFA(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	setRefreshTokenCookie(ctx, response.RefreshToken)
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "login successful",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) setupMFA(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	response, err := h.service.SetupMFA(ctx.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "scan the QR code with your authenticator app, then confirm with a code",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) enableMFA(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+	sessionID, _ := ctx.Locals("session_id").(string)
+
+	var request session.MFACodeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.EnableMFA(ctx.Context(), userID, sessionID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "two-factor authentication enabled, store the recovery codes somewhere safe",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) disableMFA(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	var request session.MFACodeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
+	if err := h.service.DisableMFA(ctx.Context(), userID, request); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "two-factor authentication disabled",
+	})
+}
+
+func (h *AuthHandler) regenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	var request session.MFACodeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
+	response, err := h.service.RegenerateRecoveryCodes(ctx.Context(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "recovery codes regenerated, the old ones no longer work",
+		"payload": response,
+	})
+}
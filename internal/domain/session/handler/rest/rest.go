@@ -2,17 +2,19 @@ package rest
 
 import (
 	"github.com/vistara-studio/vistara-be/internal/domain/session/service"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type AuthHandler struct {
-	service   service.AuthServiceItf
-	validator *validator.Validate
+	service    service.AuthServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
 }
 
-func New(service service.AuthServiceItf, validator *validator.Validate) *AuthHandler {
-	return &AuthHandler{service: service, validator: validator}
+func New(service service.AuthServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *AuthHandler {
+	return &AuthHandler{service: service, validator: validator, middleware: middleware}
 }
 
 func (h *AuthHandler) Mount(router fiber.Router) {
@@ -20,4 +22,22 @@ func (h *AuthHandler) Mount(router fiber.Router) {
 
 	authGroup.Post("/register", h.register)
 	authGroup.Post("/login", h.login)
+	authGroup.Post("/google", h.googleLogin)
+	authGroup.Post("/google/link", h.linkGoogle)
+	authGroup.Post("/refresh", h.refresh)
+	authGroup.Post("/verify-email", h.verifyEmail)
+	authGroup.Post("/forgot-password", h.forgotPassword)
+	authGroup.Post("/reset-password", h.resetPassword)
+	authGroup.Post("/mfa/verify", h.verifyMFA)
+
+	authentication := h.middleware.Authentication()
+	authGroup.Post("/verify-email/resend", authentication, h.resendVerification)
+	authGroup.Post("/logout", authentication, h.logout)
+	authGroup.Post("/logout-all", authentication, h.logoutAll)
+	authGroup.Get("/sessions", authentication, h.listSessions)
+	authGroup.Delete("/sessions/:sessionID", authentication, h.revokeSession)
+	authGroup.Post("/mfa/setup", authentication, h.setupMFA)
+	authGroup.Post("/mfa/enable", authentication, h.enableMFA)
+	authGroup.Post("/mfa/disable", authentication, h.disableMFA)
+	authGroup.Post("/mfa/recovery-codes", authentication, h.regenerateRecoveryCodes)
 }
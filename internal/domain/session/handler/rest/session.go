@@ -13,10 +13,18 @@ func (h *AuthHandler) register(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	// The profile photo is optional and only sent with multipart/form-data
+	if photo, err := ctx.FormFile("photo"); err == nil {
+		request.Photo = photo
+	}
+
 	if err := h.validator.Struct(request); err != nil {
 		return err
 	}
 
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
 	response, err := h.service.Register(ctx.Context(), request)
 	if err != nil {
 		return err
@@ -35,23 +43,113 @@ func (h *AuthHandler) login(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
 	response, err := h.service.Login(ctx.Context(), request)
 	if err != nil {
 		return err
 	}
 
+	setRefreshTokenCookie(ctx, response.RefreshToken)
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "login successful",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) googleLogin(ctx *fiber.Ctx) error {
+	var request session.GoogleLoginRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
+	response, err := h.service.GoogleLogin(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	setRefreshTokenCookie(ctx, response.RefreshToken)
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "login successful",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) linkGoogle(ctx *fiber.Ctx) error {
+	var request session.GoogleLinkRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+	request.IPAddress = ctx.IP()
+
+	response, err := h.service.LinkGoogle(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	setRefreshTokenCookie(ctx, response.RefreshToken)
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "google account linked",
+		"payload": response,
+	})
+}
+
+func (h *AuthHandler) refresh(ctx *fiber.Ctx) error {
+	refreshToken := ctx.Cookies("refresh_token")
+	if refreshToken == "" {
+		return session.ErrInvalidRefreshToken
+	}
+
+	response, err := h.service.Refresh(ctx.Context(), session.RefreshRequest{
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Get(fiber.HeaderUserAgent),
+		IPAddress:    ctx.IP(),
+	})
+	if err != nil {
+		if err == session.ErrInvalidRefreshToken || err == session.ErrRefreshTokenReused {
+			ctx.ClearCookie("refresh_token")
+		}
+		return err
+	}
+
+	setRefreshTokenCookie(ctx, response.RefreshToken)
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "refresh successful",
+		"payload": response,
+	})
+}
+
+// setRefreshTokenCookie stores the refresh token, logins waiting for a second factor have none yet
+func setRefreshTokenCookie(ctx *fiber.Ctx, refreshToken string) {
+	if refreshToken == "" {
+		return
+	}
+
 	ctx.Cookie(&fiber.Cookie{
 		Name:     "refresh_token",
-		Value:    response.RefreshToken,
+		Value:    refreshToken,
 		Path:     "/",
-		Expires:  time.Now().Add(7 * 24 * time.Hour),
+		Expires:  time.Now().Add(session.RefreshTokenTTL),
 		HTTPOnly: true,
 		Secure:   true,
 		SameSite: "Lax",
 	})
-
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "login successful",
-		"payload": response,
-	})
 }
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) verifyEmail(ctx *fiber.Ctx) error {
+	var request session.VerifyEmailRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.service.VerifyEmail(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "email verified, refresh your session to pick up the change",
+	})
+}
+
+func (h *AuthHandler) resendVerification(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	if err := h.service.ResendVerification(ctx.Context(), userID); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "verification email sent",
+	})
+}
+
+func (h *AuthHandler) forgotPassword(ctx *fiber.Ctx) error {
+	var request session.ForgotPasswordRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.service.ForgotPassword(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "if the email is registered, a reset link has been sent",
+	})
+}
+
+func (h *AuthHandler) resetPassword(ctx *fiber.Ctx) error {
+	var request session.ResetPasswordRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.service.ResetPassword(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	ctx.ClearCookie("refresh_token")
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "password has been reset, please log in again",
+	})
+}
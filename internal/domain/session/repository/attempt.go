@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+)
+
+// LockLoginSubjects serialises login attempts for the email and the IP address until the transaction ends,
+// the email is always locked first so two attempts can't wait on each other
+func (r *sessionRepository) LockLoginSubjects(ctx context.Context, email, ipAddress string) error {
+	if _, err := r.q.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('login:account:' || $1))`, email); err != nil {
+		return err
+	}
+
+	_, err := r.q.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('login:ip:' || $1))`, ipAddress)
+	return err
+}
+
+func (r *sessionRepository) RecordLoginAttempt(ctx context.Context, data *session.LoginAttempt) error {
+	query := `INSERT INTO login_attempts (email, ip_address, succeeded, created_at)
+	VALUES ($1, $2, $3, NOW())
+	RETURNING id`
+
+	return r.q.QueryRowxContext(ctx, query, data.Email, data.IPAddress, data.Succeeded).Scan(&data.ID)
+}
+
+// MarkLoginAttemptSucceeded turns a recorded attempt into a success, which resets the account failure count
+func (r *sessionRepository) MarkLoginAttemptSucceeded(ctx context.Context, id int64) error {
+	_, err := r.q.ExecContext(ctx, `UPDATE login_attempts SET succeeded = TRUE WHERE id = $1`, id)
+	return err
+}
+
+// DeleteLoginAttempt forgets an attempt that ended without a verdict, such as a password step waiting for its second factor
+func (r *sessionRepository) DeleteLoginAttempt(ctx context.Context, id int64) error {
+	_, err := r.q.ExecContext(ctx, `DELETE FROM login_attempts WHERE id = $1`, id)
+	return err
+}
+
+// GetAccountLoginFailures counts failures for the email since its last successful login within the window
+func (r *sessionRepository) GetAccountLoginFailures(ctx context.Context, email string, since time.Time, out *session.LoginFailures) error {
+	query := `SELECT COUNT(*) AS failures, MAX(created_at) AS last_failed_at
+	FROM login_attempts
+	WHERE email = $1 AND succeeded = FALSE AND created_at > $2
+	AND created_at > COALESCE(
+		(SELECT MAX(created_at) FROM login_attempts WHERE email = $1 AND succeeded = TRUE),
+		'-infinity'
+	)`
+
+	return r.q.QueryRowxContext(ctx, query, email, since).StructScan(out)
+}
+
+// GetIPLoginFailures counts failures from the IP address within the window, successes don't reset it
+func (r *sessionRepository) GetIPLoginFailures(ctx context.Context, ipAddress string, since time.Time, out *session.LoginFailures) error {
+	query := `SELECT COUNT(*) AS failures, MAX(created_at) AS last_failed_at
+	FROM login_attempts
+	WHERE ip_address = $1 AND succeeded = FALSE AND created_at > $2`
+
+	return r.q.QueryRowxContext(ctx, query, ipAddress, since).StructScan(out)
+}
+
+// GetActiveLockout returns the latest lockout of the subject that has not ended yet
+func (r *sessionRepository) GetActiveLockout(ctx context.Context, scope session.LockoutScope, subject string, out *session.Lockout) error {
+	query := `SELECT 
+	id, scope, subject, user_id, failed_attempts, locked_until, ip_address, user_agent, created_at
+	FROM login_lockouts
+	WHERE scope = $1 AND subject = $2 AND locked_until > NOW()
+	ORDER BY locked_until DESC
+	LIMIT 1`
+
+	row := r.q.QueryRowxContext(ctx, query, scope, subject)
+	if err := row.StructScan(out); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.ErrLockoutNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) CreateLockout(ctx context.Context, data session.Lockout) error {
+	query := `INSERT INTO login_lockouts (
+		id, scope, subject, user_id, failed_attempts, locked_until, ip_address, user_agent
+	) VALUES (
+		:id, :scope, :subject, :user_id, :failed_attempts, :locked_until, :ip_address, :user_agent
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	return err
+}
+
+// DeleteLoginAttemptsBefore prunes attempts too old to affect throttling, lockouts are kept for auditing
+func (r *sessionRepository) DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error {
+	_, err := r.q.ExecContext(ctx, `DELETE FROM login_attempts WHERE created_at < $1`, before)
+	return err
+}
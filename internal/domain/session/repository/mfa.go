@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/google/uuid"
+)
+
+func (r *sessionRepository) GetMFA(ctx context.Context, data *session.MFA) error {
+	query := `SELECT user_id, secret, enabled_at, last_counter, created_at
+	FROM user_mfa
+	WHERE user_id = $1`
+
+	row := r.q.QueryRowxContext(ctx, query, data.UserID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.ErrMFANotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SavePendingMFA stores a new secret for an enrolment that is not enabled yet, replacing an earlier pending one
+func (r *sessionRepository) SavePendingMFA(ctx context.Context, data session.MFA) error {
+	query := `INSERT INTO user_mfa (user_id, secret, last_counter, created_at)
+	VALUES (:user_id, :secret, 0, NOW())
+	ON CONFLICT (user_id) DO UPDATE
+	SET secret = EXCLUDED.secret, last_counter = 0, created_at = NOW()
+	WHERE user_mfa.enabled_at IS NULL`
+
+	res, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// EnableMFA turns a pending enrolment on, counter is the time step of the code that confirmed it
+func (r *sessionRepository) EnableMFA(ctx context.Context, userID uuid.UUID, counter int64) error {
+	query := `UPDATE user_mfa
+	SET enabled_at = NOW(), last_counter = $2
+	WHERE user_id = $1 AND enabled_at IS NULL`
+
+	res, err := r.q.ExecContext(ctx, query, userID, counter)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// UseMFACounter records the time step of an accepted code, codes from that step or earlier can't be used again
+func (r *sessionRepository) UseMFACounter(ctx context.Context, userID uuid.UUID, counter int64) error {
+	query := `UPDATE user_mfa SET last_counter = $2 WHERE user_id = $1 AND last_counter < $2`
+
+	res, err := r.q.ExecContext(ctx, query, userID, counter)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// DeleteMFA removes the enrolment together with its recovery codes
+func (r *sessionRepository) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	_, err := r.q.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	return err
+}
+
+// ReplaceRecoveryCodes drops every existing recovery code of the user and stores the new hashes
+func (r *sessionRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO mfa_recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)`
+	for _, codeHash := range codeHashes {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.q.ExecContext(ctx, query, id, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used, atomically so it works once
+func (r *sessionRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `UPDATE mfa_recovery_codes
+	SET used_at = NOW()
+	WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	res, err := r.q.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) CreateMFAChallenge(ctx context.Context, data session.MFAChallenge) error {
+	query := `INSERT INTO mfa_challenges (
+		id, user_id, token_hash, expires_at, user_agent, ip_address
+	) VALUES (
+		:id, :user_id, :token_hash, :expires_at, :user_agent, :ip_address
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	return err
+}
+
+// GetMFAChallengeForUpdate locks an open challenge by token hash, used, expired or exhausted ones are not returned
+func (r *sessionRepository) GetMFAChallengeForUpdate(ctx context.Context, data *session.MFAChallenge) error {
+	query := `SELECT 
+	id, user_id, token_hash, attempts, expires_at, used_at, user_agent, ip_address, created_at
+	FROM mfa_challenges
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() AND attempts < $2
+	FOR UPDATE`
+
+	row := r.q.QueryRowxContext(ctx, query, data.TokenHash, session.MaxMFAChallengeAttempts)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.ErrInvalidMFAChallenge
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) IncrementMFAChallengeAttempts(ctx context.Context, id uuid.UUID) error {
+	_, err := r.q.ExecContext(ctx, `UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r *sessionRepository) UseMFAChallenge(ctx context.Context, id uuid.UUID) error {
+	_, err := r.q.ExecContext(ctx, `UPDATE mfa_challenges SET used_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// MarkSessionFamilyMFA records that the active sessions of a family passed a second factor
+func (r *sessionRepository) MarkSessionFamilyMFA(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE sessions SET mfa_authenticated = TRUE
+	WHERE family_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.q.ExecContext(ctx, query, familyID)
+	return err
+}
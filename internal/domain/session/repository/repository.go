@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/session"
 	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -30,9 +33,38 @@ type sessionRepository struct {
 type sessionRepositoryItf interface {
 	Commit() error
 	Rollback() error
+	AuditTx() audit.Tx
 	CreateSession(ctx context.Context, data session.Table) error
 	GetSessionByUserID(ctx context.Context, data *user.Table, out *[]session.Table) error
-	DeleteOldestSessionByUserID(ctx context.Context, data session.Table) error
+	RevokeOldestSessionFamily(ctx context.Context, data session.Table) error
+	GetSessionByTokenHashForUpdate(ctx context.Context, data *session.Table) error
+	RotateSession(ctx context.Context, data session.Table, replacedBy uuid.UUID) error
+	RevokeSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	GetActiveSessionByFamilyID(ctx context.Context, data *session.Table) error
+	UpdateSessionLastUsed(ctx context.Context, data session.Table) error
+	RevokeUserSessionFamily(ctx context.Context, userID, familyID uuid.UUID) error
+	RevokeSessionsByUserID(ctx context.Context, userID, keepFamilyID uuid.UUID) error
+	LockLoginSubjects(ctx context.Context, email, ipAddress string) error
+	RecordLoginAttempt(ctx context.Context, data *session.LoginAttempt) error
+	MarkLoginAttemptSucceeded(ctx context.Context, id int64) error
+	DeleteLoginAttempt(ctx context.Context, id int64) error
+	GetAccountLoginFailures(ctx context.Context, email string, since time.Time, out *session.LoginFailures) error
+	GetIPLoginFailures(ctx context.Context, ipAddress string, since time.Time, out *session.LoginFailures) error
+	GetActiveLockout(ctx context.Context, scope session.LockoutScope, subject string, out *session.Lockout) error
+	CreateLockout(ctx context.Context, data session.Lockout) error
+	DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error
+	GetMFA(ctx context.Context, data *session.MFA) error
+	SavePendingMFA(ctx context.Context, data session.MFA) error
+	EnableMFA(ctx context.Context, userID uuid.UUID, counter int64) error
+	UseMFACounter(ctx context.Context, userID uuid.UUID, counter int64) error
+	DeleteMFA(ctx context.Context, userID uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	CreateMFAChallenge(ctx context.Context, data session.MFAChallenge) error
+	GetMFAChallengeForUpdate(ctx context.Context, data *session.MFAChallenge) error
+	IncrementMFAChallengeAttempts(ctx context.Context, id uuid.UUID) error
+	UseMFAChallenge(ctx context.Context, id uuid.UUID) error
+	MarkSessionFamilyMFA(ctx context.Context, familyID uuid.UUID) error
 }
 
 type namedExt interface {
@@ -74,3 +106,8 @@ func (r *sessionRepository) Rollback() error {
 
 	return errFailedToRollback
 }
+
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (r *sessionRepository) AuditTx() audit.Tx {
+	return r.q
+}
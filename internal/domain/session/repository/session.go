@@ -2,16 +2,19 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/vistara-studio/vistara-be/internal/domain/session"
 	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/google/uuid"
 )
 
 func (r *sessionRepository) CreateSession(ctx context.Context, data session.Table) error {
 	query := `INSERT INTO sessions (
-		id, user_id
+		id, user_id, family_id, token_hash, expires_at, user_agent, ip_address, mfa_authenticated, last_used_at
 	) VALUES (
-		:id, :user_id
+		:id, :user_id, :family_id, :token_hash, :expires_at, :user_agent, :ip_address, :mfa_authenticated, NOW()
 	)`
 
 	_, err := r.q.NamedExecContext(ctx, query, data)
@@ -22,11 +25,14 @@ func (r *sessionRepository) CreateSession(ctx context.Context, data session.Tabl
 	return nil
 }
 
-func (r *sessionRepository) DeleteOldestSessionByUserID(ctx context.Context, data session.Table) error {
-	query := `DELETE FROM sessions
-WHERE id = (
-    SELECT id FROM sessions
-    WHERE user_id = $1
+// RevokeOldestSessionFamily signs out the family of the least recently refreshed active session. The rows
+// stay until they expire so a later use of one of the family's rotated tokens is still recognised as reuse.
+func (r *sessionRepository) RevokeOldestSessionFamily(ctx context.Context, data session.Table) error {
+	query := `UPDATE sessions
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL AND family_id = (
+    SELECT family_id FROM sessions
+    WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
     ORDER BY created_at ASC
     LIMIT 1
 )`
@@ -48,11 +54,15 @@ WHERE id = (
 	return nil
 }
 
+// GetSessionByUserID returns the active sessions of a user, one row per session family
 func (r *sessionRepository) GetSessionByUserID(ctx context.Context, data *user.Table, out *[]session.Table) error {
 	query := `SELECT 
-	id, user_id, created_at
-	FROM sessions
-	WHERE user_id = $1
+	s.id, s.user_id, s.family_id, s.replaced_by, s.revoked_at, s.expires_at,
+	s.user_agent, s.ip_address, s.last_used_at, s.created_at,
+	(SELECT MIN(f.created_at) FROM sessions f WHERE f.family_id = s.family_id) AS signed_in_at
+	FROM sessions s
+	WHERE s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > NOW()
+	ORDER BY s.last_used_at DESC
 	`
 
 	rows, err := r.q.QueryxContext(ctx, query, data.ID)
@@ -74,3 +84,120 @@ func (r *sessionRepository) GetSessionByUserID(ctx context.Context, data *user.T
 	*out = result
 	return nil
 }
+
+// GetSessionByTokenHashForUpdate locks the session a refresh token was issued for
+func (r *sessionRepository) GetSessionByTokenHashForUpdate(ctx context.Context, data *session.Table) error {
+	query := `SELECT 
+	id, user_id, family_id, token_hash, replaced_by, revoked_at, expires_at,
+	user_agent, ip_address, mfa_authenticated, last_used_at, created_at
+	FROM sessions
+	WHERE token_hash = $1
+	FOR UPDATE
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, data.TokenHash)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.ErrSessionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RotateSession revokes a session and points it at the session that replaced it
+func (r *sessionRepository) RotateSession(ctx context.Context, data session.Table, replacedBy uuid.UUID) error {
+	query := `UPDATE sessions
+	SET replaced_by = $2, revoked_at = NOW()
+	WHERE id = $1 AND revoked_at IS NULL`
+
+	res, err := r.q.ExecContext(ctx, query, data.ID, replacedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeSessionFamily revokes every still active session that descends from the same login
+func (r *sessionRepository) RevokeSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `UPDATE sessions
+	SET revoked_at = NOW()
+	WHERE family_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.q.ExecContext(ctx, query, familyID)
+	return err
+}
+
+// GetActiveSessionByFamilyID returns the current, not yet rotated session of a family
+func (r *sessionRepository) GetActiveSessionByFamilyID(ctx context.Context, data *session.Table) error {
+	query := `SELECT 
+	id, user_id, family_id, replaced_by, revoked_at, expires_at,
+	user_agent, ip_address, last_used_at, created_at
+	FROM sessions
+	WHERE family_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	ORDER BY created_at DESC
+	LIMIT 1
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, data.FamilyID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.ErrSessionNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) UpdateSessionLastUsed(ctx context.Context, data session.Table) error {
+	query := `UPDATE sessions SET last_used_at = NOW() WHERE id = $1`
+
+	_, err := r.q.ExecContext(ctx, query, data.ID)
+	return err
+}
+
+// RevokeUserSessionFamily revokes a session family only if it belongs to the given user
+func (r *sessionRepository) RevokeUserSessionFamily(ctx context.Context, userID, familyID uuid.UUID) error {
+	query := `UPDATE sessions
+	SET revoked_at = NOW()
+	WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL`
+
+	res, err := r.q.ExecContext(ctx, query, userID, familyID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeSessionsByUserID revokes every active session of a user except the keepFamilyID family,
+// pass uuid.Nil to revoke all of them
+func (r *sessionRepository) RevokeSessionsByUserID(ctx context.Context, userID, keepFamilyID uuid.UUID) error {
+	query := `UPDATE sessions
+	SET revoked_at = NOW()
+	WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL`
+
+	_, err := r.q.ExecContext(ctx, query, userID, keepFamilyID)
+	return err
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/infra/oauth"
+	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/google/uuid"
+)
+
+// GoogleLogin signs a user in with a Google ID token, creating the account on first use.
+// An existing email/password account is never taken over here; it has to be linked with LinkGoogle.
+func (s *authService) GoogleLogin(ctx context.Context, request session.GoogleLoginRequest) (session.LoginResponse, error) {
+	identity, err := s.verifyGoogleToken(ctx, request.IDToken)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	account := &user.Table{GoogleID: identity.Subject}
+	err = userRepository.GetAccountByGoogleID(ctx, account)
+	if err == nil {
+		return s.completeLogin(ctx, account, request.UserAgent, request.IPAddress)
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return session.LoginResponse{}, err
+	}
+
+	account = &user.Table{Email: identity.Email}
+	err = userRepository.GetAccountByEmail(ctx, account)
+	if err == nil {
+		if account.GoogleID != "" {
+			return session.LoginResponse{}, session.ErrGoogleAccountMismatch
+		}
+		return session.LoginResponse{}, session.ErrGoogleLinkRequired
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return session.LoginResponse{}, err
+	}
+
+	userID, err := uuid.NewV7()
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	// Google only issues ID tokens for verified emails
+	verifiedAt := time.Now()
+	account = &user.Table{
+		ID:           userID,
+		FullName:     identity.Name,
+		Email:        identity.Email,
+		AuthProvider: user.AuthProviderGoogle,
+		GoogleID:     identity.Subject,
+		PhotoUrl:     identity.Picture,
+		VerifiedAt:   &verifiedAt,
+	}
+	if account.FullName == "" {
+		account.FullName = strings.Split(identity.Email, "@")[0]
+	}
+	if account.PhotoUrl == "" {
+		account.PhotoUrl = s.defaultPhotoURL
+	}
+
+	if err := userRepository.CreateUser(ctx, *account); err != nil {
+		if errors.Is(err, user.ErrEmailAlreadyExists) {
+			return session.LoginResponse{}, session.ErrGoogleLinkRequired
+		}
+		return session.LoginResponse{}, err
+	}
+
+	return s.completeLogin(ctx, account, request.UserAgent, request.IPAddress)
+}
+
+// LinkGoogle attaches a Google account to the email/password account with the same email
+// after the user confirms the account password
+func (s *authService) LinkGoogle(ctx context.Context, request session.GoogleLinkRequest) (session.LoginResponse, error) {
+	identity, err := s.verifyGoogleToken(ctx, request.IDToken)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	// The password check goes through the login throttle so linking can't be used to guess passwords
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	attemptID, err := s.beginLoginAttempt(ctx, email, request.IPAddress)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	account := &user.Table{Email: identity.Email}
+	if err := userRepository.GetAccountByEmail(ctx, account); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	if account.Password == "" || bcrypt.ComparePassword(account.Password, request.Password) != nil {
+		attempt := session.LoginRequest{Email: email, UserAgent: request.UserAgent, IPAddress: request.IPAddress}
+		if err := s.failLogin(ctx, email, attempt, &account.ID); !errors.Is(err, session.ErrInvalidCredentials) {
+			return session.LoginResponse{}, err
+		}
+		return session.LoginResponse{}, session.ErrInvalidPassword
+	}
+
+	switch account.GoogleID {
+	case identity.Subject:
+		// Already linked, nothing to do
+	case "":
+		account.GoogleID = identity.Subject
+		if err := userRepository.LinkGoogleAccount(ctx, *account); err != nil {
+			return session.LoginResponse{}, err
+		}
+
+		// The Google token proves ownership of the email as well
+		if account.VerifiedAt == nil {
+			if err := userRepository.MarkEmailVerified(ctx, *account); err != nil {
+				return session.LoginResponse{}, err
+			}
+			verifiedAt := time.Now()
+			account.VerifiedAt = &verifiedAt
+		}
+	default:
+		return session.LoginResponse{}, session.ErrGoogleAccountMismatch
+	}
+
+	return s.finishPasswordLogin(ctx, attemptID, account, request.UserAgent, request.IPAddress)
+}
+
+func (s *authService) verifyGoogleToken(ctx context.Context, idToken string) (*oauth.GoogleIdentity, error) {
+	if s.google == nil || !s.google.Enabled() {
+		return nil, session.ErrGoogleSignInDisabled
+	}
+
+	identity, err := s.google.Verify(ctx, idToken)
+	if err != nil {
+		return nil, session.ErrInvalidGoogleToken
+	}
+
+	return identity, nil
+}
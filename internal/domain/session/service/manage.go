@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// lastUsedResolution limits how often a session's last_used_at is written
+const lastUsedResolution = time.Minute
+
+// ValidateSession checks that the session family an access token belongs to is still active
+func (s *authService) ValidateSession(ctx context.Context, sessionID string) error {
+	familyID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return session.ErrSessionRevoked
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	current := &session.Table{FamilyID: familyID}
+	if err := sessionRepository.GetActiveSessionByFamilyID(ctx, current); err != nil {
+		if err == session.ErrSessionNotFound {
+			return session.ErrSessionRevoked
+		}
+		return err
+	}
+
+	if time.Since(current.LastUsedAt) > lastUsedResolution {
+		return sessionRepository.UpdateSessionLastUsed(ctx, *current)
+	}
+
+	return nil
+}
+
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]session.SessionResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return []session.SessionResponse{}, user.ErrUserNotFound
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return []session.SessionResponse{}, err
+	}
+
+	sessions := new([]session.Table)
+	if err := sessionRepository.GetSessionByUserID(ctx, &user.Table{ID: id}, sessions); err != nil {
+		return []session.SessionResponse{}, err
+	}
+
+	response := make([]session.SessionResponse, len(*sessions))
+	for i, item := range *sessions {
+		response[i] = session.SessionResponse{
+			ID:         item.FamilyID,
+			UserAgent:  item.UserAgent,
+			IPAddress:  item.IPAddress,
+			SignedInAt: item.SignedInAt,
+			LastUsedAt: item.LastUsedAt,
+			ExpiresAt:  item.ExpiresAt,
+			Current:    item.FamilyID.String() == currentSessionID,
+		}
+	}
+
+	return response, nil
+}
+
+// Logout revokes one of the user's sessions, including every refresh token rotated from it
+func (s *authService) Logout(ctx context.Context, userID, sessionID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	familyID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return session.ErrSessionNotFound
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return sessionRepository.RevokeUserSessionFamily(ctx, id, familyID)
+}
+
+// LogoutAll revokes every session of the user
+func (s *authService) LogoutAll(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return sessionRepository.RevokeSessionsByUserID(ctx, id, uuid.Nil)
+}
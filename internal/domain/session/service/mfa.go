@@ -0,0 +1,478 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/pkg/totp"
+	"github.com/google/uuid"
+)
+
+// totpSkew accepts the previous and next code as well to absorb clock drift
+const totpSkew = 1
+
+// completeLogin issues the session right away, or a challenge when the account has two-factor authentication on
+func (s *authService) completeLogin(ctx context.Context, account *user.Table, userAgent, ipAddress string) (session.LoginResponse, error) {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	mfa := &session.MFA{UserID: account.ID}
+	err = sessionRepository.GetMFA(ctx, mfa)
+	if err != nil && !errors.Is(err, session.ErrMFANotFound) {
+		return session.LoginResponse{}, err
+	}
+
+	if err != nil || mfa.EnabledAt == nil {
+		return s.issueSession(ctx, account, userAgent, ipAddress, false)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return session.LoginResponse{}, err
+	}
+	rawToken := base64.RawURLEncoding.EncodeToString(secret)
+
+	challengeID, err := uuid.NewV7()
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	err = sessionRepository.CreateMFAChallenge(ctx, session.MFAChallenge{
+		ID:        challengeID,
+		UserID:    account.ID,
+		TokenHash: hashUserToken(rawToken),
+		ExpiresAt: time.Now().Add(session.MFAChallengeTTL),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	})
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	return session.LoginResponse{
+		MFARequired: true,
+		MFAToken:    rawToken,
+	}, nil
+}
+
+// VerifyMFA completes a two-step login, a challenge is burned after too many wrong codes.
+// Every code is a login attempt as well, so wrong codes hit the same limits as wrong passwords.
+func (s *authService) VerifyMFA(ctx context.Context, request session.MFAVerifyRequest) (response session.LoginResponse, err error) {
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	challenge := &session.MFAChallenge{TokenHash: hashUserToken(request.MFAToken)}
+	if err := sessionRepository.GetMFAChallengeForUpdate(ctx, challenge); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	account := &user.Table{ID: challenge.UserID}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	attemptID, err := s.beginLoginAttempt(ctx, strings.ToLower(strings.TrimSpace(account.Email)), request.IPAddress)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	// The code is checked on the challenge transaction, a recovery code is only spent if the login goes through
+	codeErr := s.checkMFACode(ctx, sessionRepository, challenge.UserID, request.Code, request.RecoveryCode, true)
+	if codeErr != nil {
+		if !errors.Is(codeErr, session.ErrInvalidMFACode) {
+			return session.LoginResponse{}, codeErr
+		}
+
+		if err := sessionRepository.IncrementMFAChallengeAttempts(ctx, challenge.ID); err != nil {
+			return session.LoginResponse{}, err
+		}
+		if err := sessionRepository.Commit(); err != nil {
+			return session.LoginResponse{}, err
+		}
+		committed = true
+
+		return session.LoginResponse{}, s.failMFACode(ctx, account, request.UserAgent, request.IPAddress)
+	}
+
+	if err := sessionRepository.UseMFAChallenge(ctx, challenge.ID); err != nil {
+		return session.LoginResponse{}, err
+	}
+	if err := sessionRepository.Commit(); err != nil {
+		return session.LoginResponse{}, err
+	}
+	committed = true
+
+	s.recordLoginSuccess(ctx, attemptID)
+
+	return s.issueSession(ctx, account, request.UserAgent, request.IPAddress, true)
+}
+
+// failMFACode counts a wrong code like a wrong password, locking the account or IP address at the same limits
+func (s *authService) failMFACode(ctx context.Context, account *user.Table, userAgent, ipAddress string) error {
+	email := strings.ToLower(strings.TrimSpace(account.Email))
+	attempt := session.LoginRequest{Email: email, UserAgent: userAgent, IPAddress: ipAddress}
+
+	if err := s.failLogin(ctx, email, attempt, &account.ID); !errors.Is(err, session.ErrInvalidCredentials) {
+		return err
+	}
+
+	return session.ErrInvalidMFACode
+}
+
+// SetupMFA starts enrolment with a new secret, the user confirms it with EnableMFA
+func (s *authService) SetupMFA(ctx context.Context, userID string) (session.MFASetupResponse, error) {
+	if s.mfa.Secrets == nil {
+		return session.MFASetupResponse{}, session.ErrMFADisabled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return session.MFASetupResponse{}, user.ErrUserNotFound
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	account := &user.Table{ID: id}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	sealed, err := s.mfa.Secrets.Seal([]byte(secret))
+	if err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	if err := sessionRepository.SavePendingMFA(ctx, session.MFA{UserID: account.ID, Secret: sealed}); err != nil {
+		return session.MFASetupResponse{}, err
+	}
+
+	return session.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.ProvisioningURI(s.mfa.Issuer, account.Email, secret),
+	}, nil
+}
+
+// EnableMFA confirms the pending secret with a code, hands out recovery codes and signs out every other session.
+// The current session counts as verified, so it gets a new access token with the mfa claim.
+func (s *authService) EnableMFA(ctx context.Context, userID, sessionID string, request session.MFACodeRequest) (response session.MFAEnableResponse, err error) {
+	if s.mfa.Secrets == nil {
+		return session.MFAEnableResponse{}, session.ErrMFADisabled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return session.MFAEnableResponse{}, user.ErrUserNotFound
+	}
+
+	familyID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return session.MFAEnableResponse{}, session.ErrSessionNotFound
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	mfa := &session.MFA{UserID: id}
+	if err = sessionRepository.GetMFA(ctx, mfa); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+	if mfa.EnabledAt != nil {
+		return session.MFAEnableResponse{}, session.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := s.mfa.Secrets.Open(mfa.Secret)
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	counter, ok := totp.Validate(string(secret), request.Code, time.Now(), totpSkew)
+	if !ok {
+		return session.MFAEnableResponse{}, session.ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	if err = sessionRepository.EnableMFA(ctx, id, counter); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+	if err = sessionRepository.ReplaceRecoveryCodes(ctx, id, hashes); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+	if err = sessionRepository.RevokeSessionsByUserID(ctx, id, familyID); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+	if err = sessionRepository.MarkSessionFamilyMFA(ctx, familyID); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	account := &user.Table{ID: id}
+	if err = userRepository.GetAccountByID(ctx, account); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	token, err := s.jwt.Encode(account, familyID, true)
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, sessionRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(userID),
+		Action:     audit.ActionMFAEnabled,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	if err = sessionRepository.Commit(); err != nil {
+		return session.MFAEnableResponse{}, err
+	}
+
+	return session.MFAEnableResponse{
+		AccessToken:   token,
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// DisableMFA turns two-factor authentication off after a TOTP or recovery code confirms it
+func (s *authService) DisableMFA(ctx context.Context, userID string, request session.MFACodeRequest) (err error) {
+	account, attemptID, err := s.beginMFACodeAttempt(ctx, userID, request.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	if err = s.checkMFACode(ctx, sessionRepository, account.ID, request.Code, request.RecoveryCode, true); err != nil {
+		if errors.Is(err, session.ErrInvalidMFACode) {
+			return s.failMFACode(ctx, account, request.UserAgent, request.IPAddress)
+		}
+		return err
+	}
+
+	if err = sessionRepository.DeleteMFA(ctx, account.ID); err != nil {
+		return err
+	}
+	err = s.audit.Record(ctx, sessionRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(userID),
+		Action:     audit.ActionMFADisabled,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = sessionRepository.Commit(); err != nil {
+		return err
+	}
+
+	s.recordLoginSuccess(ctx, attemptID)
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, confirmed with a TOTP code only
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID string, request session.MFACodeRequest) (response session.RecoveryCodesResponse, err error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return session.RecoveryCodesResponse{}, err
+	}
+
+	account, attemptID, err := s.beginMFACodeAttempt(ctx, userID, request.IPAddress)
+	if err != nil {
+		return session.RecoveryCodesResponse{}, err
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return session.RecoveryCodesResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	if err = s.checkMFACode(ctx, sessionRepository, account.ID, request.Code, "", false); err != nil {
+		if errors.Is(err, session.ErrInvalidMFACode) {
+			return session.RecoveryCodesResponse{}, s.failMFACode(ctx, account, request.UserAgent, request.IPAddress)
+		}
+		return session.RecoveryCodesResponse{}, err
+	}
+
+	if err = sessionRepository.ReplaceRecoveryCodes(ctx, account.ID, hashes); err != nil {
+		return session.RecoveryCodesResponse{}, err
+	}
+	if err = sessionRepository.Commit(); err != nil {
+		return session.RecoveryCodesResponse{}, err
+	}
+
+	s.recordLoginSuccess(ctx, attemptID)
+
+	return session.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// beginMFACodeAttempt loads the signed in account and starts a login attempt for the code it is about to check,
+// so a stolen access token can't be used to guess codes faster than passwords
+func (s *authService) beginMFACodeAttempt(ctx context.Context, userID, ipAddress string) (*user.Table, int64, error) {
+	if s.mfa.Secrets == nil {
+		return nil, 0, session.ErrMFADisabled
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, 0, user.ErrUserNotFound
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	account := &user.Table{ID: id}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return nil, 0, err
+	}
+
+	attemptID, err := s.beginLoginAttempt(ctx, strings.ToLower(strings.TrimSpace(account.Email)), ipAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return account, attemptID, nil
+}
+
+// mfaCodeStore is the part of a session repository client that checkMFACode needs
+type mfaCodeStore interface {
+	GetMFA(ctx context.Context, data *session.MFA) error
+	UseMFACounter(ctx context.Context, userID uuid.UUID, counter int64) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+// checkMFACode accepts a TOTP code that was not used before, or an unused recovery code when allowed.
+// The code is spent on store, which should be the transaction of the change the code confirms.
+func (s *authService) checkMFACode(ctx context.Context, store mfaCodeStore, userID uuid.UUID, code, recoveryCode string, allowRecovery bool) error {
+	if s.mfa.Secrets == nil {
+		return session.ErrMFADisabled
+	}
+
+	mfa := &session.MFA{UserID: userID}
+	if err := store.GetMFA(ctx, mfa); err != nil {
+		if errors.Is(err, session.ErrMFANotFound) {
+			return session.ErrMFANotEnabled
+		}
+		return err
+	}
+	if mfa.EnabledAt == nil {
+		return session.ErrMFANotEnabled
+	}
+
+	if code == "" {
+		if !allowRecovery || recoveryCode == "" {
+			return session.ErrInvalidMFACode
+		}
+		return store.UseRecoveryCode(ctx, userID, hashUserToken(normalizeRecoveryCode(recoveryCode)))
+	}
+
+	secret, err := s.mfa.Secrets.Open(mfa.Secret)
+	if err != nil {
+		return err
+	}
+
+	counter, ok := totp.Validate(string(secret), code, time.Now(), totpSkew)
+	if !ok {
+		return session.ErrInvalidMFACode
+	}
+
+	// Each code works once, a replay inside its 30 second window is rejected
+	return store.UseMFACounter(ctx, userID, counter)
+}
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx and the hashes to store
+func generateRecoveryCodes() (codes []string, hashes []string, err error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := 0; i < session.RecoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(raw)[:10])
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashUserToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes typed by the user
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
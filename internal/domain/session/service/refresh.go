@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// newRefreshToken returns a random 256-bit refresh token and the hash stored in its place.
+// The token is unrelated to the session and family IDs, which end up in access tokens.
+func newRefreshToken() (rawToken, tokenHash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	rawToken = base64.RawURLEncoding.EncodeToString(secret)
+
+	return rawToken, hashRefreshToken(rawToken), nil
+}
+
+func hashRefreshToken(rawToken string) string {
+	digest := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(digest[:])
+}
+
+// refreshResult is what presenting the refresh token of a session leads to
+type refreshResult int
+
+const (
+	refreshRotate refreshResult = iota
+	refreshReused
+	refreshInvalid
+)
+
+// refreshOutcome decides what to do with the session a refresh token was issued for. Only the holder of the
+// random secret can present it, so a rotated token coming back means two parties hold it: the real owner and
+// a thief, and the family is revoked.
+func refreshOutcome(current *session.Table, now time.Time) refreshResult {
+	if current.RevokedAt != nil {
+		if current.ReplacedBy == nil {
+			// Revoked by a logout, not by a rotation
+			return refreshInvalid
+		}
+		return refreshReused
+	}
+
+	if current.ExpiresAt.Before(now) {
+		return refreshInvalid
+	}
+
+	return refreshRotate
+}
+
+// Refresh exchanges a refresh token for a new access token and a rotated refresh token.
+// Presenting a refresh token that was already rotated means it leaked, so the whole
+// session family is revoked and the user has to log in again.
+func (s *authService) Refresh(ctx context.Context, request session.RefreshRequest) (response session.LoginResponse, err error) {
+	if request.RefreshToken == "" {
+		return session.LoginResponse{}, session.ErrInvalidRefreshToken
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	current := &session.Table{TokenHash: hashRefreshToken(request.RefreshToken)}
+	if err := sessionRepository.GetSessionByTokenHashForUpdate(ctx, current); err != nil {
+		if err == session.ErrSessionNotFound {
+			return session.LoginResponse{}, session.ErrInvalidRefreshToken
+		}
+		return session.LoginResponse{}, err
+	}
+
+	switch refreshOutcome(current, time.Now()) {
+	case refreshReused:
+		if err := sessionRepository.RevokeSessionFamily(ctx, current.FamilyID); err != nil {
+			return session.LoginResponse{}, err
+		}
+		if err := sessionRepository.Commit(); err != nil {
+			return session.LoginResponse{}, err
+		}
+		committed = true
+
+		return session.LoginResponse{}, session.ErrRefreshTokenReused
+	case refreshInvalid:
+		return session.LoginResponse{}, session.ErrInvalidRefreshToken
+	}
+
+	// Reload the account so the new access token reflects the current premium state
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	account := &user.Table{ID: current.UserID}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	token, err := s.jwt.Encode(account, current.FamilyID, current.MFA)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	newSessionID, err := uuid.NewV7()
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	refreshToken, tokenHash, err := newRefreshToken()
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	next := session.Table{
+		ID:        newSessionID,
+		UserID:    current.UserID,
+		FamilyID:  current.FamilyID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(session.RefreshTokenTTL),
+		UserAgent: request.UserAgent,
+		IPAddress: request.IPAddress,
+		MFA:       current.MFA,
+	}
+
+	if err := sessionRepository.CreateSession(ctx, next); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	if err := sessionRepository.RotateSession(ctx, *current, next.ID); err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	if err := sessionRepository.Commit(); err != nil {
+		return session.LoginResponse{}, err
+	}
+	committed = true
+
+	return session.LoginResponse{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	}, nil
+}
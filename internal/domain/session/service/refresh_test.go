@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		rawToken, tokenHash, err := newRefreshToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret, err := base64.RawURLEncoding.DecodeString(rawToken)
+		if err != nil {
+			t.Fatalf("token %q is not raw url base64: %v", rawToken, err)
+		}
+		if len(secret) != 32 {
+			t.Fatalf("token carries %d bytes, want 32", len(secret))
+		}
+		if tokenHash != hashRefreshToken(rawToken) {
+			t.Fatal("returned hash doesn't match the token")
+		}
+		if strings.Contains(tokenHash, rawToken) {
+			t.Fatal("stored hash contains the token")
+		}
+		if seen[rawToken] {
+			t.Fatalf("token %q issued twice", rawToken)
+		}
+		seen[rawToken] = true
+	}
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "empty", token: "", want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{name: "token", token: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := hashRefreshToken(test.token); got != test.want {
+				t.Fatalf("hashRefreshToken(%q) = %s, want %s", test.token, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRefreshOutcome(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+	replacement := uuid.New()
+
+	tests := []struct {
+		name    string
+		current session.Table
+		want    refreshResult
+	}{
+		{name: "active", current: session.Table{ExpiresAt: now.Add(time.Hour)}, want: refreshRotate},
+		{name: "expired", current: session.Table{ExpiresAt: earlier}, want: refreshInvalid},
+		{name: "logged out", current: session.Table{RevokedAt: &earlier, ExpiresAt: now.Add(time.Hour)}, want: refreshInvalid},
+		{name: "rotated", current: session.Table{RevokedAt: &earlier, ReplacedBy: &replacement, ExpiresAt: now.Add(time.Hour)}, want: refreshReused},
+		{name: "rotated and expired", current: session.Table{RevokedAt: &earlier, ReplacedBy: &replacement, ExpiresAt: earlier}, want: refreshReused},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := refreshOutcome(&test.current, now); got != test.want {
+				t.Fatalf("refreshOutcome() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
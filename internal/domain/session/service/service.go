@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/session"
 	sessionRepository "github.com/vistara-studio/vistara-be/internal/domain/session/repository"
 	userRepository "github.com/vistara-studio/vistara-be/internal/domain/user/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/mailer"
+	"github.com/vistara-studio/vistara-be/internal/infra/oauth"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
 	"github.com/vistara-studio/vistara-be/pkg/jwt"
 )
 
@@ -13,18 +17,59 @@ type authService struct {
 	repository        userRepository.RepositoryItf
 	sessionRepository sessionRepository.RepositoryItf
 	jwt               *jwt.JWTStruct
+	google            GoogleVerifier
+	mailer            mailer.Mailer
+	storage           storage.Uploader
+	remover           storage.Remover
+	appURL            string
+	defaultPhotoURL   string
+	throttle          session.LoginThrottle
+	mfa               session.MFAConfig
+	audit             audit.Recorder
+}
+
+// GoogleVerifier validates Google ID tokens presented at sign-in
+type GoogleVerifier interface {
+	Enabled() bool
+	Verify(ctx context.Context, idToken string) (*oauth.GoogleIdentity, error)
 }
 
 type AuthServiceItf interface {
 	Register(ctx context.Context, request session.RegisterRequest) (session.LoginResponse, error)
 	Login(ctx context.Context, request session.LoginRequest) (session.LoginResponse, error)
+	GoogleLogin(ctx context.Context, request session.GoogleLoginRequest) (session.LoginResponse, error)
+	LinkGoogle(ctx context.Context, request session.GoogleLinkRequest) (session.LoginResponse, error)
+	Refresh(ctx context.Context, request session.RefreshRequest) (session.LoginResponse, error)
+	VerifyEmail(ctx context.Context, request session.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, userID string) error
+	ForgotPassword(ctx context.Context, request session.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, request session.ResetPasswordRequest) error
+	ValidateSession(ctx context.Context, sessionID string) error
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]session.SessionResponse, error)
+	Logout(ctx context.Context, userID, sessionID string) error
+	LogoutAll(ctx context.Context, userID string) error
+	PruneLoginAttempts(ctx context.Context) error
+	VerifyMFA(ctx context.Context, request session.MFAVerifyRequest) (session.LoginResponse, error)
+	SetupMFA(ctx context.Context, userID string) (session.MFASetupResponse, error)
+	EnableMFA(ctx context.Context, userID, sessionID string, request session.MFACodeRequest) (session.MFAEnableResponse, error)
+	DisableMFA(ctx context.Context, userID string, request session.MFACodeRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, request session.MFACodeRequest) (session.RecoveryCodesResponse, error)
 }
 
-func New(repository userRepository.RepositoryItf, sessionRepository sessionRepository.RepositoryItf, jwt *jwt.JWTStruct) AuthServiceItf {
+func New(repository userRepository.RepositoryItf, sessionRepository sessionRepository.RepositoryItf, jwt *jwt.JWTStruct, google GoogleVerifier, mailer mailer.Mailer, storage storage.Uploader, remover storage.Remover, appURL, defaultPhotoURL string, throttle session.LoginThrottle, mfa session.MFAConfig, recorder audit.Recorder) AuthServiceItf {
 
 	return &authService{
 		repository:        repository,
 		sessionRepository: sessionRepository,
 		jwt:               jwt,
+		google:            google,
+		mailer:            mailer,
+		storage:           storage,
+		remover:           remover,
+		appURL:            appURL,
+		defaultPhotoURL:   defaultPhotoURL,
+		throttle:          throttle,
+		mfa:               mfa,
+		audit:             recorder,
 	}
 }
@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/vistara-studio/vistara-be/internal/domain/session"
 	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
 	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/vistara-studio/vistara-be/pkg/util"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 func (s *authService) Register(ctx context.Context, request session.RegisterRequest) (session.LoginResponse, error) {
@@ -27,23 +32,48 @@ func (s *authService) Register(ctx context.Context, request session.RegisterRequ
 		return session.LoginResponse{}, err
 	}
 
+	photoURL := s.defaultPhotoURL
+	if request.Photo != nil {
+		if err := util.ValidateFile(request.Photo); err != nil {
+			return session.LoginResponse{}, user.ErrInvalidPhoto
+		}
+
+		photoURL, err = storage.UploadProfilePhoto(s.storage, userID, request.Photo)
+		if err != nil {
+			return session.LoginResponse{}, user.ErrPhotoUploadFailed
+		}
+	}
+
 	user := user.Table{
 		ID:           userID,
 		FullName:     request.FullName,
 		Email:        request.Email,
 		Password:     hashedPassword,
 		AuthProvider: user.AuthProviderEmail,
-		PhotoUrl:     "https://htnqkjejgcovkehhtqjw.supabase.co/storage/v1/object/public/hackfest-uhuy//photo_profile.jpg",
+		PhotoUrl:     photoURL,
 	}
 
 	err = userRepository.CreateUser(ctx, user)
 	if err != nil {
+		// The photo was stored under the new user's prefix, nothing else references it
+		if request.Photo != nil {
+			if err := storage.RemoveProfilePhotos(ctx, s.remover, userID); err != nil {
+				log.Warn().Err(err).Str("user_id", userID.String()).Msg("failed to remove orphaned profile photo")
+			}
+		}
 		return session.LoginResponse{}, err
 	}
 
+	// The account is usable right away; a failed email can be re-sent later
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID.String()).Msg("failed to send verification email")
+	}
+
 	return s.Login(ctx, session.LoginRequest{
-		Email:    request.Email,
-		Password: request.Password,
+		Email:     request.Email,
+		Password:  request.Password,
+		UserAgent: request.UserAgent,
+		IPAddress: request.IPAddress,
 	})
 }
 
@@ -53,19 +83,55 @@ func (s *authService) Login(ctx context.Context, request session.LoginRequest) (
 		return session.LoginResponse{}, err
 	}
 
-	user := &user.Table{
+	// Throttling is keyed on the normalised email so case variations share one counter
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+	attemptID, err := s.beginLoginAttempt(ctx, email, request.IPAddress)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	account := &user.Table{
 		Email: request.Email,
 	}
 
-	if err := userRepository.GetAccountByEmail(ctx, user); err != nil {
+	if err := userRepository.GetAccountByEmail(ctx, account); err != nil {
+		if !errors.Is(err, user.ErrUserNotFound) {
+			return session.LoginResponse{}, err
+		}
+
+		compareDummyPassword(request.Password)
+		return session.LoginResponse{}, s.failLogin(ctx, email, request, nil)
+	}
+
+	// Google-only accounts have no password and fail the same way as a wrong one
+	if account.Password == "" {
+		compareDummyPassword(request.Password)
+		return session.LoginResponse{}, s.failLogin(ctx, email, request, &account.ID)
+	}
+
+	if err := bcrypt.ComparePassword(account.Password, request.Password); err != nil {
+		return session.LoginResponse{}, s.failLogin(ctx, email, request, &account.ID)
+	}
+
+	return s.finishPasswordLogin(ctx, attemptID, account, request.UserAgent, request.IPAddress)
+}
+
+// issueSession starts a new session family for the user and returns its access and refresh tokens
+func (s *authService) issueSession(ctx context.Context, user *user.Table, userAgent, ipAddress string, mfa bool) (response session.LoginResponse, err error) {
+	sessionID, err := uuid.NewV7()
+	if err != nil {
 		return session.LoginResponse{}, err
 	}
 
-	if err := bcrypt.ComparePassword(user.Password, request.Password); err != nil {
+	// The family ID is the access token's sid, so it is drawn on its own rather than reusing the session ID
+	familyID := uuid.New()
+
+	refreshToken, tokenHash, err := newRefreshToken()
+	if err != nil {
 		return session.LoginResponse{}, err
 	}
 
-	token, err := s.jwt.Encode(user)
+	token, err := s.jwt.Encode(user, familyID, mfa)
 	if err != nil {
 		return session.LoginResponse{}, err
 	}
@@ -84,14 +150,15 @@ func (s *authService) Login(ctx context.Context, request session.LoginRequest) (
 		}
 	}()
 
-	sessionID, err := uuid.NewV7()
-	if err != nil {
-		return session.LoginResponse{}, err
-	}
-
 	newSession := session.Table{
-		ID:     sessionID,
-		UserID: user.ID,
+		ID:        sessionID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(session.RefreshTokenTTL),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		MFA:       mfa,
 	}
 
 	err = sessionRepository.CreateSession(ctx, newSession)
@@ -108,7 +175,7 @@ func (s *authService) Login(ctx context.Context, request session.LoginRequest) (
 	}
 
 	if len(*sessions) > 3 {
-		err := sessionRepository.DeleteOldestSessionByUserID(ctx, newSession)
+		err := sessionRepository.RevokeOldestSessionFamily(ctx, newSession)
 		if err != nil {
 			return session.LoginResponse{}, err
 		}
@@ -120,6 +187,6 @@ func (s *authService) Login(ctx context.Context, request session.LoginRequest) (
 
 	return session.LoginResponse{
 		AccessToken:  token,
-		RefreshToken: base64.StdEncoding.EncodeToString([]byte(newSession.ID.String())),
+		RefreshToken: refreshToken,
 	}, nil
 }
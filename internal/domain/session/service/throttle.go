@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// freeLoginFailures is how many failures in a row are allowed without any delay
+	freeLoginFailures = 2
+
+	// loginDelayBase is the first enforced delay, it doubles with every further failure
+	loginDelayBase = time.Second
+	loginDelayMax  = 30 * time.Second
+
+	// loginAttemptRetention is how long attempts are kept before the prune job removes them
+	loginAttemptRetention = 24 * time.Hour
+)
+
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
+// compareDummyPassword spends as long as a real password check so response times don't reveal unknown emails
+func compareDummyPassword(password string) {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = bcrypt.EncryptPassword(uuid.NewString())
+	})
+
+	_ = bcrypt.ComparePassword(dummyPasswordHash, password)
+}
+
+// loginDelay is how long after the last failure the next attempt is accepted
+func loginDelay(failures int) time.Duration {
+	if failures <= freeLoginFailures {
+		return 0
+	}
+
+	delay := loginDelayBase << (failures - freeLoginFailures - 1)
+	if delay <= 0 || delay > loginDelayMax {
+		return loginDelayMax
+	}
+
+	return delay
+}
+
+// beginLoginAttempt rejects locked accounts and IP addresses, and attempts made before the retry delay passed.
+// The allowed attempt is recorded as a failure in the same transaction that checked the limits, so concurrent
+// guesses see each other; it becomes a success through recordLoginSuccess once the credentials check out.
+func (s *authService) beginLoginAttempt(ctx context.Context, email, ipAddress string) (attemptID int64, err error) {
+	sessionRepository, err := s.sessionRepository.NewClient(true)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = sessionRepository.Rollback()
+		}
+	}()
+
+	if err = sessionRepository.LockLoginSubjects(ctx, email, ipAddress); err != nil {
+		return 0, err
+	}
+
+	subjects := map[session.LockoutScope]string{
+		session.LockoutScopeAccount: email,
+		session.LockoutScopeIP:      ipAddress,
+	}
+	for scope, subject := range subjects {
+		lockoutErr := sessionRepository.GetActiveLockout(ctx, scope, subject, new(session.Lockout))
+		if lockoutErr == nil {
+			err = session.ErrLoginLocked
+			return 0, err
+		}
+		if !errors.Is(lockoutErr, session.ErrLockoutNotFound) {
+			err = lockoutErr
+			return 0, err
+		}
+	}
+
+	since := time.Now().Add(-s.throttle.Window)
+
+	accountFailures := session.LoginFailures{}
+	if err = sessionRepository.GetAccountLoginFailures(ctx, email, since, &accountFailures); err != nil {
+		return 0, err
+	}
+
+	ipFailures := session.LoginFailures{}
+	if err = sessionRepository.GetIPLoginFailures(ctx, ipAddress, since, &ipFailures); err != nil {
+		return 0, err
+	}
+
+	for _, failures := range []session.LoginFailures{accountFailures, ipFailures} {
+		if failures.LastFailedAt != nil && time.Since(*failures.LastFailedAt) < loginDelay(failures.Count) {
+			err = session.ErrLoginThrottled
+			return 0, err
+		}
+	}
+
+	attempt := session.LoginAttempt{
+		Email:     email,
+		IPAddress: ipAddress,
+		Succeeded: false,
+	}
+	if err = sessionRepository.RecordLoginAttempt(ctx, &attempt); err != nil {
+		return 0, err
+	}
+
+	if err = sessionRepository.Commit(); err != nil {
+		return 0, err
+	}
+
+	return attempt.ID, nil
+}
+
+// failLogin locks the account or IP address once the failure begun by beginLoginAttempt hits the limit
+// and returns the generic credentials error so callers can't tell unknown emails apart
+func (s *authService) failLogin(ctx context.Context, email string, request session.LoginRequest, userID *uuid.UUID) error {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-s.throttle.Window)
+
+	accountFailures := session.LoginFailures{}
+	if err := sessionRepository.GetAccountLoginFailures(ctx, email, since, &accountFailures); err != nil {
+		return err
+	}
+	if accountFailures.Count >= s.throttle.MaxAccountFailures {
+		if err := s.lockLogin(ctx, session.LockoutScopeAccount, email, userID, accountFailures.Count, request); err != nil {
+			return err
+		}
+	}
+
+	ipFailures := session.LoginFailures{}
+	if err := sessionRepository.GetIPLoginFailures(ctx, request.IPAddress, since, &ipFailures); err != nil {
+		return err
+	}
+	if ipFailures.Count >= s.throttle.MaxIPFailures {
+		if err := s.lockLogin(ctx, session.LockoutScopeIP, request.IPAddress, nil, ipFailures.Count, request); err != nil {
+			return err
+		}
+	}
+
+	return session.ErrInvalidCredentials
+}
+
+// lockLogin blocks the subject for the lockout duration and keeps the event for auditing
+func (s *authService) lockLogin(ctx context.Context, scope session.LockoutScope, subject string, userID *uuid.UUID, failures int, request session.LoginRequest) error {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	lockoutID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	lockout := session.Lockout{
+		ID:             lockoutID,
+		Scope:          scope,
+		Subject:        subject,
+		UserID:         userID,
+		FailedAttempts: failures,
+		LockedUntil:    time.Now().Add(s.throttle.LockoutDuration),
+		IPAddress:      request.IPAddress,
+		UserAgent:      request.UserAgent,
+	}
+
+	if err := sessionRepository.CreateLockout(ctx, lockout); err != nil {
+		return err
+	}
+
+	log.Warn().
+		Str("scope", string(scope)).
+		Str("ip_address", request.IPAddress).
+		Int("failed_attempts", failures).
+		Time("locked_until", lockout.LockedUntil).
+		Msg("login locked after repeated failures")
+
+	return nil
+}
+
+// recordLoginSuccess turns the attempt into a success, which resets the account failure count.
+// A failure to record it doesn't block the login.
+func (s *authService) recordLoginSuccess(ctx context.Context, attemptID int64) {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err == nil {
+		err = sessionRepository.MarkLoginAttemptSucceeded(ctx, attemptID)
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to record successful login")
+	}
+}
+
+// releaseLoginAttempt drops an attempt that neither failed nor succeeded, a failure to drop it only leaves it counted
+func (s *authService) releaseLoginAttempt(ctx context.Context, attemptID int64) {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err == nil {
+		err = sessionRepository.DeleteLoginAttempt(ctx, attemptID)
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to release login attempt")
+	}
+}
+
+// finishPasswordLogin settles a login whose password checked out, it only counts as a success
+// once the session is issued, a pending second factor is throttled on its own in VerifyMFA
+func (s *authService) finishPasswordLogin(ctx context.Context, attemptID int64, account *user.Table, userAgent, ipAddress string) (session.LoginResponse, error) {
+	response, err := s.completeLogin(ctx, account, userAgent, ipAddress)
+	if err != nil {
+		return session.LoginResponse{}, err
+	}
+
+	if response.MFARequired {
+		s.releaseLoginAttempt(ctx, attemptID)
+	} else {
+		s.recordLoginSuccess(ctx, attemptID)
+	}
+
+	return response, nil
+}
+
+// PruneLoginAttempts removes attempts older than any throttling window, run by the scheduler
+func (s *authService) PruneLoginAttempts(ctx context.Context) error {
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return sessionRepository.DeleteLoginAttemptsBefore(ctx, time.Now().Add(-loginAttemptRetention))
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/session"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/infra/mailer"
+	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/google/uuid"
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+// VerifyEmail consumes an email verification token and marks the account as verified
+func (s *authService) VerifyEmail(ctx context.Context, request session.VerifyEmailRequest) (err error) {
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	token := &user.Token{
+		Purpose:   user.TokenPurposeEmailVerification,
+		TokenHash: hashUserToken(request.Token),
+	}
+	if err = userRepository.ConsumeToken(ctx, token); err != nil {
+		return err
+	}
+
+	if err = userRepository.MarkEmailVerified(ctx, user.Table{ID: token.UserID}); err != nil {
+		return err
+	}
+
+	return userRepository.Commit()
+}
+
+// ResendVerification replaces any outstanding verification token with a new one
+func (s *authService) ResendVerification(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	account := &user.Table{ID: id}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return err
+	}
+
+	if account.VerifiedAt != nil {
+		return user.ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(ctx, *account)
+}
+
+// ForgotPassword emails a password reset link. It succeeds for unknown emails too,
+// so the endpoint can't be used to find out which emails are registered.
+func (s *authService) ForgotPassword(ctx context.Context, request session.ForgotPasswordRequest) error {
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	account := &user.Table{Email: request.Email}
+	if err := userRepository.GetAccountByEmail(ctx, account); err != nil {
+		if err == user.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	rawToken, err := s.issueUserToken(ctx, account.ID, user.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      account.Email,
+		Subject: "Reset your Vistara password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your Vistara account. "+
+				"Use the link below within the next hour to choose a new one:\n\n%s/reset-password?token=%s\n\n"+
+				"If it wasn't you, you can safely ignore this email.",
+			account.FullName, s.appURL, rawToken,
+		),
+	})
+}
+
+// ResetPassword sets a new password from a reset token and signs the user out everywhere
+func (s *authService) ResetPassword(ctx context.Context, request session.ResetPasswordRequest) (err error) {
+	hashedPassword, err := bcrypt.EncryptPassword(request.Password)
+	if err != nil {
+		return err
+	}
+
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	token := &user.Token{
+		Purpose:   user.TokenPurposePasswordReset,
+		TokenHash: hashUserToken(request.Token),
+	}
+	if err = userRepository.ConsumeToken(ctx, token); err != nil {
+		return err
+	}
+
+	if err = userRepository.UpdatePassword(ctx, user.Table{ID: token.UserID, Password: hashedPassword}); err != nil {
+		return err
+	}
+
+	if err = userRepository.InvalidateTokens(ctx, *token); err != nil {
+		return err
+	}
+
+	// Receiving the reset email proves the address as well
+	if err = userRepository.MarkEmailVerified(ctx, user.Table{ID: token.UserID}); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, userRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(token.UserID.String()),
+		Action:     audit.ActionPasswordReset,
+		TargetType: audit.TargetUser,
+		TargetID:   token.UserID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = userRepository.Commit(); err != nil {
+		return err
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return sessionRepository.RevokeSessionsByUserID(ctx, token.UserID, uuid.Nil)
+}
+
+func (s *authService) sendVerificationEmail(ctx context.Context, account user.Table) error {
+	rawToken, err := s.issueUserToken(ctx, account.ID, user.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      account.Email,
+		Subject: "Verify your Vistara email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWelcome to Vistara! Confirm your email address to start booking tour guides:\n\n%s/verify-email?token=%s\n\n"+
+				"The link expires in 24 hours.",
+			account.FullName, s.appURL, rawToken,
+		),
+	})
+}
+
+// issueUserToken invalidates the user's outstanding tokens for purpose and stores the hash of a new one.
+// Only the returned raw token can be used; it is never persisted.
+func (s *authService) issueUserToken(ctx context.Context, userID uuid.UUID, purpose user.TokenPurpose, ttl time.Duration) (rawToken string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	rawToken = base64.RawURLEncoding.EncodeToString(secret)
+
+	tokenID, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	token := user.Token{
+		ID:        tokenID,
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(rawToken),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	if err = userRepository.InvalidateTokens(ctx, token); err != nil {
+		return "", err
+	}
+
+	if err = userRepository.CreateToken(ctx, token); err != nil {
+		return "", err
+	}
+
+	if err = userRepository.Commit(); err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+func hashUserToken(rawToken string) string {
+	digest := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,49 @@
+package subscription
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestCheckout represents a request to buy a subscription plan
+type RequestCheckout struct {
+	PlanCode string    `json:"plan_code" validate:"required"`
+	UserID   uuid.UUID `json:"-"`
+}
+
+// ResponseCheckout contains the Snap token and redirect URL of a new order
+type ResponseCheckout struct {
+	OrderID    string    `json:"order_id"`
+	Token      string    `json:"token"`
+	PaymentURL string    `json:"payment_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ResponsePlan represents a plan in API responses
+type ResponsePlan struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Price        int64  `json:"price"`
+	DurationDays int    `json:"duration_days"`
+}
+
+// ResponseOrder represents an order in API responses
+type ResponseOrder struct {
+	OrderID     string      `json:"order_id"`
+	Amount      int64       `json:"amount"`
+	Status      OrderStatus `json:"status"`
+	PaymentURL  string      `json:"payment_url,omitempty"`
+	PaidAt      *time.Time  `json:"paid_at,omitempty"`
+	PeriodStart *time.Time  `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time  `json:"period_end,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// ResponseSubscription is the current premium state of a user along with their orders
+type ResponseSubscription struct {
+	IsPremium bool            `json:"is_premium"`
+	ExpiredAt *time.Time      `json:"expired_at"`
+	Orders    []ResponseOrder `json:"orders"`
+}
@@ -0,0 +1,44 @@
+package subscription
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Plan represents a premium subscription plan that can be purchased
+type Plan struct {
+	ID           uuid.UUID `db:"id"`
+	Code         string    `db:"code"`
+	Name         string    `db:"name"`
+	Description  string    `db:"description"`
+	Price        int64     `db:"price"`
+	DurationDays int       `db:"duration_days"`
+	IsActive     bool      `db:"is_active"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// Order represents a single purchase of a plan, its amount and duration are copied from the plan at checkout
+type Order struct {
+	ID           uuid.UUID   `db:"id"`
+	UserID       uuid.UUID   `db:"user_id"`
+	PlanID       uuid.UUID   `db:"plan_id"`
+	Amount       int64       `db:"amount"`
+	DurationDays int         `db:"duration_days"`
+	Status       OrderStatus `db:"status"`
+	PaymentURL   string      `db:"payment_url"`
+	ExpiresAt    time.Time   `db:"expires_at"`
+	PaidAt       *time.Time  `db:"paid_at"`
+	PeriodStart  *time.Time  `db:"period_start"`
+	PeriodEnd    *time.Time  `db:"period_end"`
+	CreatedAt    time.Time   `db:"created_at"`
+	UpdatedAt    time.Time   `db:"updated_at"`
+}
+
+// Premium is the premium state stored on the user
+type Premium struct {
+	UserID    uuid.UUID  `db:"id"`
+	IsPremium bool       `db:"is_premium"`
+	ExpiredAt *time.Time `db:"expired_at"`
+}
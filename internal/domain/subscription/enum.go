@@ -0,0 +1,38 @@
+package subscription
+
+import "github.com/google/uuid"
+
+// OrderIDPrefix marks Midtrans order IDs that belong to subscription orders
+const OrderIDPrefix = "SUB-"
+
+// MidtransOrderID returns the Midtrans order ID of a subscription order
+func MidtransOrderID(orderID uuid.UUID) string {
+	return OrderIDPrefix + orderID.String()
+}
+
+// OrderStatus represents the lifecycle state of a subscription order
+type OrderStatus string
+
+const (
+	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusExpired        OrderStatus = "expired"
+	OrderStatusRefunded       OrderStatus = "refunded"
+	OrderStatusFlagged        OrderStatus = "flagged" // paid with the wrong amount, needs a manual review
+)
+
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPendingPayment: {OrderStatusPaid, OrderStatusCancelled, OrderStatusExpired, OrderStatusFlagged},
+	OrderStatusPaid:           {OrderStatusRefunded},
+}
+
+// CanTransitionTo reports whether an order may move from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package subscription
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+)
+
+var (
+	ErrPlanNotFound             = cerr.New(fiber.ErrNotFound.Code, "subscription plan not found", errors.New("subscription plan not found"))
+	ErrOrderNotFound            = cerr.New(fiber.ErrNotFound.Code, "subscription order not found", errors.New("subscription order not found"))
+	ErrInvalidPaymentSignature  = cerr.New(fiber.ErrForbidden.Code, "invalid payment signature", errors.New("signature key mismatch"))
+	ErrPaymentStatusUnavailable = cerr.New(fiber.ErrBadGateway.Code, "failed to verify payment status", errors.New("midtrans status check failed"))
+	ErrCheckoutFailed           = cerr.New(fiber.ErrBadGateway.Code, "failed to create payment", errors.New("snap transaction creation failed"))
+)
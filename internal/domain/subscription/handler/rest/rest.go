@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription/service"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+)
+
+// SubscriptionHandler handles HTTP requests for premium subscriptions
+type SubscriptionHandler struct {
+	service    service.SubscriptionServiceInterface
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+// New creates a new SubscriptionHandler instance
+func New(service service.SubscriptionServiceInterface, validator *validator.Validate, middleware *middleware.Middleware) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		service:    service,
+		validator:  validator,
+		middleware: middleware,
+	}
+}
+
+// Mount registers all subscription routes
+func (h *SubscriptionHandler) Mount(router fiber.Router) {
+	subscriptionGroup := router.Group("/subscriptions")
+
+	// Plans are public so they can be shown before sign-in
+	subscriptionGroup.Get("/plans", h.GetPlans)
+
+	authentication := h.middleware.Authentication()
+	subscriptionGroup.Get("/me", authentication, h.GetSubscription)
+	subscriptionGroup.Post("/checkout", authentication, h.Checkout)
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	"github.com/vistara-studio/vistara-be/pkg/cerr"
+)
+
+// GetPlans handles GET /subscriptions/plans
+func (h *SubscriptionHandler) GetPlans(ctx *fiber.Ctx) error {
+	plans, err := h.service.GetPlans(ctx.Context())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to get subscription plans",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Subscription plans retrieved successfully",
+		"data":    plans,
+	})
+}
+
+// GetSubscription handles GET /subscriptions/me
+func (h *SubscriptionHandler) GetSubscription(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	response, err := h.service.GetSubscription(ctx.Context(), userID)
+	if err != nil {
+		return handleServiceError(ctx, err, "Failed to get subscription")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Subscription retrieved successfully",
+		"data":    response,
+	})
+}
+
+// Checkout handles POST /subscriptions/checkout
+func (h *SubscriptionHandler) Checkout(ctx *fiber.Ctx) error {
+	var request subscription.RequestCheckout
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": "Failed to parse request body",
+		})
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"message": err.Error(),
+		})
+	}
+
+	userIDStr, _ := ctx.Locals("user_id").(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Invalid user",
+			"message": "Failed to identify the authenticated user",
+		})
+	}
+	request.UserID = userID
+
+	response, err := h.service.Checkout(ctx.Context(), request)
+	if err != nil {
+		return handleServiceError(ctx, err, "Failed to create checkout")
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Checkout created successfully",
+		"data":    response,
+	})
+}
+
+// handleServiceError maps service errors onto the JSON error response
+func handleServiceError(ctx *fiber.Ctx, err error, message string) error {
+	var customErr *cerr.CustomError
+	if errors.As(err, &customErr) {
+		return ctx.Status(customErr.Code).JSON(fiber.Map{
+			"error":   customErr.Message,
+			"message": message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   message,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+)
+
+var (
+	ErrFailedToCommitTransaction   = errors.New("failed to commit transaction")
+	ErrFailedToRollbackTransaction = errors.New("failed to rollback transaction")
+)
+
+// Repository represents the main repository struct
+type Repository struct {
+	db *sqlx.DB
+}
+
+// RepositoryInterface defines the contract for repository creation
+type RepositoryInterface interface {
+	NewClient(withTransaction bool) (SubscriptionRepositoryInterface, error)
+}
+
+// subscriptionRepository implements the subscription repository with database connection
+type subscriptionRepository struct {
+	queryExecutor namedExtension
+}
+
+// SubscriptionRepositoryInterface defines all plan, order and premium operations
+type SubscriptionRepositoryInterface interface {
+	// Transaction management
+	Commit() error
+	Rollback() error
+
+	// Plan operations
+	GetActivePlans(ctx context.Context, out *[]subscription.Plan) error
+	GetActivePlanByCode(ctx context.Context, plan *subscription.Plan) error
+
+	// Order operations
+	CreateOrder(ctx context.Context, order *subscription.Order) error
+	UpdateOrderPaymentURL(ctx context.Context, order *subscription.Order) error
+	GetOrderByIDForUpdate(ctx context.Context, order *subscription.Order) error
+	UpdateOrderStatus(ctx context.Context, order *subscription.Order) error
+	GetOrdersByUserID(ctx context.Context, userID uuid.UUID, out *[]subscription.Order) error
+
+	// Premium operations
+	GetPremiumForUpdate(ctx context.Context, premium *subscription.Premium) error
+	GetPremium(ctx context.Context, premium *subscription.Premium) error
+	UpdatePremium(ctx context.Context, premium *subscription.Premium) error
+	DowngradeExpiredPremium(ctx context.Context) (int64, error)
+}
+
+// namedExtension extends sqlx with named query capabilities
+type namedExtension interface {
+	sqlx.ExtContext
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// New creates a new repository instance
+func New(database *sqlx.DB) RepositoryInterface {
+	return &Repository{db: database}
+}
+
+// NewClient creates a new subscription repository client with optional transaction support
+func (r *Repository) NewClient(withTransaction bool) (SubscriptionRepositoryInterface, error) {
+	var queryExecutor namedExtension
+
+	queryExecutor = r.db
+	if withTransaction {
+		transaction, err := r.db.Beginx()
+		if err != nil {
+			return nil, err
+		}
+		queryExecutor = transaction
+	}
+
+	return &subscriptionRepository{queryExecutor: queryExecutor}, nil
+}
+
+// Commit commits the transaction if one exists
+func (sr *subscriptionRepository) Commit() error {
+	switch executor := sr.queryExecutor.(type) {
+	case *sqlx.Tx:
+		return executor.Commit()
+	case *sqlx.DB:
+		return nil // No transaction to commit
+	default:
+		return ErrFailedToCommitTransaction
+	}
+}
+
+// Rollback rolls back the transaction if one exists
+func (sr *subscriptionRepository) Rollback() error {
+	switch executor := sr.queryExecutor.(type) {
+	case *sqlx.Tx:
+		return executor.Rollback()
+	case *sqlx.DB:
+		return nil // No transaction to rollback
+	default:
+		return ErrFailedToRollbackTransaction
+	}
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+)
+
+const orderColumns = `id, user_id, plan_id, amount, duration_days, status, COALESCE(payment_url, '') AS payment_url,
+	expires_at, paid_at, period_start, period_end, created_at, updated_at`
+
+// GetActivePlans retrieves every plan that can currently be purchased, cheapest first
+func (sr *subscriptionRepository) GetActivePlans(ctx context.Context, out *[]subscription.Plan) error {
+	query := `SELECT id, code, name, description, price, duration_days, is_active, created_at, updated_at
+	FROM subscription_plans
+	WHERE is_active
+	ORDER BY price ASC`
+
+	return sqlx.SelectContext(ctx, sr.queryExecutor, out, query)
+}
+
+// GetActivePlanByCode retrieves a purchasable plan by its code
+func (sr *subscriptionRepository) GetActivePlanByCode(ctx context.Context, plan *subscription.Plan) error {
+	query := `SELECT id, code, name, description, price, duration_days, is_active, created_at, updated_at
+	FROM subscription_plans
+	WHERE code = $1 AND is_active`
+
+	if err := sqlx.GetContext(ctx, sr.queryExecutor, plan, query, plan.Code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return subscription.ErrPlanNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CreateOrder inserts a new pending subscription order
+func (sr *subscriptionRepository) CreateOrder(ctx context.Context, order *subscription.Order) error {
+	query := `INSERT INTO subscription_orders (
+		id, user_id, plan_id, amount, duration_days, status, expires_at
+	) VALUES (
+		:id, :user_id, :plan_id, :amount, :duration_days, :status, :expires_at
+	)`
+
+	_, err := sr.queryExecutor.NamedExecContext(ctx, query, order)
+	return err
+}
+
+// UpdateOrderPaymentURL stores the Snap redirect URL of an order
+func (sr *subscriptionRepository) UpdateOrderPaymentURL(ctx context.Context, order *subscription.Order) error {
+	query := `UPDATE subscription_orders SET payment_url = :payment_url, updated_at = NOW() WHERE id = :id`
+
+	_, err := sr.queryExecutor.NamedExecContext(ctx, query, order)
+	return err
+}
+
+// GetOrderByIDForUpdate retrieves an order and locks it until the transaction ends
+func (sr *subscriptionRepository) GetOrderByIDForUpdate(ctx context.Context, order *subscription.Order) error {
+	query := `SELECT ` + orderColumns + `
+	FROM subscription_orders
+	WHERE id = $1
+	FOR UPDATE`
+
+	if err := sqlx.GetContext(ctx, sr.queryExecutor, order, query, order.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return subscription.ErrOrderNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOrderStatus saves the status and billing period of an order
+func (sr *subscriptionRepository) UpdateOrderStatus(ctx context.Context, order *subscription.Order) error {
+	query := `UPDATE subscription_orders
+	SET status = :status, paid_at = :paid_at, period_start = :period_start, period_end = :period_end, updated_at = NOW()
+	WHERE id = :id`
+
+	_, err := sr.queryExecutor.NamedExecContext(ctx, query, order)
+	return err
+}
+
+// GetOrdersByUserID retrieves the orders of a user, newest first
+func (sr *subscriptionRepository) GetOrdersByUserID(ctx context.Context, userID uuid.UUID, out *[]subscription.Order) error {
+	query := `SELECT ` + orderColumns + `
+	FROM subscription_orders
+	WHERE user_id = $1
+	ORDER BY created_at DESC`
+
+	return sqlx.SelectContext(ctx, sr.queryExecutor, out, query, userID)
+}
+
+// GetPremiumForUpdate retrieves the premium state of a user and locks the user row,
+// so concurrent payments extend the subscription one after another
+func (sr *subscriptionRepository) GetPremiumForUpdate(ctx context.Context, premium *subscription.Premium) error {
+	query := `SELECT id, is_premium, expired_at FROM users WHERE id = $1 FOR UPDATE`
+
+	if err := sqlx.GetContext(ctx, sr.queryExecutor, premium, query, premium.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetPremium retrieves the premium state of a user
+func (sr *subscriptionRepository) GetPremium(ctx context.Context, premium *subscription.Premium) error {
+	query := `SELECT id, is_premium, expired_at FROM users WHERE id = $1`
+
+	if err := sqlx.GetContext(ctx, sr.queryExecutor, premium, query, premium.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePremium saves the premium state of a user
+func (sr *subscriptionRepository) UpdatePremium(ctx context.Context, premium *subscription.Premium) error {
+	query := `UPDATE users SET is_premium = :is_premium, expired_at = :expired_at, updated_at = NOW() WHERE id = :id`
+
+	_, err := sr.queryExecutor.NamedExecContext(ctx, query, premium)
+	return err
+}
+
+// DowngradeExpiredPremium clears the premium flag of every user whose subscription has run out,
+// premium granted without an expiry is left alone
+func (sr *subscriptionRepository) DowngradeExpiredPremium(ctx context.Context) (int64, error) {
+	query := `UPDATE users
+	SET is_premium = FALSE, updated_at = NOW()
+	WHERE is_premium AND expired_at IS NOT NULL AND expired_at <= NOW()`
+
+	result, err := sr.queryExecutor.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
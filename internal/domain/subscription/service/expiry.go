@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DowngradeExpiredPremium removes premium from users whose subscription period has ended
+func (s *subscriptionService) DowngradeExpiredPremium(ctx context.Context) error {
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	downgraded, err := repository.DowngradeExpiredPremium(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to downgrade expired premium users: %w", err)
+	}
+
+	if downgraded > 0 {
+		log.Info().Int64("users", downgraded).Msg("downgraded expired premium users")
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+)
+
+// orderStatusFromMidtrans maps a Midtrans transaction and fraud status onto an order status.
+// An empty result means the notification does not settle the order yet.
+func orderStatusFromMidtrans(transactionStatus, fraudStatus string) subscription.OrderStatus {
+	switch transactionStatus {
+	case "capture":
+		if fraudStatus == "accept" {
+			return subscription.OrderStatusPaid
+		}
+		if fraudStatus == "deny" {
+			return subscription.OrderStatusCancelled
+		}
+		return ""
+	case "settlement":
+		return subscription.OrderStatusPaid
+	case "deny", "cancel":
+		return subscription.OrderStatusCancelled
+	case "expire":
+		return subscription.OrderStatusExpired
+	case "refund":
+		return subscription.OrderStatusRefunded
+	default:
+		return ""
+	}
+}
+
+// HandlePaymentNotification processes a Midtrans HTTP notification for a subscription order.
+// A paid order extends the user's premium period; repeated notifications are no-ops.
+// A payment for the wrong amount flags the order instead of activating it.
+func (s *subscriptionService) HandlePaymentNotification(ctx context.Context, request payment.Notification) (err error) {
+	if !payment.VerifySignature(s.coreAPI.ServerKey, request.OrderID, request.StatusCode, request.GrossAmount, request.SignatureKey) {
+		return subscription.ErrInvalidPaymentSignature
+	}
+
+	orderID, err := uuid.Parse(strings.TrimPrefix(request.OrderID, subscription.OrderIDPrefix))
+	if err != nil {
+		return subscription.ErrOrderNotFound
+	}
+
+	// Never trust the notification body alone, ask Midtrans for the actual status
+	transaction, midtransErr := s.coreAPI.CheckTransaction(request.OrderID)
+	if midtransErr != nil || transaction.OrderID != request.OrderID {
+		return subscription.ErrPaymentStatusUnavailable
+	}
+
+	nextStatus := orderStatusFromMidtrans(transaction.TransactionStatus, transaction.FraudStatus)
+	if nextStatus == "" {
+		return nil
+	}
+
+	repository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = repository.Rollback()
+		}
+	}()
+
+	order := &subscription.Order{ID: orderID}
+	if err = repository.GetOrderByIDForUpdate(ctx, order); err != nil {
+		return err
+	}
+
+	if !order.Status.CanTransitionTo(nextStatus) {
+		// Already processed or superseded by a later notification
+		return repository.Commit()
+	}
+
+	if nextStatus == subscription.OrderStatusPaid {
+		grossAmount, parseErr := strconv.ParseFloat(transaction.GrossAmount, 64)
+		if parseErr != nil || int64(grossAmount) != order.Amount {
+			// Rejecting the notification would only make Midtrans retry it, so park the order for review
+			log.Error().
+				Str("order_id", order.ID.String()).
+				Str("gross_amount", transaction.GrossAmount).
+				Int64("amount", order.Amount).
+				Msg("subscription payment amount does not match the order, order flagged")
+			nextStatus = subscription.OrderStatusFlagged
+		}
+	}
+
+	switch nextStatus {
+	case subscription.OrderStatusPaid:
+		err = activateOrder(ctx, repository, order)
+	case subscription.OrderStatusRefunded:
+		err = refundOrder(ctx, repository, order)
+	default:
+		order.Status = nextStatus
+		err = repository.UpdateOrderStatus(ctx, order)
+	}
+	if err != nil {
+		return err
+	}
+
+	return repository.Commit()
+}
+
+// activateOrder marks an order paid and adds its duration to the user's premium period.
+// A renewal bought before the current period ends starts when that period ends.
+func activateOrder(ctx context.Context, repository repository.SubscriptionRepositoryInterface, order *subscription.Order) error {
+	premium := &subscription.Premium{UserID: order.UserID}
+	if err := repository.GetPremiumForUpdate(ctx, premium); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	periodStart := now
+	if premium.IsPremium && premium.ExpiredAt != nil && premium.ExpiredAt.After(now) {
+		periodStart = *premium.ExpiredAt
+	}
+	periodEnd := periodStart.AddDate(0, 0, order.DurationDays)
+
+	order.Status = subscription.OrderStatusPaid
+	order.PaidAt = &now
+	order.PeriodStart = &periodStart
+	order.PeriodEnd = &periodEnd
+	if err := repository.UpdateOrderStatus(ctx, order); err != nil {
+		return err
+	}
+
+	premium.IsPremium = true
+	premium.ExpiredAt = &periodEnd
+	return repository.UpdatePremium(ctx, premium)
+}
+
+// refundOrder marks an order refunded and takes its duration back from the user's premium period
+func refundOrder(ctx context.Context, repository repository.SubscriptionRepositoryInterface, order *subscription.Order) error {
+	premium := &subscription.Premium{UserID: order.UserID}
+	if err := repository.GetPremiumForUpdate(ctx, premium); err != nil {
+		return err
+	}
+
+	order.Status = subscription.OrderStatusRefunded
+	if err := repository.UpdateOrderStatus(ctx, order); err != nil {
+		return err
+	}
+
+	if premium.ExpiredAt == nil || order.PeriodStart == nil || order.PeriodEnd == nil {
+		return nil
+	}
+
+	expiredAt := premium.ExpiredAt.Add(-order.PeriodEnd.Sub(*order.PeriodStart))
+	premium.ExpiredAt = &expiredAt
+	premium.IsPremium = expiredAt.After(time.Now())
+	return repository.UpdatePremium(ctx, premium)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/payment"
+)
+
+// subscriptionService implements the subscription service
+type subscriptionService struct {
+	repository repository.RepositoryInterface
+	snapClient snap.Client
+	coreAPI    coreapi.Client
+}
+
+// SubscriptionServiceInterface defines the contract for premium subscription operations
+type SubscriptionServiceInterface interface {
+	GetPlans(ctx context.Context) ([]subscription.ResponsePlan, error)
+	Checkout(ctx context.Context, request subscription.RequestCheckout) (subscription.ResponseCheckout, error)
+	GetSubscription(ctx context.Context, userID string) (subscription.ResponseSubscription, error)
+	HandlePaymentNotification(ctx context.Context, request payment.Notification) error
+	DowngradeExpiredPremium(ctx context.Context) error
+}
+
+// New creates a new subscription service instance
+func New(repo repository.RepositoryInterface, snapClient snap.Client, coreAPI coreapi.Client) SubscriptionServiceInterface {
+	return &subscriptionService{
+		repository: repo,
+		snapClient: snapClient,
+		coreAPI:    coreAPI,
+	}
+}
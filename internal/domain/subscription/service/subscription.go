@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/vistara-studio/vistara-be/internal/domain/subscription"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+)
+
+// checkoutExpiry is how long a subscription order can be paid, matching the Snap expiry
+const checkoutExpiry = 24 * time.Hour
+
+// GetPlans retrieves every plan that can currently be purchased
+func (s *subscriptionService) GetPlans(ctx context.Context) ([]subscription.ResponsePlan, error) {
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []subscription.Plan
+	if err := repository.GetActivePlans(ctx, &plans); err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+
+	response := make([]subscription.ResponsePlan, len(plans))
+	for i, plan := range plans {
+		response[i] = subscription.ResponsePlan{
+			Code:         plan.Code,
+			Name:         plan.Name,
+			Description:  plan.Description,
+			Price:        plan.Price,
+			DurationDays: plan.DurationDays,
+		}
+	}
+
+	return response, nil
+}
+
+// Checkout creates a pending order for a plan and returns its Midtrans Snap payment page
+func (s *subscriptionService) Checkout(ctx context.Context, request subscription.RequestCheckout) (subscription.ResponseCheckout, error) {
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return subscription.ResponseCheckout{}, err
+	}
+
+	plan := &subscription.Plan{Code: request.PlanCode}
+	if err := repository.GetActivePlanByCode(ctx, plan); err != nil {
+		return subscription.ResponseCheckout{}, err
+	}
+
+	orderID, err := uuid.NewV7()
+	if err != nil {
+		return subscription.ResponseCheckout{}, fmt.Errorf("failed to generate order ID: %w", err)
+	}
+
+	order := &subscription.Order{
+		ID:           orderID,
+		UserID:       request.UserID,
+		PlanID:       plan.ID,
+		Amount:       plan.Price,
+		DurationDays: plan.DurationDays,
+		Status:       subscription.OrderStatusPendingPayment,
+		ExpiresAt:    time.Now().Add(checkoutExpiry),
+	}
+	if err := repository.CreateOrder(ctx, order); err != nil {
+		return subscription.ResponseCheckout{}, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	snapRequest := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  subscription.MidtransOrderID(order.ID),
+			GrossAmt: order.Amount,
+		},
+		Items: &[]midtrans.ItemDetails{{
+			ID:    plan.Code,
+			Name:  plan.Name,
+			Price: plan.Price,
+			Qty:   1,
+		}},
+		EnabledPayments: snap.AllSnapPaymentType,
+		Expiry: &snap.ExpiryDetails{
+			Duration: int64(checkoutExpiry / time.Hour),
+			Unit:     "hours",
+		},
+	}
+
+	snapResponse, snapErr := s.snapClient.CreateTransaction(snapRequest)
+	if snapErr != nil {
+		order.Status = subscription.OrderStatusCancelled
+		if err := repository.UpdateOrderStatus(ctx, order); err != nil {
+			return subscription.ResponseCheckout{}, fmt.Errorf("failed to cancel order %s: %w", order.ID, err)
+		}
+		return subscription.ResponseCheckout{}, subscription.ErrCheckoutFailed
+	}
+
+	order.PaymentURL = snapResponse.RedirectURL
+	if err := repository.UpdateOrderPaymentURL(ctx, order); err != nil {
+		return subscription.ResponseCheckout{}, fmt.Errorf("failed to save payment URL: %w", err)
+	}
+
+	return subscription.ResponseCheckout{
+		OrderID:    subscription.MidtransOrderID(order.ID),
+		Token:      snapResponse.Token,
+		PaymentURL: snapResponse.RedirectURL,
+		ExpiresAt:  order.ExpiresAt,
+	}, nil
+}
+
+// GetSubscription retrieves the premium state and order history of a user
+func (s *subscriptionService) GetSubscription(ctx context.Context, userID string) (subscription.ResponseSubscription, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return subscription.ResponseSubscription{}, user.ErrUserNotFound
+	}
+
+	repository, err := s.repository.NewClient(false)
+	if err != nil {
+		return subscription.ResponseSubscription{}, err
+	}
+
+	premium := &subscription.Premium{UserID: id}
+	if err := repository.GetPremium(ctx, premium); err != nil {
+		return subscription.ResponseSubscription{}, err
+	}
+
+	var orders []subscription.Order
+	if err := repository.GetOrdersByUserID(ctx, id, &orders); err != nil {
+		return subscription.ResponseSubscription{}, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	response := subscription.ResponseSubscription{
+		IsPremium: premium.IsPremium && premium.ExpiredAt != nil && premium.ExpiredAt.After(time.Now()),
+		ExpiredAt: premium.ExpiredAt,
+		Orders:    make([]subscription.ResponseOrder, len(orders)),
+	}
+	for i, order := range orders {
+		response.Orders[i] = subscription.ResponseOrder{
+			OrderID:     subscription.MidtransOrderID(order.ID),
+			Amount:      order.Amount,
+			Status:      order.Status,
+			PaidAt:      order.PaidAt,
+			PeriodStart: order.PeriodStart,
+			PeriodEnd:   order.PeriodEnd,
+			CreatedAt:   order.CreatedAt,
+		}
+		if order.Status == subscription.OrderStatusPendingPayment {
+			response.Orders[i].PaymentURL = order.PaymentURL
+		}
+	}
+
+	return response, nil
+}
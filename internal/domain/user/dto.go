@@ -0,0 +1,74 @@
+package user
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProfileResponse struct {
+	ID               uuid.UUID    `json:"id"`
+	FullName         string       `json:"full_name"`
+	Email            string       `json:"email"`
+	AuthProvider     AuthProvider `json:"auth_provider"`
+	Role             Role         `json:"role"`
+	PhotoUrl         string       `json:"photo_url"`
+	IsPremium        bool         `json:"is_premium"`
+	PremiumExpiredAt *time.Time   `json:"premium_expired_at"`
+	EmailVerified    bool         `json:"email_verified"`
+	HasPassword      bool         `json:"has_password"`
+	GoogleLinked     bool         `json:"google_linked"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+type UpdateProfileRequest struct {
+	FullName *string               `json:"full_name" form:"full_name" validate:"omitempty,min=1,max=100"`
+	Photo    *multipart.FileHeader `json:"-" form:"-"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,nefield=CurrentPassword"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+}
+
+type UpdateRoleRequest struct {
+	Role Role `json:"role" validate:"required"`
+}
+
+// DeleteAccountRequest confirms the deletion, password is only checked for accounts that have one
+type DeleteAccountRequest struct {
+	Password     string `json:"password"`
+	ConfirmEmail string `json:"confirm_email" validate:"required,email"`
+}
+
+// DataArchive is the ZIP archive returned by the personal data export
+type DataArchive struct {
+	FileName string
+	Content  []byte
+}
+
+// NewProfileResponse builds the public view of an account
+func NewProfileResponse(data Table) ProfileResponse {
+	response := ProfileResponse{
+		ID:            data.ID,
+		FullName:      data.FullName,
+		Email:         data.Email,
+		AuthProvider:  data.AuthProvider,
+		Role:          data.Role,
+		PhotoUrl:      data.PhotoUrl,
+		IsPremium:     data.IsPremium,
+		EmailVerified: data.VerifiedAt != nil,
+		HasPassword:   data.Password != "",
+		GoogleLinked:  data.GoogleID != "",
+		CreatedAt:     data.CreatedAt,
+	}
+
+	if !data.ExpiredAt.IsZero() {
+		expiredAt := data.ExpiredAt
+		response.PremiumExpiredAt = &expiredAt
+	}
+
+	return response
+}
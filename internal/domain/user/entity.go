@@ -1,6 +1,7 @@
 package user
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,9 +13,36 @@ type Table struct {
 	Email        string       `db:"email"`
 	Password     string       `db:"password"`
 	AuthProvider AuthProvider `db:"auth_provider"`
+	GoogleID     string       `db:"google_id"`
+	Role         Role         `db:"role"`
 	PhotoUrl     string       `db:"photo_url"`
 	IsPremium    bool         `db:"is_premium"`
 	ExpiredAt    time.Time    `db:"expired_at"`
+	VerifiedAt   *time.Time   `db:"email_verified_at"`
 	CreatedAt    time.Time    `db:"created_at"`
 	UpdatedAt    time.Time    `db:"updated_at"`
 }
+
+type Token struct {
+	ID        uuid.UUID    `db:"id"`
+	UserID    uuid.UUID    `db:"user_id"`
+	Purpose   TokenPurpose `db:"purpose"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    *time.Time   `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// DataExport holds every row the user owns, each field is a JSON document written to its own file in the export archive
+type DataExport struct {
+	Profile            json.RawMessage `db:"profile"`
+	Sessions           json.RawMessage `db:"sessions"`
+	Reviews            json.RawMessage `db:"reviews"`
+	Bookings           json.RawMessage `db:"bookings"`
+	SubscriptionOrders json.RawMessage `db:"subscription_orders"`
+	LoginAttempts      json.RawMessage `db:"login_attempts"`
+	LoginLockouts      json.RawMessage `db:"login_lockouts"`
+	MFA                json.RawMessage `db:"mfa"`
+	Listings           json.RawMessage `db:"listings"`
+	APIKeys            json.RawMessage `db:"api_keys"`
+}
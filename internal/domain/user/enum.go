@@ -6,3 +6,27 @@ const (
 	AuthProviderEmail  AuthProvider = "email"
 	AuthProviderGoogle AuthProvider = "google"
 )
+
+type Role string
+
+const (
+	RoleTourist   Role = "tourist"
+	RoleMerchant  Role = "merchant"
+	RoleTourGuide Role = "tour_guide"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleTourist, RoleMerchant, RoleTourGuide, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+)
@@ -0,0 +1,26 @@
+package user
+
+import "testing"
+
+func TestRoleIsValid(t *testing.T) {
+	tests := []struct {
+		role Role
+		want bool
+	}{
+		{role: RoleTourist, want: true},
+		{role: RoleMerchant, want: true},
+		{role: RoleTourGuide, want: true},
+		{role: RoleAdmin, want: true},
+		{role: "Admin"},
+		{role: "superuser"},
+		{role: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if got := tt.role.IsValid(); got != tt.want {
+				t.Errorf("Role(%q).IsValid() = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+}
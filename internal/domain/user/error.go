@@ -8,6 +8,22 @@ import (
 )
 
 var (
-	ErrEmailAlreadyExists = cerr.New(fiber.ErrConflict.Code, "email has been taken", errors.New("unique email constraint violation"))
-	ErrUserNotFound       = cerr.New(fiber.ErrNotFound.Code, "account not found", errors.New("account not found"))
+	ErrEmailAlreadyExists         = cerr.New(fiber.ErrConflict.Code, "email has been taken", errors.New("unique email constraint violation"))
+	ErrUserNotFound               = cerr.New(fiber.ErrNotFound.Code, "account not found", errors.New("account not found"))
+	ErrGoogleAccountAlreadyLinked = cerr.New(fiber.ErrConflict.Code, "a google account is already linked", errors.New("google account already linked"))
+	ErrInvalidToken               = cerr.New(fiber.ErrBadRequest.Code, "token is invalid or has expired", errors.New("user token not found, used or expired"))
+	ErrEmailNotVerified           = cerr.New(fiber.ErrForbidden.Code, "please verify your email first", errors.New("email is not verified"))
+	ErrEmailAlreadyVerified       = cerr.New(fiber.ErrConflict.Code, "email is already verified", errors.New("email is already verified"))
+	ErrIncorrectPassword          = cerr.New(fiber.ErrBadRequest.Code, "current password is incorrect", errors.New("current password mismatch"))
+	ErrNoPasswordSet              = cerr.New(fiber.ErrBadRequest.Code, "account has no password, use forgot password to set one", errors.New("account has no password"))
+	ErrInvalidPhoto               = cerr.New(fiber.ErrBadRequest.Code, "photo must be a jpeg, png or webp image of at most 2MB", errors.New("invalid profile photo"))
+	ErrInvalidRole                = cerr.New(fiber.ErrBadRequest.Code, "role must be one of tourist, merchant, tour_guide or admin", errors.New("invalid role"))
+	ErrForbiddenRole              = cerr.New(fiber.ErrForbidden.Code, "you don't have permission to access this resource", errors.New("role not allowed"))
+	ErrMFARequired                = cerr.New(fiber.ErrForbidden.Code, "two-factor authentication is required for your role, set it up at /api/auth/mfa/setup and sign in again", errors.New("role requires an mfa verified session"))
+	ErrCannotChangeOwnRole        = cerr.New(fiber.ErrBadRequest.Code, "you can't change your own role", errors.New("admin tried to change own role"))
+	ErrPhotoUploadFailed          = cerr.New(fiber.ErrBadGateway.Code, "failed to upload photo", errors.New("profile photo upload failed"))
+	ErrPasswordRequired           = cerr.New(fiber.ErrBadRequest.Code, "password is required to delete your account", errors.New("account deletion without password"))
+	ErrEmailConfirmationMismatch  = cerr.New(fiber.ErrBadRequest.Code, "confirm_email doesn't match your account email", errors.New("account deletion email mismatch"))
+	ErrAIDataUnavailable          = cerr.New(fiber.ErrBadGateway.Code, "your AI assistant data couldn't be reached, please try again later", errors.New("vistara-ai user data request failed"))
+	ErrPhotoRemovalFailed         = cerr.New(fiber.ErrBadGateway.Code, "failed to remove your photos, please try again later", errors.New("profile photo removal failed"))
 )
@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportData sends the user's personal data as a ZIP archive download
+func (h *UserHandler) exportData(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	archive, err := h.service.ExportData(ctx.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, "application/zip")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, archive.FileName))
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+
+	return ctx.Status(fiber.StatusOK).Send(archive.Content)
+}
+
+// deleteAccount permanently deletes the user, reviews and bookings stay anonymised for accounting
+func (h *UserHandler) deleteAccount(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	var request user.DeleteAccountRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteAccount(ctx.Context(), userID, request); err != nil {
+		return err
+	}
+
+	ctx.ClearCookie("refresh_token")
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "account deleted",
+	})
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *UserHandler) getProfile(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	response, err := h.service.GetProfile(ctx.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "profile retrieved",
+		"payload": response,
+	})
+}
+
+// updateProfile accepts JSON, or multipart/form-data when a new photo is included
+func (h *UserHandler) updateProfile(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+
+	var request user.UpdateProfileRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if photo, err := ctx.FormFile("photo"); err == nil {
+		request.Photo = photo
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateProfile(ctx.Context(), userID, request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "profile updated",
+		"payload": response,
+	})
+}
+
+func (h *UserHandler) changePassword(ctx *fiber.Ctx) error {
+	userID, _ := ctx.Locals("user_id").(string)
+	sessionID, _ := ctx.Locals("session_id").(string)
+
+	var request user.ChangePasswordRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	if err := h.service.ChangePassword(ctx.Context(), userID, sessionID, request); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "password changed, other sessions have been signed out",
+	})
+}
@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/domain/user/service"
+	"github.com/vistara-studio/vistara-be/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler struct {
+	service    service.UserServiceItf
+	validator  *validator.Validate
+	middleware *middleware.Middleware
+}
+
+func New(service service.UserServiceItf, validator *validator.Validate, middleware *middleware.Middleware) *UserHandler {
+	return &UserHandler{service: service, validator: validator, middleware: middleware}
+}
+
+func (h *UserHandler) Mount(router fiber.Router) {
+	authGroup := router.Group("/auth")
+
+	authentication := h.middleware.Authentication()
+	authGroup.Get("/profile", authentication, h.getProfile)
+	authGroup.Patch("/profile", authentication, h.updateProfile)
+	authGroup.Delete("/profile", authentication, h.deleteAccount)
+	authGroup.Get("/profile/export", authentication, h.exportData)
+	authGroup.Post("/change-password", authentication, h.changePassword)
+
+	adminGroup := router.Group("/admin")
+	adminGroup.Patch("/users/:userID/role", authentication, h.middleware.RequireRole(user.RoleAdmin), h.updateRole)
+}
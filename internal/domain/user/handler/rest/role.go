@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *UserHandler) updateRole(ctx *fiber.Ctx) error {
+	adminID, _ := ctx.Locals("user_id").(string)
+
+	var request user.UpdateRoleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(request); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateRole(ctx.Context(), adminID, ctx.Params("userID"), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "role updated, the user has to sign in again",
+		"payload": response,
+	})
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetDataExport collects every row owned by the account as JSON documents, login history is matched by email
+func (r *userRepository) GetDataExport(ctx context.Context, account user.Table, data *user.DataExport) error {
+	query := `SELECT
+	(SELECT row_to_json(t) FROM (
+		SELECT id, full_name, email, auth_provider, google_id, role, photo_url, is_premium,
+		expired_at, email_verified_at, created_at, updated_at
+		FROM users WHERE id = $1
+	) t) AS profile,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT id, family_id, user_agent, ip_address, mfa_authenticated, created_at,
+		last_used_at, expires_at, revoked_at
+		FROM sessions WHERE user_id = $1
+	) t) AS sessions,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT id, local_id, star, content, photo_url, created_at, updated_at
+		FROM reviews WHERE user_id = $1
+	) t) AS reviews,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT tb.id, tb.tourist_attraction_id, tb.status, tb.booked_at, tb.payment_url,
+		tb.star, tb.content, tb.photo_url, tb.expires_at, tb.created_at, tb.updated_at,
+		(SELECT COALESCE(json_agg(h ORDER BY h.created_at), '[]') FROM (
+			SELECT from_status, to_status, actor, reason, created_at
+			FROM booking_status_history WHERE booking_id = tb.id
+		) h) AS status_history
+		FROM tourguide_bookings tb WHERE tb.user_id = $1
+	) t) AS bookings,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT o.id, p.code AS plan_code, o.amount, o.duration_days, o.status, o.payment_url,
+		o.expires_at, o.paid_at, o.period_start, o.period_end, o.created_at, o.updated_at
+		FROM subscription_orders o
+		INNER JOIN subscription_plans p ON p.id = o.plan_id
+		WHERE o.user_id = $1
+	) t) AS subscription_orders,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT ip_address, succeeded, created_at
+		FROM login_attempts WHERE email = LOWER($2)
+	) t) AS login_attempts,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT scope, failed_attempts, locked_until, ip_address, user_agent, created_at
+		FROM login_lockouts WHERE user_id = $1 OR (scope = 'account' AND subject = LOWER($2))
+	) t) AS login_lockouts,
+	(SELECT row_to_json(t) FROM (
+		SELECT enabled_at, created_at,
+		(SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL) AS unused_recovery_codes
+		FROM user_mfa WHERE user_id = $1
+	) t) AS mfa,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT 'local_business' AS type, id, name, created_at FROM locals WHERE owner_id = $1
+		UNION ALL
+		SELECT 'tourist_attraction' AS type, id, name, created_at FROM tourist_attractions WHERE owner_id = $1
+	) t) AS listings,
+	(SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]') FROM (
+		SELECT id, name, prefix, scopes, expires_at, revoked_at, last_used_at, request_count, created_at
+		FROM api_keys WHERE user_id = $1
+	) t) AS api_keys
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, account.ID, account.Email)
+	return row.StructScan(data)
+}
+
+// GetContributedPhotoURLs lists the photos the user attached to reviews and bookings
+func (r *userRepository) GetContributedPhotoURLs(ctx context.Context, account user.Table, out *[]string) error {
+	query := `SELECT photo_url FROM reviews WHERE user_id = $1 AND COALESCE(photo_url, '') <> ''
+	UNION
+	SELECT photo_url FROM tourguide_bookings WHERE user_id = $1 AND COALESCE(photo_url, '') <> ''`
+
+	return sqlx.SelectContext(ctx, r.q, out, query, account.ID)
+}
+
+// DeleteAccount removes the account and its personal data, reviews, bookings and orders are kept without the user
+// for accounting and listings lose their owner. Must run on a transaction client.
+func (r *userRepository) DeleteAccount(ctx context.Context, account user.Table) error {
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE reviews SET user_id = NULL, photo_url = NULL, updated_at = NOW() WHERE user_id = $1`, []interface{}{account.ID}},
+		{`UPDATE tourguide_bookings SET user_id = NULL, photo_url = NULL, updated_at = NOW() WHERE user_id = $1`, []interface{}{account.ID}},
+		{`UPDATE booking_status_history SET actor = 'user:deleted' WHERE actor = 'user:' || $1::text`, []interface{}{account.ID.String()}},
+		{`UPDATE subscription_orders SET user_id = NULL, updated_at = NOW() WHERE user_id = $1`, []interface{}{account.ID}},
+		{`DELETE FROM login_attempts WHERE email = LOWER($1)`, []interface{}{account.Email}},
+		{`UPDATE login_lockouts SET subject = 'deleted', user_id = NULL, ip_address = '', user_agent = ''
+		WHERE user_id = $1 OR (scope = 'account' AND subject = LOWER($2))`, []interface{}{account.ID, account.Email}},
+		{`DELETE FROM sessions WHERE user_id = $1`, []interface{}{account.ID}},
+		{`DELETE FROM users WHERE id = $1`, []interface{}{account.ID}},
+	}
+
+	for _, statement := range statements {
+		if _, err := r.q.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AnonymizeAuditEvents erases the actor ID and IP address of the events the user performed, their salted digest
+// stays so the hash chain still verifies. Events from before the digest existed can't be erased.
+func (r *userRepository) AnonymizeAuditEvents(ctx context.Context, account user.Table) error {
+	query := `UPDATE audit_events SET actor_id = '', ip_address = '', personal_salt = ''
+	WHERE actor_type = 'user' AND actor_id = $1 AND personal_digest <> ''`
+
+	_, err := r.q.ExecContext(ctx, query, account.ID.String())
+	return err
+}
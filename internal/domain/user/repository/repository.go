@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
 	"github.com/vistara-studio/vistara-be/internal/domain/user"
 	"github.com/jmoiron/sqlx"
 )
@@ -29,8 +30,23 @@ type userRepository struct {
 type userRepositoryItf interface {
 	Commit() error
 	Rollback() error
+	AuditTx() audit.Tx
 	CreateUser(ctx context.Context, data user.Table) error
 	GetAccountByEmail(ctx context.Context, data *user.Table) error
+	GetAccountByID(ctx context.Context, data *user.Table) error
+	GetAccountByGoogleID(ctx context.Context, data *user.Table) error
+	LinkGoogleAccount(ctx context.Context, data user.Table) error
+	MarkEmailVerified(ctx context.Context, data user.Table) error
+	UpdatePassword(ctx context.Context, data user.Table) error
+	UpdateProfile(ctx context.Context, data user.Table) error
+	UpdateRole(ctx context.Context, data user.Table) error
+	CreateToken(ctx context.Context, data user.Token) error
+	ConsumeToken(ctx context.Context, data *user.Token) error
+	InvalidateTokens(ctx context.Context, data user.Token) error
+	GetDataExport(ctx context.Context, account user.Table, data *user.DataExport) error
+	GetContributedPhotoURLs(ctx context.Context, account user.Table, out *[]string) error
+	DeleteAccount(ctx context.Context, account user.Table) error
+	AnonymizeAuditEvents(ctx context.Context, account user.Table) error
 }
 
 type namedExt interface {
@@ -72,3 +88,8 @@ func (r *userRepository) Rollback() error {
 
 	return errFailedToRollback
 }
+
+// AuditTx exposes the transaction so audit events are written atomically with the change
+func (r *userRepository) AuditTx() audit.Tx {
+	return r.q
+}
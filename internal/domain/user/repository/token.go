@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+)
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, data user.Table) error {
+	query := `UPDATE users
+	SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+	WHERE id = $1
+	`
+
+	_, err := r.q.ExecContext(ctx, query, data.ID)
+	return err
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, data user.Table) error {
+	query := `UPDATE users
+	SET password = $2, updated_at = NOW()
+	WHERE id = $1
+	`
+
+	_, err := r.q.ExecContext(ctx, query, data.ID, data.Password)
+	return err
+}
+
+func (r *userRepository) CreateToken(ctx context.Context, data user.Token) error {
+	query := `INSERT INTO user_tokens (
+		id, user_id, purpose, token_hash, expires_at
+	) VALUES (
+		:id, :user_id, :purpose, :token_hash, :expires_at
+	)`
+
+	_, err := r.q.NamedExecContext(ctx, query, data)
+	return err
+}
+
+// ConsumeToken marks an unused, unexpired token as used and loads it.
+// The update is atomic, so a token can only ever be consumed once.
+func (r *userRepository) ConsumeToken(ctx context.Context, data *user.Token) error {
+	query := `UPDATE user_tokens
+	SET used_at = NOW()
+	WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, data.TokenHash, data.Purpose)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.ErrInvalidToken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateTokens burns every outstanding token of the user for the given purpose
+func (r *userRepository) InvalidateTokens(ctx context.Context, data user.Token) error {
+	query := `UPDATE user_tokens
+	SET used_at = NOW()
+	WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`
+
+	_, err := r.q.ExecContext(ctx, query, data.UserID, data.Purpose)
+	return err
+}
@@ -11,9 +11,9 @@ import (
 
 func (r *userRepository) CreateUser(ctx context.Context, data user.Table) error {
 	query := `INSERT INTO users (
-		id, full_name, email, password, auth_provider, photo_url
+		id, full_name, email, password, auth_provider, google_id, photo_url, email_verified_at
 	) VALUES (
-		:id, :full_name, :email, :password, :auth_provider, :photo_url
+		:id, :full_name, :email, NULLIF(:password, ''), :auth_provider, NULLIF(:google_id, ''), :photo_url, :email_verified_at
 	)`
 
 	_, err := r.q.NamedExecContext(ctx, query, data)
@@ -31,7 +31,9 @@ func (r *userRepository) CreateUser(ctx context.Context, data user.Table) error
 
 func (r *userRepository) GetAccountByEmail(ctx context.Context, data *user.Table) error {
 	query := `SELECT 
-	id, full_name, email, password, auth_provider, photo_url, created_at, updated_at
+	id, full_name, email, COALESCE(password, '') AS password, auth_provider,
+	COALESCE(google_id, '') AS google_id, role, photo_url, is_premium,
+	COALESCE(expired_at, '0001-01-01') AS expired_at, email_verified_at, created_at, updated_at
 	FROM users
 	WHERE email = $1
 	`
@@ -47,3 +49,116 @@ func (r *userRepository) GetAccountByEmail(ctx context.Context, data *user.Table
 
 	return nil
 }
+
+func (r *userRepository) GetAccountByID(ctx context.Context, data *user.Table) error {
+	query := `SELECT 
+	id, full_name, email, COALESCE(password, '') AS password, auth_provider,
+	COALESCE(google_id, '') AS google_id, role, photo_url, is_premium,
+	COALESCE(expired_at, '0001-01-01') AS expired_at, email_verified_at, created_at, updated_at
+	FROM users
+	WHERE id = $1
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, data.ID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetAccountByGoogleID(ctx context.Context, data *user.Table) error {
+	query := `SELECT 
+	id, full_name, email, COALESCE(password, '') AS password, auth_provider,
+	COALESCE(google_id, '') AS google_id, role, photo_url, is_premium,
+	COALESCE(expired_at, '0001-01-01') AS expired_at, email_verified_at, created_at, updated_at
+	FROM users
+	WHERE google_id = $1
+	`
+
+	row := r.q.QueryRowxContext(ctx, query, data.GoogleID)
+	if err := row.StructScan(data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// LinkGoogleAccount attaches a Google subject to an account that has none yet
+func (r *userRepository) LinkGoogleAccount(ctx context.Context, data user.Table) error {
+	query := `UPDATE users
+	SET google_id = $2, updated_at = NOW()
+	WHERE id = $1 AND google_id IS NULL
+	`
+
+	result, err := r.q.ExecContext(ctx, query, data.ID, data.GoogleID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return user.ErrGoogleAccountAlreadyLinked
+		}
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return user.ErrGoogleAccountAlreadyLinked
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, data user.Table) error {
+	query := `UPDATE users
+	SET full_name = :full_name, photo_url = :photo_url, updated_at = NOW()
+	WHERE id = :id
+	`
+
+	result, err := r.q.NamedExecContext(ctx, query, data)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, data user.Table) error {
+	query := `UPDATE users
+	SET role = $2, updated_at = NOW()
+	WHERE id = $1
+	`
+
+	result, err := r.q.ExecContext(ctx, query, data.ID, data.Role)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
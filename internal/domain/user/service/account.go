@@ -0,0 +1,182 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/rs/zerolog/log"
+)
+
+// ExportData builds a ZIP archive with one JSON file per kind of data we hold about the user,
+// including what they sent to the AI endpoints
+func (s *userService) ExportData(ctx context.Context, userID string) (user.DataArchive, error) {
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return user.DataArchive{}, err
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return user.DataArchive{}, err
+	}
+
+	var data user.DataExport
+	if err := userRepository.GetDataExport(ctx, *account, &data); err != nil {
+		return user.DataArchive{}, err
+	}
+
+	aiData, err := s.ai.ExportUserData(account.ID.String())
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", account.ID.String()).Msg("failed to export vistara-ai user data")
+		return user.DataArchive{}, user.ErrAIDataUnavailable
+	}
+
+	files := []struct {
+		name    string
+		content json.RawMessage
+	}{
+		{"profile.json", data.Profile},
+		{"sessions.json", data.Sessions},
+		{"reviews.json", data.Reviews},
+		{"bookings.json", data.Bookings},
+		{"subscription_orders.json", data.SubscriptionOrders},
+		{"login_attempts.json", data.LoginAttempts},
+		{"login_lockouts.json", data.LoginLockouts},
+		{"mfa.json", data.MFA},
+		{"listings.json", data.Listings},
+		{"api_keys.json", data.APIKeys},
+		{"ai.json", aiData},
+	}
+
+	generatedAt := time.Now().UTC()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: generatedAt})
+		if err != nil {
+			return user.DataArchive{}, err
+		}
+
+		if _, err := writer.Write(indentJSON(file.content)); err != nil {
+			return user.DataArchive{}, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return user.DataArchive{}, err
+	}
+
+	return user.DataArchive{
+		FileName: fmt.Sprintf("vistara-data-%s-%s.zip", account.ID, generatedAt.Format("20060102")),
+		Content:  buffer.Bytes(),
+	}, nil
+}
+
+// DeleteAccount erases the user's data after they confirm with their email, and their password if they have one.
+// AI data and photos go first so a failure there leaves the account intact for a retry.
+func (s *userService) DeleteAccount(ctx context.Context, userID string, request user.DeleteAccountRequest) error {
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(request.ConfirmEmail), account.Email) {
+		return user.ErrEmailConfirmationMismatch
+	}
+
+	if account.Password != "" {
+		if request.Password == "" {
+			return user.ErrPasswordRequired
+		}
+		if err := bcrypt.ComparePassword(account.Password, request.Password); err != nil {
+			return user.ErrIncorrectPassword
+		}
+	}
+
+	if err := s.ai.DeleteUserData(account.ID.String()); err != nil {
+		log.Warn().Err(err).Str("user_id", account.ID.String()).Msg("failed to delete vistara-ai user data")
+		return user.ErrAIDataUnavailable
+	}
+
+	if err := storage.RemoveProfilePhotos(ctx, s.remover, account.ID); err != nil {
+		log.Warn().Err(err).Str("user_id", account.ID.String()).Msg("failed to remove profile photos")
+		return user.ErrPhotoRemovalFailed
+	}
+
+	// Review and booking photos are personal data too, their rows are kept but lose the photo
+	if err := s.removeContributedPhotos(ctx, account); err != nil {
+		log.Warn().Err(err).Str("user_id", account.ID.String()).Msg("failed to remove review and booking photos")
+		return user.ErrPhotoRemovalFailed
+	}
+
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	if err = userRepository.DeleteAccount(ctx, *account); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, userRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(userID),
+		Action:     audit.ActionAccountDeleted,
+		TargetType: audit.TargetUser,
+		TargetID:   account.ID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Erased last so the deletion event itself doesn't keep the user's IP address
+	if err = userRepository.AnonymizeAuditEvents(ctx, *account); err != nil {
+		return err
+	}
+
+	return userRepository.Commit()
+}
+
+// removeContributedPhotos deletes the stored photos of the user's reviews and bookings
+func (s *userService) removeContributedPhotos(ctx context.Context, account *user.Table) error {
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	var urls []string
+	if err := userRepository.GetContributedPhotoURLs(ctx, *account, &urls); err != nil {
+		return err
+	}
+
+	return s.remover.RemoveURLs(ctx, urls...)
+}
+
+// indentJSON pretty prints a document for the archive, missing documents are written as null
+func indentJSON(content json.RawMessage) []byte {
+	if len(content) == 0 {
+		return []byte("null\n")
+	}
+
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, content, "", "  "); err != nil {
+		return content
+	}
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+	"github.com/vistara-studio/vistara-be/pkg/bcrypt"
+	"github.com/vistara-studio/vistara-be/pkg/util"
+	"github.com/google/uuid"
+)
+
+func (s *userService) GetProfile(ctx context.Context, userID string) (user.ProfileResponse, error) {
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	return user.NewProfileResponse(*account), nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, userID string, request user.UpdateProfileRequest) (user.ProfileResponse, error) {
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	if request.FullName != nil {
+		account.FullName = *request.FullName
+	}
+
+	if request.Photo != nil {
+		if err := util.ValidateFile(request.Photo); err != nil {
+			return user.ProfileResponse{}, user.ErrInvalidPhoto
+		}
+
+		photoURL, err := storage.UploadProfilePhoto(s.storage, account.ID, request.Photo)
+		if err != nil {
+			return user.ProfileResponse{}, user.ErrPhotoUploadFailed
+		}
+		account.PhotoUrl = photoURL
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	if err := userRepository.UpdateProfile(ctx, *account); err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	return user.NewProfileResponse(*account), nil
+}
+
+// ChangePassword replaces the password and signs out every session except the one making the request
+func (s *userService) ChangePassword(ctx context.Context, userID, sessionID string, request user.ChangePasswordRequest) (err error) {
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if account.Password == "" {
+		return user.ErrNoPasswordSet
+	}
+
+	if err := bcrypt.ComparePassword(account.Password, request.CurrentPassword); err != nil {
+		return user.ErrIncorrectPassword
+	}
+
+	hashedPassword, err := bcrypt.EncryptPassword(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	account.Password = hashedPassword
+	if err = userRepository.UpdatePassword(ctx, *account); err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, userRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(userID),
+		Action:     audit.ActionPasswordChanged,
+		TargetType: audit.TargetUser,
+		TargetID:   account.ID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = userRepository.Commit(); err != nil {
+		return err
+	}
+
+	familyID, err := uuid.Parse(sessionID)
+	if err != nil {
+		familyID = uuid.Nil
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return err
+	}
+
+	return sessionRepository.RevokeSessionsByUserID(ctx, account.ID, familyID)
+}
+
+func (s *userService) getAccount(ctx context.Context, userID string) (*user.Table, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrUserNotFound
+	}
+
+	userRepository, err := s.repository.NewClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &user.Table{ID: id}
+	if err := userRepository.GetAccountByID(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// UpdateRole changes the role of a user and signs them out, so the new role is in every token they hold
+func (s *userService) UpdateRole(ctx context.Context, adminID, userID string, request user.UpdateRoleRequest) (response user.ProfileResponse, err error) {
+	if !request.Role.IsValid() {
+		return user.ProfileResponse{}, user.ErrInvalidRole
+	}
+
+	if adminID == userID {
+		return user.ProfileResponse{}, user.ErrCannotChangeOwnRole
+	}
+
+	account, err := s.getAccount(ctx, userID)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	if account.Role == request.Role {
+		return user.NewProfileResponse(*account), nil
+	}
+
+	userRepository, err := s.repository.NewClient(true)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = userRepository.Rollback()
+		}
+	}()
+
+	previousRole := account.Role
+	account.Role = request.Role
+	if err = userRepository.UpdateRole(ctx, *account); err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	err = s.audit.Record(ctx, userRepository.AuditTx(), audit.Entry{
+		Actor:      audit.UserActor(adminID),
+		Action:     audit.ActionRoleChanged,
+		TargetType: audit.TargetUser,
+		TargetID:   account.ID.String(),
+		Changes:    audit.Changes{"role": {From: previousRole, To: account.Role}},
+	})
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	if err = userRepository.Commit(); err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	sessionRepository, err := s.sessionRepository.NewClient(false)
+	if err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	if err := sessionRepository.RevokeSessionsByUserID(ctx, account.ID, uuid.Nil); err != nil {
+		return user.ProfileResponse{}, err
+	}
+
+	return user.NewProfileResponse(*account), nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	sessionRepository "github.com/vistara-studio/vistara-be/internal/domain/session/repository"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	userRepository "github.com/vistara-studio/vistara-be/internal/domain/user/repository"
+	"github.com/vistara-studio/vistara-be/internal/infra/ai"
+	"github.com/vistara-studio/vistara-be/internal/infra/storage"
+)
+
+type userService struct {
+	repository        userRepository.RepositoryItf
+	sessionRepository sessionRepository.RepositoryItf
+	storage           storage.Uploader
+	remover           storage.Remover
+	ai                *ai.Client
+	audit             audit.Recorder
+}
+
+type UserServiceItf interface {
+	GetProfile(ctx context.Context, userID string) (user.ProfileResponse, error)
+	UpdateProfile(ctx context.Context, userID string, request user.UpdateProfileRequest) (user.ProfileResponse, error)
+	ChangePassword(ctx context.Context, userID, sessionID string, request user.ChangePasswordRequest) error
+	UpdateRole(ctx context.Context, adminID, userID string, request user.UpdateRoleRequest) (user.ProfileResponse, error)
+	ExportData(ctx context.Context, userID string) (user.DataArchive, error)
+	DeleteAccount(ctx context.Context, userID string, request user.DeleteAccountRequest) error
+}
+
+func New(repository userRepository.RepositoryItf, sessionRepository sessionRepository.RepositoryItf, storage storage.Uploader, remover storage.Remover, ai *ai.Client, recorder audit.Recorder) UserServiceItf {
+	return &userService{
+		repository:        repository,
+		sessionRepository: sessionRepository,
+		storage:           storage,
+		remover:           remover,
+		ai:                ai,
+		audit:             recorder,
+	}
+}
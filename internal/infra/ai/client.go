@@ -5,21 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/vistara-studio/vistara-be/pkg/signature"
 )
 
 // Client handles communication with vistara-ai service
 type Client struct {
 	BaseURL    string
-	APIKey     string
+	Signer     *signature.Signer
 	HTTPClient *http.Client
 }
 
-// NewClient creates a new AI service client
-func NewClient(baseURL, apiKey string) *Client {
+// NewClient creates a new AI service client that signs every request with signer
+func NewClient(baseURL string, signer *signature.Signer) *Client {
 	return &Client{
 		BaseURL: baseURL,
-		APIKey:  apiKey,
+		Signer:  signer,
 		HTTPClient: &http.Client{
 			Timeout: 60 * time.Second, // AI requests may take longer
 		},
@@ -106,7 +109,9 @@ func (c *Client) GenerateSmartPlan(req *SmartPlanRequest) (*SmartPlanResponse, e
 	// Set headers for service-to-service communication
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Service", "vistara-be")
-	httpReq.Header.Set("X-API-Key", c.APIKey)
+	if err := c.Signer.Sign(httpReq, jsonData); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 
 	// Execute request
 	resp, err := c.HTTPClient.Do(httpReq)
@@ -155,7 +160,9 @@ func (c *Client) GenerateNusaLingo(req *NusaLingoRequest) (*NusaLingoResponse, e
 	// Set headers for service-to-service communication
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Service", "vistara-be")
-	httpReq.Header.Set("X-API-Key", c.APIKey)
+	if err := c.Signer.Sign(httpReq, jsonData); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 
 	// Execute request
 	resp, err := c.HTTPClient.Do(httpReq)
@@ -204,7 +211,9 @@ func (c *Client) GenerateHistoricalStory(req *HistoricalStoryRequest) (*Historic
 	// Set headers for service-to-service communication
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("X-Service", "vistara-be")
-	httpReq.Header.Set("X-API-Key", c.APIKey)
+	if err := c.Signer.Sign(httpReq, jsonData); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 
 	// Execute request
 	resp, err := c.HTTPClient.Do(httpReq)
@@ -235,3 +244,78 @@ func (c *Client) GenerateHistoricalStory(req *HistoricalStoryRequest) (*Historic
 
 	return response.Data, nil
 }
+
+// ExportUserData fetches everything the user has sent to the AI service, nil means the service holds nothing for them
+func (c *Client) ExportUserData(userID string) (json.RawMessage, error) {
+	resp, err := c.userData(http.MethodGet, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	// Parse response
+	var response struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("AI service error: %s", response.Message)
+	}
+
+	return response.Data, nil
+}
+
+// DeleteUserData erases everything the user has sent to the AI service
+func (c *Client) DeleteUserData(userID string) error {
+	resp, err := c.userData(http.MethodDelete, userID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Nothing stored is as good as deleted
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// userData sends a signed request to the per-user data endpoint of the AI service
+func (c *Client) userData(method, userID string) (*http.Response, error) {
+	httpReq, err := http.NewRequest(method, c.BaseURL+"/api/v1/service/users/"+url.PathEscape(userID)+"/data", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Service", "vistara-be")
+	if err := c.Signer.Sign(httpReq, nil); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	return resp, nil
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	_ "github.com/joho/godotenv/autoload"
 )
@@ -11,8 +14,23 @@ type Env struct {
 	AppName string `env:"APP_NAME,required"`
 	AppPort int    `env:"APP_PORT,required"`
 
-	// JWT authentication settings
-	JWTSecret string `env:"JWT_SECRET,required"`
+	// JWT authentication settings, the signing key is an inline PEM or a PEM file
+	JWTAlgorithm            string `env:"JWT_ALGORITHM"`
+	JWTKeyID                string `env:"JWT_KEY_ID,required"`
+	JWTPrivateKey           string `env:"JWT_PRIVATE_KEY"`
+	JWTPrivateKeyFile       string `env:"JWT_PRIVATE_KEY_FILE"`
+	JWTVerificationKeyFiles string `env:"JWT_VERIFICATION_KEY_FILES"`
+	JWTIssuer               string `env:"JWT_ISSUER" envDefault:"vistara-be"`
+	JWTAudience             string `env:"JWT_AUDIENCE" envDefault:"vistara"`
+
+	// Proxies allowed to set the client IP through X-Real-IP, none unless configured
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+
+	// Login brute-force protection
+	LoginMaxAccountFailures int           `env:"LOGIN_MAX_ACCOUNT_FAILURES" envDefault:"5"`
+	LoginMaxIPFailures      int           `env:"LOGIN_MAX_IP_FAILURES" envDefault:"20"`
+	LoginFailureWindow      time.Duration `env:"LOGIN_FAILURE_WINDOW" envDefault:"15m"`
+	LoginLockoutDuration    time.Duration `env:"LOGIN_LOCKOUT_DURATION" envDefault:"15m"`
 
 	// PostgreSQL database settings
 	PostgresUsername string `env:"POSTGRES_USERNAME,required"`
@@ -27,12 +45,45 @@ type Env struct {
 	StorageToken  string `env:"SUPABASE_KEY,required"`
 	StorageBucket string `env:"SUPABASE_BUCKET,required"`
 
+	// Photo given to users who register without uploading one
+	DefaultPhotoURL string `env:"DEFAULT_PHOTO_URL" envDefault:"https://htnqkjejgcovkehhtqjw.supabase.co/storage/v1/object/public/hackfest-uhuy//photo_profile.jpg"`
+
 	// Midtrans payment settings
 	MidtransKey string `env:"MIDTRANS_SERVER_KEY,required"`
 
+	// Public URL of the web client, used for links in emails
+	AppURL string `env:"APP_URL" envDefault:"http://localhost:3000"`
+
+	// Mailer settings
+	MailerDriver  string `env:"MAILER_DRIVER" envDefault:"smtp"`
+	MailFrom      string `env:"MAIL_FROM" envDefault:"Vistara <no-reply@vistara.id>"`
+	SMTPHost      string `env:"SMTP_HOST"`
+	SMTPPort      int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername  string `env:"SMTP_USERNAME"`
+	SMTPPassword  string `env:"SMTP_PASSWORD"`
+	MailerFileDir string `env:"MAILER_FILE_DIR" envDefault:"./tmp/mail"`
+
+	// Two-factor authentication, the key is 32 base64 encoded bytes used to encrypt TOTP secrets
+	MFAEncryptionKey string   `env:"MFA_ENCRYPTION_KEY"`
+	MFAIssuer        string   `env:"MFA_ISSUER" envDefault:"Vistara"`
+	MFARequiredRoles []string `env:"MFA_REQUIRED_ROLES" envSeparator:","`
+
+	// Google sign-in settings
+	GoogleClientID string `env:"GOOGLE_CLIENT_ID"`
+	GoogleJWKSURL  string `env:"GOOGLE_JWKS_URL" envDefault:"https://www.googleapis.com/oauth2/v3/certs"`
+
+	// Background job settings
+	BookingExpiryInterval    time.Duration `env:"BOOKING_EXPIRY_INTERVAL" envDefault:"5m"`
+	PremiumDowngradeInterval time.Duration `env:"PREMIUM_DOWNGRADE_INTERVAL" envDefault:"15m"`
+	MediaProcessingInterval  time.Duration `env:"MEDIA_PROCESSING_INTERVAL" envDefault:"10s"`
+
 	// AI service integration settings
 	VistaraAIURL string `env:"VISTARA_AI_URL" envDefault:"http://localhost:5000"`
-	VistaraAIKey string `env:"VISTARA_AI_KEY" envDefault:"vistara-be-service-key"`
+
+	// Service-to-service signing, keys are kid:secret pairs and every listed key is accepted
+	ServiceAuthKeys         string        `env:"SERVICE_AUTH_KEYS,required"`
+	ServiceAuthSigningKeyID string        `env:"SERVICE_AUTH_SIGNING_KEY_ID"`
+	ServiceAuthWindow       time.Duration `env:"SERVICE_AUTH_WINDOW" envDefault:"5m"`
 }
 
 // LoadEnv loads and validates environment variables
@@ -41,5 +92,28 @@ func LoadEnv() (*Env, error) {
 	if err := env.Parse(config); err != nil {
 		return nil, err
 	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
+
+// validate rejects settings the environment parser accepts but the app can't run with,
+// a ticker panics on an interval that isn't positive
+func (e *Env) validate() error {
+	durations := map[string]time.Duration{
+		"BOOKING_EXPIRY_INTERVAL":    e.BookingExpiryInterval,
+		"MEDIA_PROCESSING_INTERVAL":  e.MediaProcessingInterval,
+		"LOGIN_FAILURE_WINDOW":       e.LoginFailureWindow,
+		"LOGIN_LOCKOUT_DURATION":     e.LoginLockoutDuration,
+		"SERVICE_AUTH_WINDOW":        e.ServiceAuthWindow,
+		"PREMIUM_DOWNGRADE_INTERVAL": e.PremiumDowngradeInterval,
+	}
+	for name, value := range durations {
+		if value <= 0 {
+			return fmt.Errorf("%s must be a positive duration, got %s", name, value)
+		}
+	}
+
+	return nil
+}
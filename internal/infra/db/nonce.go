@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NonceStore keeps the nonces of signed service requests in Postgres so every instance sees them
+type NonceStore struct {
+	db *sqlx.DB
+}
+
+// NewNonceStore creates a nonce store on the service_nonces table
+func NewNonceStore(db *sqlx.DB) *NonceStore {
+	return &NonceStore{db: db}
+}
+
+// Claim inserts the nonce, an expired row for the same nonce is taken over instead of reported as a replay
+func (s *NonceStore) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	query := `INSERT INTO service_nonces (nonce, expires_at) VALUES ($1, NOW() + make_interval(secs => $2))
+	ON CONFLICT (nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	WHERE service_nonces.expires_at <= NOW()
+	RETURNING nonce`
+
+	var claimed string
+	if err := s.db.GetContext(ctx, &claimed, query, nonce, ttl.Seconds()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Prune deletes expired nonces
+func (s *NonceStore) Prune(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM service_nonces WHERE expires_at <= NOW()`)
+	return err
+}
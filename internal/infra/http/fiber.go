@@ -9,10 +9,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewFiber() *fiber.App {
+// NewFiber creates the HTTP server, the client IP is read from X-Real-IP only when set by a trusted proxy
+func NewFiber(trustedProxies []string) *fiber.App {
 	return fiber.New(fiber.Config{
-		IdleTimeout:  5 * time.Second,
-		ErrorHandler: ErrorHandler(),
+		IdleTimeout:             5 * time.Second,
+		ErrorHandler:            ErrorHandler(),
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
 	})
 }
 
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// File writes every email as an .eml file into a directory, so flows can be exercised offline
+type File struct {
+	dir     string
+	from    string
+	counter atomic.Uint64
+}
+
+// NewFile creates a file mailer, creating dir if needed
+func NewFile(dir, from string) (*File, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("file mailer requires a directory")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &File{dir: dir, from: from}, nil
+}
+
+func (m *File) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To)
+	name := fmt.Sprintf("%d-%d-%s.eml", time.Now().UnixNano(), m.counter.Add(1), recipient)
+
+	return os.WriteFile(filepath.Join(m.dir, name), render(m.from, message), 0o644)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional emails
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Driver names accepted by New
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// Config holds the settings for every mailer driver
+type Config struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string
+}
+
+// New creates the mailer selected by config.Driver
+func New(config Config) (Mailer, error) {
+	switch config.Driver {
+	case DriverSMTP:
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mailer driver")
+		}
+		return NewSMTP(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.From), nil
+	case DriverFile:
+		return NewFile(config.FileDir, config.From)
+	case DriverLog:
+		return NewLog(config.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver %q", config.Driver)
+	}
+}
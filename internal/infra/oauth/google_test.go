@@ -0,0 +1,266 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClientID = "test-client.apps.googleusercontent.com"
+
+// jwksStub stands in for Google's certificate endpoint, serving the public halves of its keys
+type jwksStub struct {
+	server   *httptest.Server
+	keys     map[string]*rsa.PrivateKey
+	requests atomic.Int32
+}
+
+func newJWKSStub(t *testing.T, kids ...string) *jwksStub {
+	t.Helper()
+
+	stub := &jwksStub{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stub.keys[kid] = key
+	}
+
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.requests.Add(1)
+
+		set := struct {
+			Keys []jsonWebKey `json:"keys"`
+		}{}
+		for kid, key := range stub.keys {
+			set.Keys = append(set.Keys, jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				Alg: "RS256",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (s *jwksStub) sign(t *testing.T, kid string, claims googleClaims) string {
+	t.Helper()
+	return signWith(t, s.keys[kid], kid, claims)
+}
+
+func signWith(t *testing.T, key *rsa.PrivateKey, kid string, claims googleClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() googleClaims {
+	now := time.Now()
+	return googleClaims{
+		Email:         "traveller@example.com",
+		EmailVerified: true,
+		Name:          "Traveller",
+		Picture:       "https://example.com/traveller.png",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "1234567890",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func TestGoogleVerifierVerify(t *testing.T) {
+	stub := newJWKSStub(t, "current")
+	other := newJWKSStub(t, "current")
+
+	// The unknown kid case is signed with a key the stub doesn't publish
+	unpublished, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			token: func() string { return stub.sign(t, "current", validClaims()) },
+		},
+		{
+			name: "issuer without scheme",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "accounts.google.com"
+				return stub.sign(t, "current", claims)
+			},
+		},
+		{
+			name: "other audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"someone-else"}
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name: "other issuer",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "https://evil.example.com"
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: errAny,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: jwt.ErrTokenExpired,
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name: "unverified email",
+			token: func() string {
+				claims := validClaims()
+				claims.EmailVerified = false
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: ErrEmailUnverified,
+		},
+		{
+			name: "missing subject",
+			token: func() string {
+				claims := validClaims()
+				claims.Subject = ""
+				return stub.sign(t, "current", claims)
+			},
+			wantErr: errAny,
+		},
+		{
+			name:    "signed by another key with the same kid",
+			token:   func() string { return other.sign(t, "current", validClaims()) },
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "unknown kid",
+			token:   func() string { return signWith(t, unpublished, "retired", validClaims()) },
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name: "hmac signed",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+				token.Header["kid"] = "current"
+				signed, err := token.SignedString([]byte("secret"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+	}
+
+	verifier := NewGoogleVerifier(stub.server.URL, testClientID)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), test.token())
+
+			if test.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				want := GoogleIdentity{Subject: "1234567890", Email: "traveller@example.com", Name: "Traveller", Picture: "https://example.com/traveller.png"}
+				if *identity != want {
+					t.Fatalf("Verify() = %+v, want %+v", *identity, want)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Verify() accepted the token, want %v", test.wantErr)
+			}
+			if test.wantErr != errAny && !errors.Is(err, test.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestGoogleVerifierCachesKeys(t *testing.T) {
+	stub := newJWKSStub(t, "current")
+	verifier := NewGoogleVerifier(stub.server.URL, testClientID)
+
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Verify(context.Background(), stub.sign(t, "current", validClaims())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A burst of unknown key IDs right after a fetch must not reach the endpoint again
+	unpublished, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Verify(context.Background(), signWith(t, unpublished, "unknown", validClaims())); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("Verify() error = %v, want %v", err, ErrUnknownKey)
+		}
+	}
+
+	if got := stub.requests.Load(); got != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", got)
+	}
+}
+
+func TestGoogleVerifierEnabled(t *testing.T) {
+	if NewGoogleVerifier("", "").Enabled() {
+		t.Fatal("verifier without a client ID reports enabled")
+	}
+	if !NewGoogleVerifier("", testClientID).Enabled() {
+		t.Fatal("verifier with a client ID reports disabled")
+	}
+}
+
+// errAny marks cases where any error will do
+var errAny = errors.New("any error")
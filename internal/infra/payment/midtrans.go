@@ -1,6 +1,11 @@
 package payment
 
 import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
@@ -15,3 +20,12 @@ func New(serverKey string) (snap.Client, coreapi.Client) {
 
 	return snap, coreapi
 }
+
+// VerifySignature checks the signature_key of a Midtrans HTTP notification,
+// which is the SHA512 hex digest of order_id + status_code + gross_amount + server key
+func VerifySignature(serverKey, orderID, statusCode, grossAmount, signatureKey string) bool {
+	digest := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	expected := hex.EncodeToString(digest[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signatureKey))) == 1
+}
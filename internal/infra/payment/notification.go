@@ -0,0 +1,56 @@
+package payment
+
+import (
+	"context"
+	"strings"
+)
+
+// Notification is the HTTP notification payload Midtrans sends when a transaction changes state
+type Notification struct {
+	OrderID           string `json:"order_id" validate:"required"`
+	StatusCode        string `json:"status_code" validate:"required"`
+	GrossAmount       string `json:"gross_amount" validate:"required"`
+	SignatureKey      string `json:"signature_key" validate:"required"`
+	TransactionStatus string `json:"transaction_status" validate:"required"`
+	FraudStatus       string `json:"fraud_status"`
+	PaymentType       string `json:"payment_type"`
+	TransactionID     string `json:"transaction_id"`
+}
+
+// NotificationHandler processes notifications for the orders it created
+type NotificationHandler interface {
+	HandlePaymentNotification(ctx context.Context, notification Notification) error
+}
+
+type route struct {
+	prefix  string
+	handler NotificationHandler
+}
+
+// Dispatcher routes Midtrans notifications to a handler by order ID prefix,
+// since every payment shares the single notification URL configured in Midtrans
+type Dispatcher struct {
+	routes   []route
+	fallback NotificationHandler
+}
+
+// NewDispatcher creates a dispatcher that sends unmatched order IDs to fallback
+func NewDispatcher(fallback NotificationHandler) *Dispatcher {
+	return &Dispatcher{fallback: fallback}
+}
+
+// Handle registers handler for order IDs starting with prefix
+func (d *Dispatcher) Handle(prefix string, handler NotificationHandler) {
+	d.routes = append(d.routes, route{prefix: prefix, handler: handler})
+}
+
+// Dispatch hands the notification to the handler owning its order ID
+func (d *Dispatcher) Dispatch(ctx context.Context, notification Notification) error {
+	for _, r := range d.routes {
+		if strings.HasPrefix(notification.OrderID, r.prefix) {
+			return r.handler.HandlePaymentNotification(ctx, notification)
+		}
+	}
+
+	return d.fallback.HandlePaymentNotification(ctx, notification)
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Objects reads and writes objects the server produces itself, the uploader library only takes multipart uploads
+type Objects interface {
+	Download(ctx context.Context, path string) ([]byte, error)
+	Put(ctx context.Context, path, contentType string, data []byte) (string, error)
+}
+
+// SupabaseObjects reads and writes objects through the Supabase storage REST API
+type SupabaseObjects struct {
+	url        string
+	token      string
+	bucket     string
+	httpClient *http.Client
+}
+
+// NewObjects creates an object client for the bucket the uploader writes to
+func NewObjects(url, token, bucket string) *SupabaseObjects {
+	return &SupabaseObjects{
+		url:        strings.TrimRight(url, "/"),
+		token:      token,
+		bucket:     bucket,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Download returns the content of the object at path
+func (o *SupabaseObjects) Download(ctx context.Context, path string) ([]byte, error) {
+	response, err := o.do(ctx, http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return io.ReadAll(response.Body)
+}
+
+// Put stores data at path, replacing any object already there, and returns its public URL
+func (o *SupabaseObjects) Put(ctx context.Context, path, contentType string, data []byte) (string, error) {
+	response, err := o.do(ctx, http.MethodPost, path, contentType, data)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+
+	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", o.url, o.bucket, path), nil
+}
+
+func (o *SupabaseObjects) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	endpoint := fmt.Sprintf("%s/storage/v1/object/%s/%s", o.url, o.bucket, strings.TrimLeft(path, "/"))
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+o.token)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+		request.Header.Set("x-upsert", "true")
+	}
+
+	response, err := o.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("storage request failed: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("storage %s %s: unexpected status code %d", method, path, response.StatusCode)
+	}
+
+	return response, nil
+}
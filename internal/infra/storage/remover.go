@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// listPageSize is the most objects Supabase returns from a single list call
+const listPageSize = 1000
+
+// Remover deletes stored objects, the uploader library's delete exits the process on network errors so it isn't used
+type Remover interface {
+	RemovePrefix(ctx context.Context, prefix string) error
+	Remove(ctx context.Context, paths ...string) error
+	RemoveURLs(ctx context.Context, urls ...string) error
+}
+
+// SupabaseRemover deletes objects through the Supabase storage REST API
+type SupabaseRemover struct {
+	url        string
+	token      string
+	bucket     string
+	httpClient *http.Client
+}
+
+// NewRemover creates a remover for the bucket the uploader writes to
+func NewRemover(url, token, bucket string) *SupabaseRemover {
+	return &SupabaseRemover{
+		url:        strings.TrimRight(url, "/"),
+		token:      token,
+		bucket:     bucket,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// RemovePrefix deletes every object directly under prefix
+func (r *SupabaseRemover) RemovePrefix(ctx context.Context, prefix string) error {
+	prefix = strings.Trim(prefix, "/")
+
+	for {
+		names, err := r.list(ctx, prefix)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = prefix + "/" + name
+		}
+		if err := r.remove(ctx, paths); err != nil {
+			return err
+		}
+
+		if len(names) < listPageSize {
+			return nil
+		}
+	}
+}
+
+// Remove deletes the objects at paths, paths that don't exist are ignored
+func (r *SupabaseRemover) Remove(ctx context.Context, paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return r.remove(ctx, paths)
+}
+
+// RemoveURLs deletes the objects behind public URLs of the bucket, URLs pointing anywhere else are skipped
+func (r *SupabaseRemover) RemoveURLs(ctx context.Context, urls ...string) error {
+	publicPrefix := r.url + "/storage/v1/object/public/" + r.bucket + "/"
+
+	paths := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if path, ok := strings.CutPrefix(url, publicPrefix); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return r.Remove(ctx, paths...)
+}
+
+func (r *SupabaseRemover) list(ctx context.Context, prefix string) ([]string, error) {
+	var objects []struct {
+		Name string  `json:"name"`
+		ID   *string `json:"id"`
+	}
+	body := map[string]any{"prefix": prefix, "limit": listPageSize, "offset": 0}
+	if err := r.do(ctx, http.MethodPost, "/storage/v1/object/list/"+r.bucket, body, &objects); err != nil {
+		return nil, err
+	}
+
+	// Folders come back without an ID and can't be deleted directly
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if object.ID != nil {
+			names = append(names, object.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (r *SupabaseRemover) remove(ctx context.Context, paths []string) error {
+	return r.do(ctx, http.MethodDelete, "/storage/v1/object/"+r.bucket, map[string]any{"prefixes": paths}, nil)
+}
+
+func (r *SupabaseRemover) do(ctx context.Context, method, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, r.url+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+r.token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("storage request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage %s %s: unexpected status code %d", method, path, response.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestSupabaseRemoverRemoveURLs(t *testing.T) {
+	var removed []string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodDelete || r.URL.Path != "/storage/v1/object/vistara" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var body struct {
+			Prefixes []string `json:"prefixes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		removed = append(removed, body.Prefixes...)
+	}))
+	defer server.Close()
+
+	remover := NewRemover(server.URL+"/", "token", "vistara")
+
+	tests := []struct {
+		name         string
+		urls         []string
+		wantRemoved  []string
+		wantRequests int
+	}{
+		{
+			name: "bucket objects",
+			urls: []string{
+				server.URL + "/storage/v1/object/public/vistara/reviews/a.jpg",
+				server.URL + "/storage/v1/object/public/vistara/bookings/b.png",
+			},
+			wantRemoved:  []string{"reviews/a.jpg", "bookings/b.png"},
+			wantRequests: 1,
+		},
+		{
+			name: "other buckets and hosts are skipped",
+			urls: []string{
+				server.URL + "/storage/v1/object/public/other/a.jpg",
+				"https://cdn.example.com/a.jpg",
+				server.URL + "/storage/v1/object/public/vistara/",
+			},
+			wantRequests: 0,
+		},
+		{
+			name:         "no urls",
+			wantRequests: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed, requests = nil, 0
+
+			if err := remover.RemoveURLs(context.Background(), tt.urls...); err != nil {
+				t.Fatalf("RemoveURLs() error = %v", err)
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Fatalf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+
+	"github.com/vistara-studio/vistara-be/pkg/util"
+	"github.com/google/uuid"
+)
+
+// Uploader stores a file and returns its public URL
+type Uploader interface {
+	Upload(fileHeader *multipart.FileHeader) (string, error)
+}
+
+// UploadProfilePhoto stores an already validated photo under profiles/<userID>/ so every user gets their own prefix
+func UploadProfilePhoto(uploader Uploader, userID uuid.UUID, photo *multipart.FileHeader) (string, error) {
+	object := *photo
+	object.Filename = fmt.Sprintf("profiles/%s/%s", userID, util.SanitizeFileName(filepath.Base(photo.Filename)))
+
+	return uploader.Upload(&object)
+}
+
+// RemoveProfilePhotos deletes every photo the user has uploaded under their profiles/<userID>/ prefix
+func RemoveProfilePhotos(ctx context.Context, remover Remover, userID uuid.UUID) error {
+	return remover.RemovePrefix(ctx, fmt.Sprintf("profiles/%s", userID))
+}
+
+// ListingMediaPrefix is where the photos of a listing are stored, listingType is a catalogue listing type
+func ListingMediaPrefix(listingType string, listingID uuid.UUID) string {
+	return fmt.Sprintf("media/%s/%s", listingType, listingID)
+}
+
+// ListingPhotoUploadPath is where a listing photo is stored, stripped of its metadata, until its renditions exist
+func ListingPhotoUploadPath(listingType string, listingID, mediaID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s-upload.jpg", ListingMediaPrefix(listingType, listingID), mediaID)
+}
+
+// RemoveListingMedia deletes every photo stored for a listing
+func RemoveListingMedia(ctx context.Context, remover Remover, listingType string, listingID uuid.UUID) error {
+	return remover.RemovePrefix(ctx, ListingMediaPrefix(listingType, listingID))
+}
+
+// ListingPhotoVariantPath is where a processed rendition of a listing photo is stored, next to the uploaded file
+func ListingPhotoVariantPath(listingType string, listingID, mediaID uuid.UUID, variant string) string {
+	return fmt.Sprintf("%s/%s-%s.jpg", ListingMediaPrefix(listingType, listingID), mediaID, variant)
+}
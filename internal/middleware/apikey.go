@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey carries partner API keys
+const HeaderAPIKey = "X-API-Key"
+
+// AuthenticationOrAPIKey accepts a user access token like Authentication, or a partner API key granted scope.
+// A key acts for the account that owns it but carries no role, so role-gated routes stay closed to partners.
+func (m *Middleware) AuthenticationOrAPIKey(scope apikey.Scope) fiber.Handler {
+	authentication := m.Authentication()
+
+	return func(ctx *fiber.Ctx) error {
+		key := ctx.Get(HeaderAPIKey)
+		if key == "" {
+			return authentication(ctx)
+		}
+
+		principal, err := m.apiKeys.AuthenticateAPIKey(ctx.Context(), key)
+		if err != nil {
+			return err
+		}
+
+		if !principal.HasScope(scope) {
+			return apikey.ErrMissingScope
+		}
+
+		ctx.Locals("user_id", principal.UserID.String())
+		ctx.Locals("api_key_id", principal.KeyID.String())
+		ctx.Locals("is_premium", principal.IsPremium)
+		ctx.Locals("email_verified", principal.EmailVerified)
+		return ctx.Next()
+	}
+}
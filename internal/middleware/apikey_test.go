@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+)
+
+// stubAPIKeys authenticates only key, as principal
+type stubAPIKeys struct {
+	key       string
+	principal apikey.Principal
+}
+
+func (s stubAPIKeys) AuthenticateAPIKey(_ context.Context, key string) (apikey.Principal, error) {
+	if key != s.key {
+		return apikey.Principal{}, apikey.ErrInvalidAPIKey
+	}
+
+	return s.principal, nil
+}
+
+func TestAuthenticationOrAPIKey(t *testing.T) {
+	principal := apikey.Principal{
+		KeyID:         uuid.New(),
+		UserID:        uuid.New(),
+		Scopes:        pq.StringArray{string(apikey.ScopeReadCatalogue), string(apikey.ScopeReadBookings)},
+		EmailVerified: true,
+	}
+	m := New(nil, nil, stubAPIKeys{key: "vsk_valid", principal: principal}, nil, nil)
+
+	tests := []struct {
+		name    string
+		key     string
+		scope   apikey.Scope
+		wantErr error
+	}{
+		{name: "key granted the scope", key: "vsk_valid", scope: apikey.ScopeReadBookings},
+		{name: "key without the scope", key: "vsk_valid", scope: apikey.ScopeWriteBookings, wantErr: apikey.ErrMissingScope},
+		{name: "rejected key", key: "vsk_revoked", scope: apikey.ScopeReadBookings, wantErr: apikey.ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got error
+			locals := map[string]interface{}{}
+			app := fiber.New(fiber.Config{
+				ErrorHandler: func(ctx *fiber.Ctx, err error) error {
+					got = err
+					return ctx.SendStatus(fiber.StatusTeapot)
+				},
+			})
+			app.Get("/", m.AuthenticationOrAPIKey(tt.scope), func(ctx *fiber.Ctx) error {
+				for _, key := range []string{"user_id", "api_key_id", "is_premium", "email_verified", "role"} {
+					locals[key] = ctx.Locals(key)
+				}
+				return ctx.SendStatus(fiber.StatusNoContent)
+			})
+
+			request := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			request.Header.Set(HeaderAPIKey, tt.key)
+			if _, err := app.Test(request); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(got, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", got, tt.wantErr)
+				}
+				return
+			}
+			if got != nil {
+				t.Fatalf("error = %v, want none", got)
+			}
+
+			if locals["user_id"] != principal.UserID.String() || locals["api_key_id"] != principal.KeyID.String() {
+				t.Fatalf("locals = %v, want the key's owner and ID", locals)
+			}
+			if locals["email_verified"] != true || locals["is_premium"] != false {
+				t.Fatalf("locals = %v, want the owner's verification and premium state", locals)
+			}
+			if locals["role"] != nil {
+				t.Fatalf("role = %v, a key carries no role", locals["role"])
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@ package middleware
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/vistara-studio/vistara-be/pkg/cerr"
-	"github.com/vistara-studio/vistara-be/pkg/jwt"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,7 +14,8 @@ var (
 	ErrInvalidTokenType = cerr.New(fiber.StatusUnauthorized, "invalid type", errors.New("invalid token type"))
 )
 
-func Authentication(jwt *jwt.JWTStruct) fiber.Handler {
+// Authentication validates the bearer access token and rejects tokens whose session was revoked
+func (m *Middleware) Authentication() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		authorizationHeader := ctx.GetReqHeaders()["Authorization"]
 		if len(authorizationHeader) <= 0 {
@@ -26,13 +27,22 @@ func Authentication(jwt *jwt.JWTStruct) fiber.Handler {
 			return ErrInvalidTokenType
 		}
 
-		claims, err := jwt.Decode(token[1])
+		claims, err := m.jwt.Decode(token[1])
 		if err != nil {
 			return err
 		}
 
+		if err := m.sessions.ValidateSession(ctx.Context(), claims.SessionID); err != nil {
+			return err
+		}
+
 		ctx.Locals("user_id", claims.UserID)
-		ctx.Locals("is_premium", claims.IsPremium)
+		ctx.Locals("session_id", claims.SessionID)
+		// The flag is only cleared by the downgrade job, so an expired period counts as not premium
+		ctx.Locals("is_premium", claims.IsPremium && claims.PremiumExpiredAt.After(time.Now()))
+		ctx.Locals("email_verified", claims.EmailVerified)
+		ctx.Locals("role", claims.Role)
+		ctx.Locals("mfa", claims.MFA)
 		return ctx.Next()
 	}
 }
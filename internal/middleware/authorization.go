@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole only lets users with one of the given roles through, must run after Authentication.
+// Roles configured to need two-factor authentication also need a token from an MFA verified session.
+func (m *Middleware) RequireRole(roles ...user.Role) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		role, _ := ctx.Locals("role").(string)
+		for _, allowed := range roles {
+			if user.Role(role) != allowed {
+				continue
+			}
+
+			mfa, _ := ctx.Locals("mfa").(bool)
+			if !mfa && m.requiresMFA(allowed) {
+				return user.ErrMFARequired
+			}
+
+			return ctx.Next()
+		}
+
+		return user.ErrForbiddenRole
+	}
+}
+
+func (m *Middleware) requiresMFA(role user.Role) bool {
+	for _, required := range m.mfaRoles {
+		if required == role {
+			return true
+		}
+	}
+
+	return false
+}
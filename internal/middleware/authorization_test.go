@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+)
+
+// serve runs handler behind a stand-in for Authentication that sets the locals it would, and returns
+// the error the chain ended with
+func serve(t *testing.T, locals map[string]interface{}, handler fiber.Handler) error {
+	t.Helper()
+
+	var got error
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
+			got = err
+			return ctx.SendStatus(fiber.StatusTeapot)
+		},
+	})
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		for key, value := range locals {
+			ctx.Locals(key, value)
+		}
+		return ctx.Next()
+	}, handler, func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusNoContent)
+	})
+
+	response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil && response.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("status = %d without an error", response.StatusCode)
+	}
+
+	return got
+}
+
+func TestRequireRole(t *testing.T) {
+	m := New(nil, nil, nil, nil, []user.Role{user.RoleAdmin})
+
+	tests := []struct {
+		name    string
+		locals  map[string]interface{}
+		roles   []user.Role
+		wantErr error
+	}{
+		{name: "allowed role", locals: map[string]interface{}{"role": "merchant"}, roles: []user.Role{user.RoleMerchant, user.RoleAdmin}},
+		{name: "role not in the list", locals: map[string]interface{}{"role": "tourist"}, roles: []user.Role{user.RoleMerchant, user.RoleAdmin}, wantErr: user.ErrForbiddenRole},
+		{name: "no role", locals: map[string]interface{}{}, roles: []user.Role{user.RoleTourist}, wantErr: user.ErrForbiddenRole},
+		{name: "unknown role", locals: map[string]interface{}{"role": "superuser"}, roles: []user.Role{user.RoleAdmin}, wantErr: user.ErrForbiddenRole},
+		{name: "role needing MFA without it", locals: map[string]interface{}{"role": "admin"}, roles: []user.Role{user.RoleAdmin}, wantErr: user.ErrMFARequired},
+		{name: "role needing MFA with it", locals: map[string]interface{}{"role": "admin", "mfa": true}, roles: []user.Role{user.RoleAdmin}},
+		{name: "MFA only matters for the configured roles", locals: map[string]interface{}{"role": "merchant"}, roles: []user.Role{user.RoleMerchant}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := serve(t, tt.locals, m.RequireRole(tt.roles...))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("error = %v, want none", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
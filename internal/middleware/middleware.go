@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/apikey"
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/vistara-studio/vistara-be/pkg/jwt"
+	"github.com/vistara-studio/vistara-be/pkg/signature"
+)
+
+// SessionValidator checks that the session an access token was issued for is still active
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, sessionID string) error
+}
+
+// APIKeyAuthenticator resolves a partner API key to the account it acts for
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (apikey.Principal, error)
+}
+
+// Middleware holds the dependencies shared by the HTTP middlewares
+type Middleware struct {
+	jwt      *jwt.JWTStruct
+	sessions SessionValidator
+	apiKeys  APIKeyAuthenticator
+	services *signature.Verifier
+	mfaRoles []user.Role
+}
+
+// New creates a new Middleware instance, users with one of mfaRoles need a second factor for role-gated routes
+func New(jwt *jwt.JWTStruct, sessions SessionValidator, apiKeys APIKeyAuthenticator, services *signature.Verifier, mfaRoles []user.Role) *Middleware {
+	return &Middleware{
+		jwt:      jwt,
+		sessions: sessions,
+		apiKeys:  apiKeys,
+		services: services,
+		mfaRoles: mfaRoles,
+	}
+}
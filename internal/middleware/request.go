@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/audit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// requestIDPattern limits caller supplied request IDs to something safe to log and store
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestMeta gives every request an ID, echoed in X-Request-ID, and keeps the client IP for the audit log.
+// A well-formed X-Request-ID from the caller is reused so requests can be traced across services.
+func RequestMeta() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestID := ctx.Get(fiber.HeaderXRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set(fiber.HeaderXRequestID, requestID)
+		ctx.Locals(audit.RequestIDKey, requestID)
+		ctx.Locals(audit.ClientIPKey, ctx.IP())
+		return ctx.Next()
+	}
+}
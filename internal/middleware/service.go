@@ -1,30 +1,32 @@
 package middleware
 
 import (
+	"github.com/vistara-studio/vistara-be/pkg/signature"
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
 )
 
-// ServiceAuthentication middleware validates service-to-service requests
-// Only allows requests from vistara-ai service with proper X-Service header
-func ServiceAuthentication() fiber.Handler {
+// ServiceAuthentication validates HMAC signed service-to-service requests
+// The signature covers method, path, timestamp, nonce and body hash, replays are rejected
+func (m *Middleware) ServiceAuthentication() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Check for service authentication header
-		serviceHeader := c.Get("X-Service")
-		if serviceHeader == "" {
+		err := m.services.Verify(c.UserContext(), signature.Request{
+			Method:    c.Method(),
+			Path:      c.OriginalURL(),
+			KeyID:     c.Get(signature.HeaderKeyID),
+			Timestamp: c.Get(signature.HeaderTimestamp),
+			Nonce:     c.Get(signature.HeaderNonce),
+			Signature: c.Get(signature.HeaderSignature),
+			Body:      c.Body(),
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("path", c.Path()).Str("key_id", c.Get(signature.HeaderKeyID)).Msg("rejected service request")
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"success": false,
 				"message": "Service authentication required",
 			})
 		}
 
-		// Validate that the request is from vistara-ai
-		if serviceHeader != "vistara-ai" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"message": "Unauthorized service",
-			})
-		}
-
 		return c.Next()
 	}
 }
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireVerifiedEmail rejects users who haven't confirmed their email, must run after Authentication
+func (m *Middleware) RequireVerifiedEmail() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		verified, _ := ctx.Locals("email_verified").(bool)
+		if !verified {
+			return user.ErrEmailNotVerified
+		}
+
+		return ctx.Next()
+	}
+}
@@ -18,7 +18,9 @@ func (e *CustomError) Error() string {
 	return e.Message
 }
 
+// WithErr returns a copy carrying err as the cause, errors are package level sentinels shared between requests
 func (e *CustomError) WithErr(err error) *CustomError {
-	e.Err = err
-	return e
+	copied := *e
+	copied.Err = err
+	return &copied
 }
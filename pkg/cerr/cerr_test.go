@@ -0,0 +1,26 @@
+package cerr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithErrLeavesSentinelUntouched(t *testing.T) {
+	cause := errors.New("sentinel cause")
+	sentinel := New(502, "upstream failed", cause)
+
+	withErr := sentinel.WithErr(errors.New("request cause"))
+
+	if sentinel.Err != cause {
+		t.Fatalf("sentinel cause changed to %v", sentinel.Err)
+	}
+	if withErr == sentinel {
+		t.Fatal("WithErr returned the sentinel itself")
+	}
+	if withErr.Code != sentinel.Code || withErr.Message != sentinel.Message {
+		t.Fatalf("copy = %d %q, want %d %q", withErr.Code, withErr.Message, sentinel.Code, sentinel.Message)
+	}
+	if withErr.Err.Error() != "request cause" {
+		t.Fatalf("copy cause = %v", withErr.Err)
+	}
+}
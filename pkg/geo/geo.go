@@ -0,0 +1,45 @@
+package geo
+
+import "math"
+
+// EarthRadiusMetres is the mean earth radius used for haversine distances
+const EarthRadiusMetres = 6371000.0
+
+// metresPerDegree is the length of one degree of latitude, and of longitude at the equator
+const metresPerDegree = math.Pi * EarthRadiusMetres / 180
+
+// Box is a latitude/longitude range in degrees. WrapsLongitude is set when the range would cross
+// the antimeridian or a pole, every longitude has to be considered then.
+type Box struct {
+	MinLatitude    float64
+	MaxLatitude    float64
+	MinLongitude   float64
+	MaxLongitude   float64
+	WrapsLongitude bool
+}
+
+// BoundingBox returns a box containing every point within radius metres of the centre, it is meant
+// as a cheap indexed prefilter before the exact haversine distance
+func BoundingBox(latitude, longitude, radius float64) Box {
+	latitudeDelta := radius / metresPerDegree
+
+	box := Box{
+		MinLatitude: math.Max(latitude-latitudeDelta, -90),
+		MaxLatitude: math.Min(latitude+latitudeDelta, 90),
+	}
+
+	if box.MinLatitude == -90 || box.MaxLatitude == 90 {
+		box.MinLongitude, box.MaxLongitude, box.WrapsLongitude = -180, 180, true
+		return box
+	}
+
+	// Degrees of longitude shrink towards the poles, the edge nearest one keeps the box wide enough
+	farthestLatitude := math.Max(math.Abs(box.MinLatitude), math.Abs(box.MaxLatitude))
+	longitudeDelta := radius / (metresPerDegree * math.Cos(farthestLatitude*math.Pi/180))
+	box.MinLongitude, box.MaxLongitude = longitude-longitudeDelta, longitude+longitudeDelta
+	if box.MinLongitude < -180 || box.MaxLongitude > 180 {
+		box.MinLongitude, box.MaxLongitude, box.WrapsLongitude = -180, 180, true
+	}
+
+	return box
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// destination walks distance metres from a point along a bearing in degrees on the sphere
+func destination(latitude, longitude, bearing, distance float64) (float64, float64) {
+	lat1, lng1 := latitude*math.Pi/180, longitude*math.Pi/180
+	theta, delta := bearing*math.Pi/180, distance/EarthRadiusMetres
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	// Normalize the longitude back into [-180, 180)
+	lng := math.Mod(lng2*180/math.Pi+540, 360) - 180
+	return lat2 * 180 / math.Pi, lng
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		radius    float64
+		wantWraps bool
+	}{
+		{name: "Jakarta 5 km", latitude: -6.2, longitude: 106.816666, radius: 5000},
+		{name: "equator 50 km", latitude: 0, longitude: 0, radius: 50000},
+		{name: "high latitude", latitude: 69.65, longitude: 18.96, radius: 50000},
+		{name: "zero radius", latitude: -8.65, longitude: 115.2167, radius: 0},
+		{name: "crosses the antimeridian", latitude: -17.7, longitude: 179.99, radius: 5000, wantWraps: true},
+		{name: "crosses the antimeridian westwards", latitude: 51.9, longitude: -179.99, radius: 5000, wantWraps: true},
+		{name: "reaches the north pole", latitude: 89.99, longitude: 0, radius: 5000, wantWraps: true},
+		{name: "reaches the south pole", latitude: -89.99, longitude: 45, radius: 5000, wantWraps: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := BoundingBox(tt.latitude, tt.longitude, tt.radius)
+
+			if box.WrapsLongitude != tt.wantWraps {
+				t.Fatalf("WrapsLongitude = %v, want %v", box.WrapsLongitude, tt.wantWraps)
+			}
+			if box.MinLatitude < -90 || box.MaxLatitude > 90 || box.MinLatitude > box.MaxLatitude {
+				t.Errorf("latitude range [%f, %f] is out of bounds", box.MinLatitude, box.MaxLatitude)
+			}
+			if box.WrapsLongitude && (box.MinLongitude != -180 || box.MaxLongitude != 180) {
+				t.Errorf("wrapping box spans [%f, %f], want every longitude", box.MinLongitude, box.MaxLongitude)
+			}
+
+			// Every point on the circle has to be inside the box, or the prefilter drops real matches
+			for bearing := 0.0; bearing < 360; bearing += 5 {
+				lat, lng := destination(tt.latitude, tt.longitude, bearing, tt.radius)
+				const epsilon = 1e-9
+				if lat < box.MinLatitude-epsilon || lat > box.MaxLatitude+epsilon {
+					t.Errorf("bearing %.0f: latitude %f outside [%f, %f]", bearing, lat, box.MinLatitude, box.MaxLatitude)
+				}
+				if !box.WrapsLongitude && (lng < box.MinLongitude-epsilon || lng > box.MaxLongitude+epsilon) {
+					t.Errorf("bearing %.0f: longitude %f outside [%f, %f]", bearing, lng, box.MinLongitude, box.MaxLongitude)
+				}
+			}
+		})
+	}
+}
+
+func TestBoundingBoxIsTight(t *testing.T) {
+	// 5 km around Jakarta is about 0.045 degrees either way, a much wider box would defeat the index
+	box := BoundingBox(-6.2, 106.816666, 5000)
+
+	if delta := box.MaxLatitude - box.MinLatitude; math.Abs(delta-0.0899) > 0.001 {
+		t.Errorf("latitude span = %f, want about 0.0899", delta)
+	}
+	if delta := box.MaxLongitude - box.MinLongitude; math.Abs(delta-0.0905) > 0.001 {
+		t.Errorf("longitude span = %f, want about 0.0905", delta)
+	}
+}
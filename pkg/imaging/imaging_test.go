@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// pngHeader is the signature and IHDR chunk of an 8-bit RGBA png, enough for image.DecodeConfig
+func pngHeader(width, height uint32) []byte {
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, width)
+	chunk = binary.BigEndian.AppendUint32(chunk, height)
+	chunk = append(chunk, 8, 6, 0, 0, 0)
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(chunk)-4))
+	data = append(data, chunk...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+}
+
+func TestDecodeRejectsTooManyPixels(t *testing.T) {
+	// 50000x50000 would need 10GB once decoded, the header alone must be enough to refuse it
+	_, err := Decode(pngHeader(50000, 50000))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Decode() error = %v, want %v", err, ErrTooLarge)
+	}
+}
+
+func TestDecodeAndEncodeJPEG(t *testing.T) {
+	// A half transparent png, the transparent half has to come out white rather than black
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			src.Set(x, y, color.NRGBA{R: 200, G: 30, B: 30, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	encoded, err := EncodeJPEG(img, 80)
+	if err != nil {
+		t.Fatalf("EncodeJPEG() error = %v", err)
+	}
+
+	out, err := jpeg.Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("EncodeJPEG() wrote an unreadable jpeg: %v", err)
+	}
+	if out.Bounds().Dx() != 40 || out.Bounds().Dy() != 20 {
+		t.Fatalf("size = %v, want 40x20", out.Bounds().Size())
+	}
+	if r, g, b, _ := out.At(30, 10).RGBA(); r>>8 < 240 || g>>8 < 240 || b>>8 < 240 {
+		t.Errorf("transparent pixel = %d %d %d, want white", r>>8, g>>8, b>>8)
+	}
+	if r, g, _, _ := out.At(10, 10).RGBA(); r>>8 < 170 || g>>8 > 60 {
+		t.Errorf("opaque pixel lost its colour: r %d g %d", r>>8, g>>8)
+	}
+}
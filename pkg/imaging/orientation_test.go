@@ -0,0 +1,112 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// exifJPEG builds the start of a jpeg whose APP1 segment holds a single IFD0 entry with the given tag and value
+func exifJPEG(order binary.ByteOrder, tag, value uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tag)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], value)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xDA, 0, 2)
+}
+
+func TestExifOrientation(t *testing.T) {
+	// An APP0 segment before the EXIF one has to be skipped over
+	withJFIF := append([]byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 4, 'J', 'F'}, exifJPEG(binary.BigEndian, exifOrientationTag, 8)[2:]...)
+	truncated := exifJPEG(binary.LittleEndian, exifOrientationTag, 6)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "little endian", data: exifJPEG(binary.LittleEndian, exifOrientationTag, 6), want: 6},
+		{name: "big endian", data: exifJPEG(binary.BigEndian, exifOrientationTag, 3), want: 3},
+		{name: "after another segment", data: withJFIF, want: 8},
+		{name: "other tag only", data: exifJPEG(binary.LittleEndian, 0x010F, 6), want: 1},
+		{name: "value out of range", data: exifJPEG(binary.BigEndian, exifOrientationTag, 9), want: 1},
+		{name: "zero value", data: exifJPEG(binary.BigEndian, exifOrientationTag, 0), want: 1},
+		{name: "segment longer than the file", data: truncated[:20], want: 1},
+		{name: "no exif before the scan", data: []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}, want: 1},
+		{name: "not a jpeg", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "empty", data: nil, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.data); got != tt.want {
+				t.Errorf("exifOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image with a distinct colour per pixel, stored as the camera saw it:
+	//   a b c
+	//   d e f
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i, name := range "abcdef" {
+		src.Set(i%3, i/3, color.RGBA{R: uint8(name), A: 255})
+	}
+
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{orientation: 0, want: []string{"abc", "def"}},
+		{orientation: 1, want: []string{"abc", "def"}},
+		{orientation: 2, want: []string{"cba", "fed"}},
+		{orientation: 3, want: []string{"fed", "cba"}},
+		{orientation: 4, want: []string{"def", "abc"}},
+		{orientation: 5, want: []string{"ad", "be", "cf"}},
+		{orientation: 6, want: []string{"da", "eb", "fc"}},
+		{orientation: 7, want: []string{"fc", "eb", "da"}},
+		{orientation: 8, want: []string{"cf", "be", "ad"}},
+		{orientation: 9, want: []string{"abc", "def"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(rune('0'+tt.orientation)), func(t *testing.T) {
+			got := orient(src, tt.orientation)
+
+			rows := make([]string, got.Rect.Dy())
+			for y := range rows {
+				row := make([]byte, got.Rect.Dx())
+				for x := range row {
+					row[x] = got.RGBAAt(x, y).R
+				}
+				rows[y] = string(row)
+			}
+
+			if len(rows) != len(tt.want) {
+				t.Fatalf("orient(%d) = %q, want %q", tt.orientation, rows, tt.want)
+			}
+			for i := range rows {
+				if rows[i] != tt.want[i] {
+					t.Fatalf("orient(%d) = %q, want %q", tt.orientation, rows, tt.want)
+				}
+			}
+		})
+	}
+}
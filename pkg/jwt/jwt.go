@@ -1,7 +1,11 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -9,6 +13,7 @@ import (
 	"github.com/vistara-studio/vistara-be/pkg/cerr"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -19,38 +24,136 @@ var (
 	ErrSignToken        = cerr.New(fiber.StatusInternalServerError, "failed to sign jwt-token", nil)
 )
 
+// Supported signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
 type Claims struct {
 	UserID           string    `json:"user_id"`
+	SessionID        string    `json:"sid"`
+	EmailVerified    bool      `json:"email_verified"`
+	Role             string    `json:"role"`
+	MFA              bool      `json:"mfa"`
 	IsPremium        bool      `json:"is_premium"`
 	PremiumExpiredAt time.Time `json:"premium_expired_at"`
 	jwt.RegisteredClaims
 }
 
+// Config describes the signing key, the extra verification keys and the expected token audience
+type Config struct {
+	Algorithm     string
+	KeyID         string
+	PrivateKeyPEM []byte
+	// VerificationKeys maps key IDs to PEM public keys, typically keys retired from signing
+	VerificationKeys map[string][]byte
+	Issuer           string
+	Audience         string
+}
+
+// verificationKey is a public key together with the algorithm it is used with
+type verificationKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
 type JWTStruct struct {
-	secret []byte
+	method     jwt.SigningMethod
+	keyID      string
+	privateKey crypto.Signer
+	keys       map[string]verificationKey
+	issuer     string
+	audience   string
 }
 
-func New(secret string) *JWTStruct {
+func New(config Config) (*JWTStruct, error) {
+	if config.KeyID == "" {
+		return nil, errors.New("jwt key id is required")
+	}
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, errors.New("jwt issuer and audience are required")
+	}
+
+	privateKey, err := parsePrivateKey(config.PrivateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("jwt signing key: %w", err)
+	}
+
+	algorithm, err := algorithmFor(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("jwt signing key: %w", err)
+	}
+	if config.Algorithm != "" && config.Algorithm != algorithm {
+		return nil, fmt.Errorf("jwt signing key is a %s key but %s is configured", algorithm, config.Algorithm)
+	}
+
+	keys := map[string]verificationKey{
+		config.KeyID: {algorithm: algorithm, key: privateKey.Public()},
+	}
+	for kid, pemBytes := range config.VerificationKeys {
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("jwt key id %q is configured twice", kid)
+		}
+
+		publicKey, err := parsePublicKey(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt verification key %q: %w", kid, err)
+		}
+
+		keyAlgorithm, err := algorithmFor(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("jwt verification key %q: %w", kid, err)
+		}
+
+		keys[kid] = verificationKey{algorithm: keyAlgorithm, key: publicKey}
+	}
+
 	return &JWTStruct{
-		secret: []byte(secret),
+		method:     jwt.GetSigningMethod(algorithm),
+		keyID:      config.KeyID,
+		privateKey: privateKey,
+		keys:       keys,
+		issuer:     config.Issuer,
+		audience:   config.Audience,
+	}, nil
+}
+
+// algorithmFor returns the signing algorithm used with the public key type
+func algorithmFor(key crypto.PublicKey) (string, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T, use RSA or Ed25519", key)
 	}
 }
 
-func (j *JWTStruct) Encode(data *user.Table) (string, error) {
+// Encode issues an access token for the user, bound to the session family it was issued for.
+// mfa tells whether that session passed a second factor.
+func (j *JWTStruct) Encode(data *user.Table, sessionID uuid.UUID, mfa bool) (string, error) {
 	claims := &Claims{
 		UserID:           data.ID.String(),
+		SessionID:        sessionID.String(),
+		EmailVerified:    data.VerifiedAt != nil,
+		Role:             string(data.Role),
+		MFA:              mfa,
 		IsPremium:        data.IsPremium,
 		PremiumExpiredAt: data.ExpiredAt,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "nusa",
+			Issuer:    j.issuer,
 			Subject:   "authentication",
+			Audience:  jwt.ClaimStrings{j.audience},
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(j.secret)
+	token := jwt.NewWithClaims(j.method, claims)
+	token.Header["kid"] = j.keyID
+	signedToken, err := token.SignedString(j.privateKey)
 	if err != nil {
 		return "", ErrSignToken.WithErr(err)
 	}
@@ -58,10 +161,14 @@ func (j *JWTStruct) Encode(data *user.Table) (string, error) {
 	return signedToken, nil
 }
 
+// Decode verifies the token with the key named by its kid and checks algorithm, issuer, audience and expiry
 func (j *JWTStruct) Decode(jwtToken string) (*Claims, error) {
-	decoded, err := jwt.ParseWithClaims(jwtToken, &Claims{}, func(_ *jwt.Token) (interface{}, error) {
-		return j.secret, nil
-	})
+	decoded, err := jwt.ParseWithClaims(jwtToken, &Claims{}, j.keyFunc,
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(j.audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -82,3 +189,18 @@ func (j *JWTStruct) Decode(jwtToken string) (*Claims, error) {
 
 	return claims, err
 }
+
+// keyFunc picks the verification key by kid, the token algorithm has to match that key
+func (j *JWTStruct) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.algorithm {
+		return nil, fmt.Errorf("key %q is not used with %s", kid, token.Method.Alg())
+	}
+
+	return key.key, nil
+}
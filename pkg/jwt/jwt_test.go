@@ -0,0 +1,288 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vistara-studio/vistara-be/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	testIssuer   = "vistara-be"
+	testAudience = "vistara"
+)
+
+// rsaKey is generated once, RSA key generation is slow
+var rsaKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func privatePEM(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicPEM(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func newJWT(t *testing.T, kid string, key crypto.Signer, verificationKeys map[string][]byte) *JWTStruct {
+	t.Helper()
+
+	j, err := New(Config{
+		KeyID:            kid,
+		PrivateKeyPEM:    privatePEM(t, key),
+		VerificationKeys: verificationKeys,
+		Issuer:           testIssuer,
+		Audience:         testAudience,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+func testUser() *user.Table {
+	now := time.Now()
+	return &user.Table{ID: uuid.New(), Role: user.RoleMerchant, VerifiedAt: &now}
+}
+
+// sign issues a token with arbitrary claims and header using key, for tokens Encode would never produce
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() *Claims {
+	return &Claims{
+		UserID: uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+	}{
+		{name: "EdDSA", key: newEd25519Key(t), algorithm: AlgorithmEdDSA},
+		{name: "RS256", key: rsaKey, algorithm: AlgorithmRS256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := newJWT(t, "current", tt.key, nil)
+			account := testUser()
+			sessionID := uuid.New()
+
+			token, err := j.Encode(account, sessionID, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Method.Alg() != tt.algorithm || parsed.Header["kid"] != "current" {
+				t.Errorf("header alg %s kid %v, want %s kid current", parsed.Method.Alg(), parsed.Header["kid"], tt.algorithm)
+			}
+
+			claims, err := j.Decode(token)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if claims.UserID != account.ID.String() || claims.SessionID != sessionID.String() ||
+				claims.Role != string(user.RoleMerchant) || !claims.MFA || !claims.EmailVerified {
+				t.Errorf("claims = %+v, want the encoded user, session, role, mfa and verified email", claims)
+			}
+		})
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	edKey := newEd25519Key(t)
+	// Signs with the Ed25519 key and also verifies with an RSA key under another kid
+	j := newJWT(t, "ed", edKey, map[string][]byte{"rsa": publicPEM(t, rsaKey.Public())})
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "someone-else"
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"another-app"}
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	rsaPublicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "EdDSA token naming the RSA key", token: sign(t, jwt.SigningMethodEdDSA, edKey, "rsa", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "RS256 token naming the Ed25519 key", token: sign(t, jwt.SigningMethodRS256, rsaKey, "ed", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "HS256 token keyed with the public key", token: sign(t, jwt.SigningMethodHS256, rsaPublicDER, "rsa", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "unknown kid", token: sign(t, jwt.SigningMethodEdDSA, edKey, "missing", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "no kid", token: sign(t, jwt.SigningMethodEdDSA, edKey, "", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "signed by another key", token: sign(t, jwt.SigningMethodEdDSA, newEd25519Key(t), "ed", validClaims()), wantErr: ErrInvalidSignature},
+		{name: "wrong issuer", token: sign(t, jwt.SigningMethodEdDSA, edKey, "ed", wrongIssuer), wantErr: ErrInvalidSignature},
+		{name: "wrong audience", token: sign(t, jwt.SigningMethodEdDSA, edKey, "ed", wrongAudience), wantErr: ErrInvalidSignature},
+		{name: "expired", token: sign(t, jwt.SigningMethodEdDSA, edKey, "ed", expired), wantErr: ErrTokenExpired},
+		{name: "no expiry", token: sign(t, jwt.SigningMethodEdDSA, edKey, "ed", noExpiry), wantErr: ErrInvalidSignature},
+		{name: "not a token", token: "not.a.token", wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := j.Decode(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The same claims signed correctly are accepted, so the cases above fail for the reason they name
+	if _, err := j.Decode(sign(t, jwt.SigningMethodEdDSA, edKey, "ed", validClaims())); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+	if _, err := j.Decode(sign(t, jwt.SigningMethodRS256, rsaKey, "rsa", validClaims())); err != nil {
+		t.Errorf("valid token of the verification key rejected: %v", err)
+	}
+}
+
+func TestRetiredKeyOnlyVerifies(t *testing.T) {
+	retiredKey := newEd25519Key(t)
+	retired := newJWT(t, "2024", retiredKey, nil)
+	current := newJWT(t, "2025", newEd25519Key(t), map[string][]byte{"2024": publicPEM(t, retiredKey.Public())})
+
+	oldToken, err := retired.Encode(testUser(), uuid.New(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.Decode(oldToken); err != nil {
+		t.Errorf("token of the retired key rejected: %v", err)
+	}
+
+	newToken, err := current.Encode(testUser(), uuid.New(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "2025" {
+		t.Errorf("signed with kid %v, want the current key 2025", parsed.Header["kid"])
+	}
+	if _, err := retired.Decode(newToken); err == nil {
+		t.Error("token of the current key verified with the retired key alone")
+	}
+}
+
+func TestNewRejectsMisconfiguration(t *testing.T) {
+	edKey := newEd25519Key(t)
+
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "algorithm of another key type", config: Config{Algorithm: AlgorithmRS256, KeyID: "ed", PrivateKeyPEM: privatePEM(t, edKey), Issuer: testIssuer, Audience: testAudience}},
+		{name: "no key id", config: Config{PrivateKeyPEM: privatePEM(t, edKey), Issuer: testIssuer, Audience: testAudience}},
+		{name: "no audience", config: Config{KeyID: "ed", PrivateKeyPEM: privatePEM(t, edKey), Issuer: testIssuer}},
+		{name: "verification key reusing the signing kid", config: Config{KeyID: "ed", PrivateKeyPEM: privatePEM(t, edKey), VerificationKeys: map[string][]byte{"ed": publicPEM(t, edKey.Public())}, Issuer: testIssuer, Audience: testAudience}},
+		{name: "not a PEM key", config: Config{KeyID: "ed", PrivateKeyPEM: []byte("secret"), Issuer: testIssuer, Audience: testAudience}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.config); err == nil {
+				t.Error("New() accepted the configuration")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	edKey := newEd25519Key(t)
+	j := newJWT(t, "ed", edKey, map[string][]byte{"rsa": publicPEM(t, rsaKey.Public())})
+
+	set := j.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kid != "ed" || set.Keys[1].Kid != "rsa" {
+		t.Fatalf("keys = %+v, want ed and rsa in kid order", set.Keys)
+	}
+
+	ed := set.Keys[0]
+	x, err := base64.RawURLEncoding.DecodeString(ed.X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != AlgorithmEdDSA || ed.Use != "sig" || !edKey.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		t.Errorf("Ed25519 JWK = %+v does not match the public key", ed)
+	}
+
+	rsaJWK := set.Keys[1]
+	n, err := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(rsaJWK.E)
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	if rsaJWK.Kty != "RSA" || rsaJWK.Alg != AlgorithmRS256 || rsaJWK.Use != "sig" || !rsaKey.PublicKey.Equal(published) {
+		t.Errorf("RSA JWK = %+v does not match the public key", rsaJWK)
+	}
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadPEM returns the inline PEM when set, otherwise the content of file.
+// Inline values may use literal \n so they fit on one line of an env file.
+func LoadPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(strings.ReplaceAll(inline, `\n`, "\n")), nil
+	}
+
+	if file == "" {
+		return nil, errors.New("no key configured")
+	}
+
+	return os.ReadFile(file)
+}
+
+// LoadKeyFiles reads a comma separated list of kid:path pairs into PEM contents keyed by kid
+func LoadKeyFiles(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kid, path, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("jwt key file %q must be in kid:path form", pair)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key file %q: %w", kid, err)
+		}
+
+		keys[kid] = content
+	}
+
+	return keys, nil
+}
+
+// parsePrivateKey reads a PKCS#8 or PKCS#1 private key
+func parsePrivateKey(pemBytes []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("expected a PKCS#8 or PKCS#1 private key")
+}
+
+// parsePublicKey reads a PKIX public key
+func parsePublicKey(pemBytes []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,79 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+var ErrMalformedCursor = errors.New("cursor is malformed")
+
+// Cursor marks the last row of a page for keyset pagination. Value is the sort column of that row
+// as text and ID breaks ties, Sort and Order pin the cursor to the ordering it was issued for.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"i"`
+}
+
+// Encode turns the cursor into an opaque URL safe token
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode parses a token produced by Encode
+func Decode(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrMalformedCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return Cursor{}, ErrMalformedCursor
+	}
+
+	return cursor, nil
+}
+
+// Page is the envelope every paginated list is returned in, NextCursor is empty on the last page
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      int    `json:"limit"`
+}
+
+// Limit bounds a requested page size, zero or less falls back to the default
+func Limit(requested, defaultLimit, maxLimit int) int {
+	if requested <= 0 {
+		return defaultLimit
+	}
+	if requested > maxLimit {
+		return maxLimit
+	}
+	return requested
+}
+
+// NewPage builds the envelope from a result fetched with limit+1 rows, the extra row only signals
+// that another page exists. cursor is called with the last item kept on the page.
+func NewPage[T any](items []T, limit int, cursor func(T) Cursor) Page[T] {
+	page := Page[T]{Items: items, Limit: limit}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		page.NextCursor = cursor(page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	return page
+}
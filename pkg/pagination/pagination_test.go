@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{
+			name:   "timestamp value",
+			cursor: Cursor{Sort: "created_at", Order: OrderDesc, Value: "2025-03-01T08:30:00.123456Z", ID: "0195a1b2-0000-7000-8000-000000000001"},
+		},
+		{
+			name:   "name with characters that need escaping",
+			cursor: Cursor{Sort: "name", Order: OrderAsc, Value: `Warung "Bu Sri" & Co/?+=`, ID: "0195a1b2-0000-7000-8000-000000000002"},
+		},
+		{
+			name:   "empty value",
+			cursor: Cursor{Sort: "name", Order: OrderAsc, ID: "0195a1b2-0000-7000-8000-000000000003"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.cursor.Encode()
+			if strings.ContainsAny(token, "+/=") {
+				t.Errorf("token %q is not URL safe", token)
+			}
+
+			got, err := Decode(token)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got != tt.cursor {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "padded base64", token: base64.URLEncoding.EncodeToString([]byte(`{"s":"name","i":"x"}`))},
+		{name: "not JSON", token: encode("name|x")},
+		{name: "wrong JSON type", token: encode(`["name","asc"]`)},
+		{name: "missing ID", token: encode(`{"s":"name","o":"asc","v":"a"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.token); !errors.Is(err, ErrMalformedCursor) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrMalformedCursor)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+	}{
+		{name: "zero falls back to the default", requested: 0, want: 20},
+		{name: "negative falls back to the default", requested: -5, want: 20},
+		{name: "within bounds", requested: 50, want: 50},
+		{name: "at the maximum", requested: 100, want: 100},
+		{name: "above the maximum", requested: 1000, want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Limit(tt.requested, 20, 100); got != tt.want {
+				t.Errorf("Limit(%d) = %d, want %d", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	cursor := func(item string) Cursor {
+		return Cursor{Sort: "name", Order: OrderAsc, Value: item, ID: item}
+	}
+
+	tests := []struct {
+		name      string
+		items     []string
+		limit     int
+		wantItems []string
+		wantMore  bool
+		wantAfter string
+	}{
+		{name: "nil result", items: nil, limit: 2, wantItems: []string{}},
+		{name: "short page", items: []string{"a"}, limit: 2, wantItems: []string{"a"}},
+		{name: "exactly full page", items: []string{"a", "b"}, limit: 2, wantItems: []string{"a", "b"}},
+		{name: "extra row signals another page", items: []string{"a", "b", "c"}, limit: 2, wantItems: []string{"a", "b"}, wantMore: true, wantAfter: "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(tt.items, tt.limit, cursor)
+
+			if page.Items == nil {
+				t.Fatal("Items is nil, want an empty slice")
+			}
+			if strings.Join(page.Items, ",") != strings.Join(tt.wantItems, ",") {
+				t.Errorf("Items = %v, want %v", page.Items, tt.wantItems)
+			}
+			if page.HasMore != tt.wantMore {
+				t.Errorf("HasMore = %v, want %v", page.HasMore, tt.wantMore)
+			}
+			if page.Limit != tt.limit {
+				t.Errorf("Limit = %d, want %d", page.Limit, tt.limit)
+			}
+
+			if !tt.wantMore {
+				if page.NextCursor != "" {
+					t.Errorf("NextCursor = %q, want none on the last page", page.NextCursor)
+				}
+				return
+			}
+
+			next, err := Decode(page.NextCursor)
+			if err != nil {
+				t.Fatalf("Decode(NextCursor) error = %v", err)
+			}
+			if next.ID != tt.wantAfter {
+				t.Errorf("NextCursor points after %q, want %q", next.ID, tt.wantAfter)
+			}
+		})
+	}
+}
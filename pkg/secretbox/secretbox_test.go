@@ -0,0 +1,133 @@
+package secretbox
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newKey(t *testing.T, size int) string {
+	t.Helper()
+
+	key := make([]byte, size)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newBox(t *testing.T) *Box {
+	t.Helper()
+
+	box, err := New(newKey(t, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return box
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	box := newBox(t)
+	plaintext := []byte("JBSWY3DPEHPK3PXP")
+
+	sealed, err := box.Seal(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains([]byte(sealed), plaintext) {
+		t.Error("sealed value contains the plaintext")
+	}
+
+	opened, err := box.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open() = %q, want %q", opened, plaintext)
+	}
+
+	// A fresh nonce per seal, the same secret never gives the same value twice
+	again, err := box.Seal(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == sealed {
+		t.Error("sealing the same plaintext twice gave the same value")
+	}
+}
+
+func TestOpenRejectsTamperedValues(t *testing.T) {
+	box := newBox(t)
+	sealed, err := box.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := func(index int) string {
+		tampered := bytes.Clone(raw)
+		tampered[index] ^= 0x01
+		return base64.StdEncoding.EncodeToString(tampered)
+	}
+
+	tests := []struct {
+		name      string
+		sealed    string
+		malformed bool
+	}{
+		{name: "flipped ciphertext byte", sealed: flipped(len(raw) - 1)},
+		{name: "flipped nonce byte", sealed: flipped(0)},
+		{name: "truncated tag", sealed: base64.StdEncoding.EncodeToString(raw[:len(raw)-1])},
+		{name: "shorter than a nonce", sealed: base64.StdEncoding.EncodeToString(raw[:4]), malformed: true},
+		{name: "not base64", sealed: "%%%", malformed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, err := box.Open(tt.sealed)
+			if err == nil {
+				t.Fatalf("Open() = %q, want an error", opened)
+			}
+			if tt.malformed && !errors.Is(err, ErrMalformed) {
+				t.Errorf("Open() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
+
+func TestOpenWithTheWrongKey(t *testing.T) {
+	sealed, err := newBox(t).Seal([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newBox(t).Open(sealed); err == nil {
+		t.Error("a value sealed with one key opened with another")
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "16 bytes", key: newKey(t, 16)},
+		{name: "31 bytes", key: newKey(t, 31)},
+		{name: "33 bytes", key: newKey(t, 33)},
+		{name: "empty", key: ""},
+		{name: "not base64", key: "not a key!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.key); err == nil {
+				t.Error("New() accepted the key")
+			}
+		})
+	}
+}
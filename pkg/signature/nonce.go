@@ -0,0 +1,13 @@
+package signature
+
+import (
+	"context"
+	"time"
+)
+
+// NonceStore remembers nonces until they expire so replayed requests can be rejected,
+// it has to be shared by every instance that verifies requests
+type NonceStore interface {
+	// Claim records the nonce for ttl and reports false when it is still remembered from an earlier request
+	Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
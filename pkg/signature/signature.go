@@ -0,0 +1,210 @@
+package signature
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers carrying the request signature
+const (
+	HeaderKeyID     = "X-Service-Key-Id"
+	HeaderTimestamp = "X-Service-Timestamp"
+	HeaderNonce     = "X-Service-Nonce"
+	HeaderSignature = "X-Service-Signature"
+)
+
+// maxNonceLength bounds the nonces kept in the nonce store
+const maxNonceLength = 128
+
+var (
+	ErrMissingSignature = errors.New("missing service signature headers")
+	ErrUnknownKey       = errors.New("unknown service key")
+	ErrInvalidTimestamp = errors.New("invalid service timestamp")
+	ErrExpiredTimestamp = errors.New("service timestamp outside the allowed window")
+	ErrInvalidNonce     = errors.New("invalid service nonce")
+	ErrReplayedNonce    = errors.New("service nonce already used")
+	ErrInvalidSignature = errors.New("invalid service signature")
+)
+
+// Key is a shared secret identified by its key ID
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// ParseKeys reads a comma separated list of kid:secret pairs, the first pair is the default signing key
+func ParseKeys(raw string) ([]Key, error) {
+	var keys []Key
+	seen := make(map[string]bool)
+
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("service key %q must be in kid:secret form", pair)
+		}
+
+		if seen[id] {
+			return nil, fmt.Errorf("service key %q is configured twice", id)
+		}
+		seen[id] = true
+
+		keys = append(keys, Key{ID: id, Secret: []byte(secret)})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("at least one service key is required")
+	}
+
+	return keys, nil
+}
+
+// Payload builds the canonical string that is signed for a request
+func Payload(method, path, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// compute returns the hex encoded HMAC-SHA256 of the payload
+func compute(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Signer signs outgoing service requests with a single key
+type Signer struct {
+	key Key
+}
+
+// NewSigner creates a signer for the given key
+func NewSigner(key Key) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign sets the signature headers on the request, body must be the exact bytes being sent
+func (s *Signer) Sign(req *http.Request, body []byte) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonceHex := hex.EncodeToString(nonce)
+	payload := Payload(req.Method, req.URL.RequestURI(), timestamp, nonceHex, body)
+
+	req.Header.Set(HeaderKeyID, s.key.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonceHex)
+	req.Header.Set(HeaderSignature, compute(s.key.Secret, payload))
+	return nil
+}
+
+// Request holds the parts of an incoming request covered by the signature
+type Request struct {
+	Method    string
+	Path      string
+	KeyID     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Body      []byte
+}
+
+// Verifier checks incoming service signatures against every active key
+type Verifier struct {
+	keys   map[string][]byte
+	window time.Duration
+	nonces NonceStore
+}
+
+// NewVerifier creates a verifier accepting any of the keys, timestamps must be within window of now
+func NewVerifier(keys []Key, window time.Duration, nonces NonceStore) *Verifier {
+	byID := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key.Secret
+	}
+
+	return &Verifier{
+		keys:   byID,
+		window: window,
+		nonces: nonces,
+	}
+}
+
+// Verify validates the signature, the timestamp window and that the nonce was not seen before
+func (v *Verifier) Verify(ctx context.Context, req Request) error {
+	if req.KeyID == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return ErrMissingSignature
+	}
+
+	secret, ok := v.keys[req.KeyID]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	unix, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	skew := time.Since(time.Unix(unix, 0))
+	if skew > v.window || skew < -v.window {
+		return ErrExpiredTimestamp
+	}
+
+	if len(req.Nonce) > maxNonceLength {
+		return ErrInvalidNonce
+	}
+
+	expected := compute(secret, Payload(req.Method, req.Path, req.Timestamp, req.Nonce, req.Body))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(req.Signature))) {
+		return ErrInvalidSignature
+	}
+
+	// Only remember nonces of valid requests so forged requests can't fill the store,
+	// a timestamp stays acceptable for window on either side of now
+	claimed, err := v.nonces.Claim(ctx, req.KeyID+":"+req.Nonce, 2*v.window)
+	if err != nil {
+		return fmt.Errorf("failed to record service nonce: %w", err)
+	}
+	if !claimed {
+		return ErrReplayedNonce
+	}
+
+	return nil
+}
+
+// SigningKey picks the key with the given ID, or the first key when id is empty
+func SigningKey(keys []Key, id string) (Key, error) {
+	if id == "" {
+		return keys[0], nil
+	}
+
+	for _, key := range keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+
+	return Key{}, fmt.Errorf("signing key %q is not among the service keys", id)
+}
@@ -0,0 +1,154 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// memoryNonces stands in for the Postgres nonce store
+type memoryNonces struct {
+	seen map[string]bool
+	err  error
+}
+
+func (m *memoryNonces) Claim(_ context.Context, nonce string, _ time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.seen[nonce] {
+		return false, nil
+	}
+	m.seen[nonce] = true
+	return true, nil
+}
+
+func signedRequest(t *testing.T, key Key, body string) Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, "http://vistara.test/api/v1/ai/callback?x=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSigner(key).Sign(req, []byte(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	return Request{
+		Method:    req.Method,
+		Path:      req.URL.RequestURI(),
+		KeyID:     req.Header.Get(HeaderKeyID),
+		Timestamp: req.Header.Get(HeaderTimestamp),
+		Nonce:     req.Header.Get(HeaderNonce),
+		Signature: req.Header.Get(HeaderSignature),
+		Body:      []byte(body),
+	}
+}
+
+func TestVerifierVerify(t *testing.T) {
+	current := Key{ID: "current", Secret: []byte("current-secret")}
+	retired := Key{ID: "retired", Secret: []byte("retired-secret")}
+	unknown := Key{ID: "unknown", Secret: []byte("unknown-secret")}
+
+	tests := []struct {
+		name    string
+		request func(t *testing.T) Request
+		want    error
+	}{
+		{
+			name:    "current key",
+			request: func(t *testing.T) Request { return signedRequest(t, current, `{"a":1}`) },
+		},
+		{
+			name:    "other accepted key",
+			request: func(t *testing.T) Request { return signedRequest(t, retired, `{"a":1}`) },
+		},
+		{
+			name:    "unknown key",
+			request: func(t *testing.T) Request { return signedRequest(t, unknown, `{"a":1}`) },
+			want:    ErrUnknownKey,
+		},
+		{
+			name: "missing nonce",
+			request: func(t *testing.T) Request {
+				req := signedRequest(t, current, `{"a":1}`)
+				req.Nonce = ""
+				return req
+			},
+			want: ErrMissingSignature,
+		},
+		{
+			name: "tampered body",
+			request: func(t *testing.T) Request {
+				req := signedRequest(t, current, `{"a":1}`)
+				req.Body = []byte(`{"a":2}`)
+				return req
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name: "tampered path",
+			request: func(t *testing.T) Request {
+				req := signedRequest(t, current, `{"a":1}`)
+				req.Path = "/api/v1/ai/callback?x=2"
+				return req
+			},
+			want: ErrInvalidSignature,
+		},
+		{
+			name: "timestamp outside the window",
+			request: func(t *testing.T) Request {
+				req := signedRequest(t, current, `{"a":1}`)
+				req.Timestamp = strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+				return req
+			},
+			want: ErrExpiredTimestamp,
+		},
+		{
+			name: "malformed timestamp",
+			request: func(t *testing.T) Request {
+				req := signedRequest(t, current, `{"a":1}`)
+				req.Timestamp = "yesterday"
+				return req
+			},
+			want: ErrInvalidTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewVerifier([]Key{current, retired}, 5*time.Minute, &memoryNonces{seen: map[string]bool{}})
+
+			err := verifier.Verify(context.Background(), tt.request(t))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierRejectsReplays(t *testing.T) {
+	key := Key{ID: "current", Secret: []byte("current-secret")}
+	verifier := NewVerifier([]Key{key}, 5*time.Minute, &memoryNonces{seen: map[string]bool{}})
+	req := signedRequest(t, key, `{"a":1}`)
+
+	if err := verifier.Verify(context.Background(), req); err != nil {
+		t.Fatalf("first Verify() error = %v", err)
+	}
+	if err := verifier.Verify(context.Background(), req); !errors.Is(err, ErrReplayedNonce) {
+		t.Fatalf("replayed Verify() error = %v, want %v", err, ErrReplayedNonce)
+	}
+}
+
+func TestVerifierNonceStoreFailure(t *testing.T) {
+	key := Key{ID: "current", Secret: []byte("current-secret")}
+	storeErr := errors.New("connection refused")
+	verifier := NewVerifier([]Key{key}, 5*time.Minute, &memoryNonces{err: storeErr})
+
+	if err := verifier.Verify(context.Background(), signedRequest(t, key, "")); !errors.Is(err, storeErr) {
+		t.Fatalf("Verify() error = %v, want %v", err, storeErr)
+	}
+}
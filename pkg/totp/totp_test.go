@@ -0,0 +1,118 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 appendix B test vectors, base32 encoded
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes, a 6 digit code is the same value modulo 10^6
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			got, err := Code(rfcSecret, Counter(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Code() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := Code(" "+strings.ToLower(rfcSecret)+" ", Counter(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code() = %s, %v, want 287082", got, err)
+	}
+}
+
+func TestCodeRejectsInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() accepted a secret that isn't base32")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	current := Counter(at)
+
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, current+step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name        string
+		code        string
+		skew        int
+		wantOK      bool
+		wantCounter int64
+	}{
+		{name: "current step", code: codeAt(0), skew: 1, wantOK: true, wantCounter: current},
+		{name: "previous step inside the skew", code: codeAt(-1), skew: 1, wantOK: true, wantCounter: current - 1},
+		{name: "next step inside the skew", code: codeAt(1), skew: 1, wantOK: true, wantCounter: current + 1},
+		{name: "two steps back outside the skew", code: codeAt(-2), skew: 1},
+		{name: "two steps ahead outside the skew", code: codeAt(2), skew: 1},
+		{name: "previous step without skew", code: codeAt(-1), skew: 0},
+		{name: "surrounding spaces", code: " " + codeAt(0) + " ", skew: 0, wantOK: true, wantCounter: current},
+		{name: "wrong code", code: "000000", skew: 1},
+		{name: "too short", code: codeAt(0)[:5], skew: 1},
+		{name: "too long", code: codeAt(0) + "0", skew: 1},
+		{name: "empty", code: "", skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok := Validate(rfcSecret, tt.code, at, tt.skew)
+			if ok != tt.wantOK {
+				t.Fatalf("Validate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			// The matched step is what a caller stores to refuse the same code again
+			if ok && counter != tt.wantCounter {
+				t.Errorf("Validate() counter = %d, want %d", counter, tt.wantCounter)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == second {
+		t.Error("two generated secrets are equal")
+	}
+	if key, err := encoding.DecodeString(first); err != nil || len(key) != secretSize {
+		t.Errorf("secret decodes to %d bytes, %v, want %d", len(key), err, secretSize)
+	}
+	if _, err := Code(first, 1); err != nil {
+		t.Errorf("generated secret can't be used: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+#!/bin/bash
+
+# Sends an HMAC signed service-to-service request, the way vistara-ai calls this backend
+# Usage: scripts/service-curl.sh METHOD PATH [JSON_BODY]
+
+set -e
+
+METHOD=${1:?method required}
+REQUEST_PATH=${2:?path required}
+BODY=${3:-}
+BASE_URL=${BASE_URL:-http://localhost:8080}
+
+if [ -f .env ] && [ -z "$SERVICE_AUTH_KEYS" ]; then
+    SERVICE_AUTH_KEYS=$(grep -E '^SERVICE_AUTH_KEYS=' .env | cut -d= -f2-)
+    SERVICE_AUTH_SIGNING_KEY_ID=${SERVICE_AUTH_SIGNING_KEY_ID:-$(grep -E '^SERVICE_AUTH_SIGNING_KEY_ID=' .env | cut -d= -f2-)}
+fi
+
+# Sign with the configured key, or the first key in the list
+PAIR=$(echo "$SERVICE_AUTH_KEYS" | tr ',' '\n' | grep -E "^${SERVICE_AUTH_SIGNING_KEY_ID:-[^:]*}:" | head -n1)
+KEY_ID=${PAIR%%:*}
+SECRET=${PAIR#*:}
+
+TIMESTAMP=$(date +%s)
+NONCE=$(openssl rand -hex 16)
+BODY_HASH=$(printf '%s' "$BODY" | openssl dgst -sha256 -hex | awk '{print $NF}')
+SIGNATURE=$(printf '%s\n%s\n%s\n%s\n%s' "$METHOD" "$REQUEST_PATH" "$TIMESTAMP" "$NONCE" "$BODY_HASH" \
+    | openssl dgst -sha256 -hmac "$SECRET" -hex | awk '{print $NF}')
+
+curl -s -X "$METHOD" "$BASE_URL$REQUEST_PATH" \
+    -H "Content-Type: application/json" \
+    -H "X-Service-Key-Id: $KEY_ID" \
+    -H "X-Service-Timestamp: $TIMESTAMP" \
+    -H "X-Service-Nonce: $NONCE" \
+    -H "X-Service-Signature: $SIGNATURE" \
+    ${BODY:+-d "$BODY"}